@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
@@ -37,6 +38,11 @@ var (
 	ctxLsVersion            = &contextKey{"language server version"}
 	ctxProgressToken        = &contextKey{"progress token"}
 	ctxExperimentalFeatures = &contextKey{"experimental features"}
+	ctxFormatter            = &contextKey{"formatter"}
+	ctxRenameOptions        = &contextKey{"rename options"}
+	ctxOrganizeAttributes   = &contextKey{"organize attributes options"}
+	ctxSemanticTokensCache  = &contextKey{"semantic tokens cache"}
+	ctxCompletionCache      = &contextKey{"completion cache"}
 )
 
 func missingContextErr(ctxKey *contextKey) *MissingContextErr {
@@ -282,3 +288,93 @@ func ExperimentalFeatures(ctx context.Context) (settings.ExperimentalFeatures, e
 	}
 	return *expFeatures, nil
 }
+
+func WithFormatter(ctx context.Context, formatter *string) context.Context {
+	return context.WithValue(ctx, ctxFormatter, formatter)
+}
+
+func SetFormatter(ctx context.Context, formatter string) error {
+	f, ok := ctx.Value(ctxFormatter).(*string)
+	if !ok {
+		return missingContextErr(ctxFormatter)
+	}
+
+	*f = formatter
+	return nil
+}
+
+func Formatter(ctx context.Context) (string, bool) {
+	formatter, ok := ctx.Value(ctxFormatter).(*string)
+	if !ok {
+		return "", false
+	}
+	return *formatter, true
+}
+
+func WithRenameOptions(ctx context.Context, opts *settings.RenameOptions) context.Context {
+	return context.WithValue(ctx, ctxRenameOptions, opts)
+}
+
+func SetRenameOptions(ctx context.Context, opts settings.RenameOptions) error {
+	o, ok := ctx.Value(ctxRenameOptions).(*settings.RenameOptions)
+	if !ok {
+		return missingContextErr(ctxRenameOptions)
+	}
+
+	*o = opts
+	return nil
+}
+
+func RenameOptions(ctx context.Context) (settings.RenameOptions, error) {
+	opts, ok := ctx.Value(ctxRenameOptions).(*settings.RenameOptions)
+	if !ok {
+		return settings.RenameOptions{}, missingContextErr(ctxRenameOptions)
+	}
+	return *opts, nil
+}
+
+func WithOrganizeAttributesOptions(ctx context.Context, opts *settings.OrganizeAttributesOptions) context.Context {
+	return context.WithValue(ctx, ctxOrganizeAttributes, opts)
+}
+
+func SetOrganizeAttributesOptions(ctx context.Context, opts settings.OrganizeAttributesOptions) error {
+	o, ok := ctx.Value(ctxOrganizeAttributes).(*settings.OrganizeAttributesOptions)
+	if !ok {
+		return missingContextErr(ctxOrganizeAttributes)
+	}
+
+	*o = opts
+	return nil
+}
+
+func OrganizeAttributesOptions(ctx context.Context) (settings.OrganizeAttributesOptions, error) {
+	opts, ok := ctx.Value(ctxOrganizeAttributes).(*settings.OrganizeAttributesOptions)
+	if !ok {
+		return settings.OrganizeAttributesOptions{}, missingContextErr(ctxOrganizeAttributes)
+	}
+	return *opts, nil
+}
+
+func WithSemanticTokensCache(ctx context.Context, cache *ilsp.SemanticTokensCache) context.Context {
+	return context.WithValue(ctx, ctxSemanticTokensCache, cache)
+}
+
+func SemanticTokensCache(ctx context.Context) (*ilsp.SemanticTokensCache, error) {
+	cache, ok := ctx.Value(ctxSemanticTokensCache).(*ilsp.SemanticTokensCache)
+	if !ok {
+		return nil, missingContextErr(ctxSemanticTokensCache)
+	}
+	return cache, nil
+}
+
+func WithCompletionCache(ctx context.Context, cache *ilsp.CompletionCache) context.Context {
+	return context.WithValue(ctx, ctxCompletionCache, cache)
+}
+
+func CompletionCache(ctx context.Context) (*ilsp.CompletionCache, error) {
+	cache, ok := ctx.Value(ctxCompletionCache).(*ilsp.CompletionCache)
+	if !ok {
+		return nil, missingContextErr(ctxCompletionCache)
+	}
+	return cache, nil
+}
@@ -0,0 +1,9 @@
+/*
+Package eval provides static evaluation of Terraform configuration,
+such as effective values of input variables and types of local values.
+
+Evaluation is based on the already decoded module state (module metadata
+and parsed files) and never involves Terraform CLI, so anything which is
+only known during plan (e.g. resource attributes) remains unknown.
+*/
+package eval
@@ -0,0 +1,309 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestVariableValues(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"terraform.tfvars": `region = "eu-west-1"
+zones = ["a", "b"]
+`,
+		"prod.auto.tfvars": `region = "us-east-1"`,
+		"dev.tfvars":       `instances = 5`,
+	})
+
+	environ := []string{
+		"HOME=/home/test",
+		"TF_VAR_instances=3",
+		"TF_VAR_tags={env = \"test\"}",
+		"TF_VAR_undeclared=foo",
+	}
+
+	values := VariableValues(mod, environ)
+	expectedValues := map[string]VariableValue{
+		"region": {
+			Value:  cty.StringVal("us-east-1"),
+			Source: "prod.auto.tfvars",
+		},
+		"zones": {
+			Value:  cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			Source: "terraform.tfvars",
+		},
+		"instances": {
+			Value:  cty.NumberIntVal(3),
+			Source: "TF_VAR_instances",
+		},
+		"tags": {
+			Value:  cty.MapVal(map[string]cty.Value{"env": cty.StringVal("test")}),
+			Source: "TF_VAR_tags",
+		},
+		"password": {
+			Value:     cty.StringVal("secret"),
+			Source:    "default",
+			Sensitive: true,
+		},
+	}
+
+	if diff := cmp.Diff(expectedValues, values, ctyComparer); diff != "" {
+		t.Fatalf("unexpected values: %s", diff)
+	}
+}
+
+func TestVariableValues_jsonFiles(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"terraform.tfvars": `region = "eu-west-1"
+zones = ["a", "b"]
+instances = 1
+`,
+		"terraform.tfvars.json": `{"region": "eu-west-2", "zones": ["c"]}`,
+		"a.auto.tfvars.json":    `{"region": "us-east-1"}`,
+		"terraform.auto.tfvars": `instances = 2`,
+	})
+
+	values := VariableValues(mod, []string{})
+	expectedValues := map[string]VariableValue{
+		"region": {
+			Value:  cty.StringVal("us-east-1"),
+			Source: "a.auto.tfvars.json",
+		},
+		"zones": {
+			Value:  cty.ListVal([]cty.Value{cty.StringVal("c")}),
+			Source: "terraform.tfvars.json",
+		},
+		"instances": {
+			Value:  cty.NumberIntVal(2),
+			Source: "terraform.auto.tfvars",
+		},
+		"password": {
+			Value:     cty.StringVal("secret"),
+			Source:    "default",
+			Sensitive: true,
+		},
+	}
+
+	if diff := cmp.Diff(expectedValues, values, ctyComparer); diff != "" {
+		t.Fatalf("unexpected values: %s", diff)
+	}
+}
+
+func TestAutoloadedVarsFilenames(t *testing.T) {
+	files := ast.VarsFiles{
+		"b.auto.tfvars.json":    &hcl.File{},
+		"terraform.tfvars.json": &hcl.File{},
+		"a.auto.tfvars":         &hcl.File{},
+		"dev.tfvars":            &hcl.File{},
+		"terraform.tfvars":      &hcl.File{},
+		"b.auto.tfvars":         &hcl.File{},
+	}
+
+	expectedNames := []ast.VarsFilename{
+		"terraform.tfvars",
+		"terraform.tfvars.json",
+		"a.auto.tfvars",
+		"b.auto.tfvars",
+		"b.auto.tfvars.json",
+	}
+	if diff := cmp.Diff(expectedNames, autoloadedVarsFilenames(files)); diff != "" {
+		t.Fatalf("unexpected order of files: %s", diff)
+	}
+}
+
+func TestLocalTypes(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"terraform.tfvars": `zones = ["a", "b"]`,
+	})
+
+	types := LocalTypes(mod, VariableValues(mod, []string{}))
+	expectedTypes := map[string]cty.Type{
+		"name":     cty.String,
+		"zone_ids": cty.Tuple([]cty.Type{cty.String, cty.String}),
+		"count":    cty.Number,
+		"upper":    cty.String,
+		"id":       cty.String,
+	}
+
+	if diff := cmp.Diff(expectedTypes, types, ctyComparer); diff != "" {
+		t.Fatalf("unexpected types: %s", diff)
+	}
+}
+
+func TestHints(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"terraform.tfvars": `region = "eu-west-1"`,
+	})
+
+	hints := Hints(mod, "main.tf", []string{})
+	expectedHints := []Hint{
+		{
+			Kind:    ValueHint,
+			Pos:     hcl.Pos{Line: 1, Column: 18, Byte: 17},
+			Label:   `= "eu-west-1"`,
+			Tooltip: "Value from terraform.tfvars",
+		},
+		{
+			Kind:    ValueHint,
+			Pos:     hcl.Pos{Line: 9, Column: 20, Byte: 122},
+			Label:   "= (sensitive)",
+			Tooltip: "Value from default",
+		},
+		{
+			Kind:    ValueHint,
+			Pos:     hcl.Pos{Line: 15, Column: 22, Byte: 208},
+			Label:   `= "eu-west-1"`,
+			Tooltip: "Value from terraform.tfvars",
+		},
+		{
+			Kind:  TypeHint,
+			Pos:   hcl.Pos{Line: 18, Column: 35, Byte: 246},
+			Label: ": string",
+		},
+		{
+			Kind:    ValueHint,
+			Pos:     hcl.Pos{Line: 21, Column: 27, Byte: 285},
+			Label:   `= "eu-west-1"`,
+			Tooltip: "Value from terraform.tfvars",
+		},
+		{
+			Kind:    ValueHint,
+			Pos:     hcl.Pos{Line: 22, Column: 49, Byte: 340},
+			Label:   `= "eu-west-1"`,
+			Tooltip: "Value from terraform.tfvars",
+		},
+		{
+			Kind:  TypeHint,
+			Pos:   hcl.Pos{Line: 24, Column: 30, Byte: 408},
+			Label: ": string",
+		},
+	}
+
+	if diff := cmp.Diff(expectedHints, hints); diff != "" {
+		t.Fatalf("unexpected hints: %s", diff)
+	}
+}
+
+func TestValueLabel(t *testing.T) {
+	testCases := []struct {
+		value         cty.Value
+		expectedLabel string
+	}{
+		{cty.StringVal("foo"), `"foo"`},
+		{cty.NumberIntVal(42), `42`},
+		{cty.NullVal(cty.String), `null`},
+		{cty.UnknownVal(cty.String), `(known after apply)`},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("foo"),
+				"with key": cty.True,
+			}),
+			`{name = "foo", "with key" = true}`,
+		},
+		{
+			cty.TupleVal([]cty.Value{
+				cty.StringVal("first-very-long-element"),
+				cty.StringVal("second-very-long-element"),
+			}),
+			`["first-very-long-element", "second-ver…`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expectedLabel, func(t *testing.T) {
+			label := valueLabel(tc.value)
+			if label != tc.expectedLabel {
+				t.Fatalf("label mismatch\nexpected: %s\ngiven: %s", tc.expectedLabel, label)
+			}
+		})
+	}
+}
+
+const testModuleConfig = `variable "region" {
+  type = string
+}
+
+variable "zones" {}
+variable "instances" {}
+variable "tags" {}
+
+variable "password" {
+  default   = "secret"
+  sensitive = true
+}
+
+provider "aws" {
+  region = var.region
+}
+
+output "name" { value = local.name }
+
+locals {
+  name     = "${var.region}-app"
+  zone_ids = [for z in var.zones : "${var.region}${z}"]
+  count    = length(var.zones)
+  upper    = upper(local.name)
+  id       = "i-${aws_instance.app.id}"
+  unknown  = aws_instance.app
+}
+`
+
+var ctyComparer = cmp.Options{
+	cmp.Comparer(func(a, b cty.Value) bool {
+		return a.RawEquals(b)
+	}),
+	cmp.Comparer(func(a, b cty.Type) bool {
+		return a.Equals(b)
+	}),
+}
+
+func testModule(t *testing.T, varsFiles map[string]string) module.Module {
+	f, diags := hclsyntax.ParseConfig([]byte(testModuleConfig), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	vFiles := make(ast.VarsFiles, 0)
+	for name, src := range varsFiles {
+		var vf *hcl.File
+		var diags hcl.Diagnostics
+		if ast.VarsFilename(name).IsJSON() {
+			vf, diags = json.Parse([]byte(src), name)
+		} else {
+			vf, diags = hclsyntax.ParseConfig([]byte(src), name, hcl.InitialPos)
+		}
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		vFiles[ast.VarsFilename(name)] = vf
+	}
+
+	return &state.Module{
+		Path: "/test",
+		ParsedModuleFiles: ast.ModFiles{
+			"main.tf": f,
+		},
+		ParsedVarsFiles: vFiles,
+		Meta: state.ModuleMetadata{
+			Variables: map[string]tfmod.Variable{
+				"region":    {Type: cty.String, DefaultValue: cty.NilVal},
+				"zones":     {Type: cty.List(cty.String), DefaultValue: cty.NilVal},
+				"instances": {Type: cty.Number, DefaultValue: cty.NilVal},
+				"tags":      {Type: cty.Map(cty.String), DefaultValue: cty.NilVal},
+				"password": {
+					Type:         cty.String,
+					DefaultValue: cty.StringVal("secret"),
+					IsSensitive:  true,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,165 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// maxLabelLength is the maximum length of rendered values
+// to avoid hints taking over the whole line
+const maxLabelLength = 40
+
+type HintKind int
+
+const (
+	ValueHint HintKind = iota
+	TypeHint
+)
+
+// Hint represents a short piece of evaluated information
+// to be displayed inline at the given position
+type Hint struct {
+	Kind    HintKind
+	Pos     hcl.Pos
+	Label   string
+	Tooltip string
+}
+
+// Hints returns hints for the given file of the module:
+// effective values after variable declarations and var.*
+// references, and inferred types after local.* references
+func Hints(mod module.Module, filename string, environ []string) []Hint {
+	hints := make([]Hint, 0)
+
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return hints
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return hints
+	}
+
+	varValues := VariableValues(mod, environ)
+	localTypes := LocalTypes(mod, varValues)
+
+	for _, block := range body.Blocks {
+		if block.Type != "variable" || len(block.Labels) != 1 {
+			continue
+		}
+		if hint, ok := valueHint(varValues, block.Labels[0], block.LabelRanges[0].End); ok {
+			hints = append(hints, hint)
+		}
+	}
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+		if !ok || len(expr.Traversal) != 2 {
+			return nil
+		}
+		attr, ok := expr.Traversal[1].(hcl.TraverseAttr)
+		if !ok {
+			return nil
+		}
+		pos := expr.Range().End
+
+		switch expr.Traversal.RootName() {
+		case "var":
+			if hint, ok := valueHint(varValues, attr.Name, pos); ok {
+				hints = append(hints, hint)
+			}
+		case "local":
+			if ty, ok := localTypes[attr.Name]; ok {
+				hints = append(hints, Hint{
+					Kind:  TypeHint,
+					Pos:   pos,
+					Label: ": " + typeexpr.TypeString(ty),
+				})
+			}
+		}
+		return nil
+	})
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		return hints[i].Pos.Byte < hints[j].Pos.Byte
+	})
+
+	return hints
+}
+
+func valueHint(values map[string]VariableValue, name string, pos hcl.Pos) (Hint, bool) {
+	val, ok := values[name]
+	if !ok {
+		return Hint{}, false
+	}
+
+	label := "(sensitive)"
+	if !val.Sensitive {
+		label = valueLabel(val.Value)
+	}
+
+	return Hint{
+		Kind:    ValueHint,
+		Pos:     pos,
+		Label:   "= " + label,
+		Tooltip: fmt.Sprintf("Value from %s", val.Source),
+	}, true
+}
+
+// valueLabel renders the value as a single line of HCL,
+// truncated if it is too long
+func valueLabel(val cty.Value) string {
+	s := formatValue(val)
+	if runes := []rune(s); len(runes) > maxLabelLength {
+		return string(runes[:maxLabelLength-1]) + "…"
+	}
+	return s
+}
+
+func formatValue(val cty.Value) string {
+	switch {
+	case val.IsMarked():
+		return "(sensitive)"
+	case !val.IsKnown():
+		return "(known after apply)"
+	case val.IsNull():
+		return "null"
+	}
+
+	ty := val.Type()
+	switch {
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		elems := make([]string, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			elems = append(elems, formatValue(v))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case ty.IsMapType() || ty.IsObjectType():
+		elems := make([]string, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			elems = append(elems, fmt.Sprintf("%s = %s",
+				formatKey(k.AsString()), formatValue(v)))
+		}
+		return "{" + strings.Join(elems, ", ") + "}"
+	}
+
+	return string(hclwrite.TokensForValue(val).Bytes())
+}
+
+func formatKey(key string) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return string(hclwrite.TokensForValue(cty.StringVal(key)).Bytes())
+}
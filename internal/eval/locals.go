@@ -0,0 +1,160 @@
+package eval
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/functions"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// LocalTypes infers types of local values declared in the module.
+//
+// Variables are evaluated to their effective values where known.
+// Locals whose type depends on anything only known during plan,
+// or which cannot be evaluated, are omitted.
+func LocalTypes(mod module.Module, varValues map[string]VariableValue) map[string]cty.Type {
+	exprs := localExpressions(mod)
+
+	vars := make(map[string]cty.Value, len(mod.Meta.Variables))
+	for name, v := range mod.Meta.Variables {
+		if val, ok := varValues[name]; ok {
+			vars[name] = val.Value
+			continue
+		}
+		ty := v.Type
+		if ty == cty.NilType {
+			ty = cty.DynamicPseudoType
+		}
+		vars[name] = cty.UnknownVal(ty)
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var": cty.ObjectVal(vars),
+		},
+		Functions: functionStubs(functions.FunctionsForVersion(mod.TerraformVersion)),
+	}
+	locals := make(map[string]cty.Value, len(exprs))
+
+	// locals may refer to each other, so we keep evaluating
+	// those with resolved dependencies until there is no progress
+	for len(exprs) > 0 {
+		progress := false
+		for name, expr := range exprs {
+			if !localDependenciesResolved(expr, locals, exprs) {
+				continue
+			}
+			delete(exprs, name)
+			progress = true
+
+			ctx.Variables["local"] = cty.ObjectVal(locals)
+			addUnknownRoots(ctx, expr)
+
+			val, diags := expr.Value(ctx)
+			if diags.HasErrors() {
+				val = cty.DynamicVal
+			}
+			locals[name] = val
+		}
+		if !progress {
+			// remaining locals have cyclic or unresolvable dependencies
+			break
+		}
+	}
+
+	types := make(map[string]cty.Type, len(locals))
+	for name, val := range locals {
+		if val.Type() == cty.DynamicPseudoType {
+			continue
+		}
+		types[name] = val.Type()
+	}
+	return types
+}
+
+func localExpressions(mod module.Module) map[string]hcl.Expression {
+	exprs := make(map[string]hcl.Expression, 0)
+	for _, f := range mod.ParsedModuleFiles {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		for _, block := range body.Blocks {
+			if block.Type != "locals" {
+				continue
+			}
+			for name, attr := range block.Body.Attributes {
+				exprs[name] = attr.Expr
+			}
+		}
+	}
+	return exprs
+}
+
+// localDependenciesResolved returns false if the expression
+// refers to any local value which is still pending evaluation
+func localDependenciesResolved(expr hcl.Expression, resolved map[string]cty.Value, pending map[string]hcl.Expression) bool {
+	for _, traversal := range expr.Variables() {
+		name, ok := localName(traversal)
+		if !ok {
+			continue
+		}
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+		if _, ok := pending[name]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+func localName(traversal hcl.Traversal) (string, bool) {
+	if len(traversal) < 2 || traversal.RootName() != "local" {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+// addUnknownRoots declares any other referenced roots (such as
+// resources or data sources) as unknown values of unknown type
+func addUnknownRoots(ctx *hcl.EvalContext, expr hcl.Expression) {
+	for _, traversal := range expr.Variables() {
+		root := traversal.RootName()
+		if _, ok := ctx.Variables[root]; !ok {
+			ctx.Variables[root] = cty.DynamicVal
+		}
+	}
+}
+
+// functionStubs returns functions which only return
+// unknown values of the function's return type
+func functionStubs(funcs functions.Functions) map[string]function.Function {
+	stubs := make(map[string]function.Function, len(funcs))
+	for name, f := range funcs {
+		retType := f.ReturnType
+		if retType == cty.NilType {
+			retType = cty.DynamicPseudoType
+		}
+		stubs[name] = function.New(&function.Spec{
+			VarParam: &function.Parameter{
+				Type:             cty.DynamicPseudoType,
+				AllowNull:        true,
+				AllowUnknown:     true,
+				AllowDynamicType: true,
+				AllowMarked:      true,
+			},
+			Type: function.StaticReturnType(retType),
+			Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+				return cty.UnknownVal(retType), nil
+			},
+		})
+	}
+	return stubs
+}
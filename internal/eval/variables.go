@@ -0,0 +1,155 @@
+package eval
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const envVarPrefix = "TF_VAR_"
+
+// VariableValue represents effective value of an input variable
+type VariableValue struct {
+	Value cty.Value
+
+	// Source describes where the value comes from, i.e. "default",
+	// name of the vars file or name of the environment variable
+	Source    string
+	Sensitive bool
+}
+
+// VariableValues returns effective values of variables declared in the module,
+// as Terraform would use them in a plan without any -var or -var-file flags.
+//
+// Values are taken from (in order of precedence) autoloaded vars files,
+// TF_VAR_ environment variables found in environ and defaults.
+// Variables without any value are omitted.
+func VariableValues(mod module.Module, environ []string) map[string]VariableValue {
+	values := make(map[string]VariableValue, 0)
+
+	for name, v := range mod.Meta.Variables {
+		if v.DefaultValue == cty.NilVal {
+			continue
+		}
+		values[name] = VariableValue{
+			Value:     v.DefaultValue,
+			Source:    "default",
+			Sensitive: v.IsSensitive,
+		}
+	}
+
+	for _, envVar := range environ {
+		if !strings.HasPrefix(envVar, envVarPrefix) {
+			continue
+		}
+		idx := strings.Index(envVar, "=")
+		if idx < 0 {
+			continue
+		}
+		name, rawValue := envVar[len(envVarPrefix):idx], envVar[idx+1:]
+
+		v, ok := mod.Meta.Variables[name]
+		if !ok {
+			continue
+		}
+		val, ok := parseEnvValue(rawValue, v.Type)
+		if !ok {
+			continue
+		}
+		values[name] = VariableValue{
+			Value:     convertValue(val, v.Type),
+			Source:    envVarPrefix + name,
+			Sensitive: v.IsSensitive,
+		}
+	}
+
+	for _, filename := range autoloadedVarsFilenames(mod.ParsedVarsFiles) {
+		f := mod.ParsedVarsFiles[filename]
+		attrs, _ := f.Body.JustAttributes()
+		for name, attr := range attrs {
+			v, ok := mod.Meta.Variables[name]
+			if !ok {
+				continue
+			}
+			val, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				continue
+			}
+			values[name] = VariableValue{
+				Value:     convertValue(val, v.Type),
+				Source:    filename.String(),
+				Sensitive: v.IsSensitive,
+			}
+		}
+	}
+
+	return values
+}
+
+// autoloadedVarsFilenames returns names of autoloaded vars files
+// in the order in which Terraform loads them
+func autoloadedVarsFilenames(files ast.VarsFiles) []ast.VarsFilename {
+	names := make([]ast.VarsFilename, 0)
+	for name, f := range files {
+		if f == nil || !name.IsAutoloaded() {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := autoloadRank(names[i]), autoloadRank(names[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// autoloadRank reflects that terraform.tfvars is loaded first,
+// followed by terraform.tfvars.json and then *.auto.tfvars(.json)
+func autoloadRank(name ast.VarsFilename) int {
+	switch name {
+	case "terraform.tfvars":
+		return 0
+	case "terraform.tfvars.json":
+		return 1
+	}
+	return 2
+}
+
+// parseEnvValue parses value of a TF_VAR_ environment variable
+// which is taken literally for variables of primitive type
+// and parsed as HCL expression otherwise
+func parseEnvValue(rawValue string, ty cty.Type) (cty.Value, bool) {
+	if ty == cty.NilType || ty == cty.DynamicPseudoType || ty.IsPrimitiveType() {
+		return cty.StringVal(rawValue), true
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(rawValue), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	return val, true
+}
+
+func convertValue(val cty.Value, ty cty.Type) cty.Value {
+	if ty == cty.NilType {
+		return val
+	}
+	converted, err := convert.Convert(val, ty)
+	if err != nil {
+		return val
+	}
+	return converted
+}
@@ -0,0 +1,234 @@
+package functions
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// Call represents a (possibly incomplete) function call
+type Call struct {
+	Name      string
+	NameRange hcl.Range
+
+	// ArgIndex is index of the argument being edited
+	ArgIndex int
+}
+
+type frameKind uint
+
+const (
+	parenFrame frameKind = iota
+	callFrame
+	bracketFrame
+	braceFrame
+	templateFrame
+)
+
+type frame struct {
+	kind frameKind
+	call *Call
+}
+
+// CallAtPos returns the innermost function call enclosing
+// the given position, if any.
+//
+// Lexer tokens are used instead of the parsed AST, so that
+// calls are recognized in incomplete (invalid) configuration
+// as it is being typed.
+func CallAtPos(src []byte, filename string, pos hcl.Pos) (*Call, bool) {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	stack, _ := scanTokens(tokens, pos)
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i].kind == callFrame {
+			return stack[i].call, true
+		}
+	}
+	return nil, false
+}
+
+// NameAtPos returns name and range of the function
+// which is being called at the given position
+func NameAtPos(src []byte, filename string, pos hcl.Pos) (string, hcl.Range, bool) {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+
+	for i, token := range tokens {
+		if token.Type != hclsyntax.TokenIdent {
+			continue
+		}
+		if pos.Byte < token.Range.Start.Byte || pos.Byte > token.Range.End.Byte {
+			continue
+		}
+		if isCallName(tokens, i) {
+			return string(token.Bytes), token.Range, true
+		}
+	}
+
+	return "", hcl.Range{}, false
+}
+
+// PrefixAtPos returns the (possibly empty) prefix of a function name
+// being typed at the given position along with its range,
+// if the position is in a context where a function can be called.
+func PrefixAtPos(src []byte, filename string, pos hcl.Pos) (string, hcl.Range, bool) {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	stack, preceding := scanTokens(tokens, pos)
+
+	prefix := ""
+	rng := hcl.Range{
+		Filename: filename,
+		Start:    pos,
+		End:      pos,
+	}
+
+	if len(preceding) > 0 {
+		last := preceding[len(preceding)-1]
+		if last.Type == hclsyntax.TokenIdent && last.Range.End.Byte == pos.Byte {
+			prefix = string(last.Bytes)
+			rng = last.Range
+			preceding = preceding[:len(preceding)-1]
+		}
+	}
+
+	// newlines are insignificant inside parentheses and brackets
+	if len(stack) > 0 {
+		switch stack[len(stack)-1].kind {
+		case parenFrame, callFrame, bracketFrame:
+			preceding = trimTrailingNewlines(preceding)
+		}
+	}
+
+	if len(preceding) == 0 {
+		return "", hcl.Range{}, false
+	}
+
+	prev := preceding[len(preceding)-1]
+	switch prev.Type {
+	case hclsyntax.TokenComma:
+		if len(stack) == 0 || stack[len(stack)-1].kind == braceFrame {
+			// object items are separated by commas too
+			return "", hcl.Range{}, false
+		}
+		return prefix, rng, true
+	case hclsyntax.TokenEqual,
+		hclsyntax.TokenOParen,
+		hclsyntax.TokenOBrack,
+		hclsyntax.TokenTemplateInterp,
+		hclsyntax.TokenColon,
+		hclsyntax.TokenQuestion,
+		hclsyntax.TokenFatArrow,
+		hclsyntax.TokenPlus,
+		hclsyntax.TokenMinus,
+		hclsyntax.TokenStar,
+		hclsyntax.TokenSlash,
+		hclsyntax.TokenPercent,
+		hclsyntax.TokenAnd,
+		hclsyntax.TokenOr,
+		hclsyntax.TokenBang,
+		hclsyntax.TokenEqualOp,
+		hclsyntax.TokenNotEqual,
+		hclsyntax.TokenLessThan,
+		hclsyntax.TokenLessThanEq,
+		hclsyntax.TokenGreaterThan,
+		hclsyntax.TokenGreaterThanEq:
+		return prefix, rng, true
+	}
+
+	return "", hcl.Range{}, false
+}
+
+// scanTokens walks all tokens starting before pos and returns
+// stack of brackets (and calls) open at pos, along with
+// the significant (non-comment) tokens preceding pos
+func scanTokens(tokens hclsyntax.Tokens, pos hcl.Pos) ([]frame, hclsyntax.Tokens) {
+	stack := make([]frame, 0)
+	preceding := make(hclsyntax.Tokens, 0)
+
+	for i, token := range tokens {
+		if token.Range.Start.Byte >= pos.Byte {
+			break
+		}
+
+		switch token.Type {
+		case hclsyntax.TokenComment:
+			continue
+		case hclsyntax.TokenOParen:
+			if isCallName(tokens, i-1) {
+				name := tokens[i-1]
+				stack = append(stack, frame{
+					kind: callFrame,
+					call: &Call{
+						Name:      string(name.Bytes),
+						NameRange: name.Range,
+					},
+				})
+			} else {
+				stack = append(stack, frame{kind: parenFrame})
+			}
+		case hclsyntax.TokenOBrack:
+			stack = append(stack, frame{kind: bracketFrame})
+		case hclsyntax.TokenOBrace:
+			stack = append(stack, frame{kind: braceFrame})
+		case hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl:
+			stack = append(stack, frame{kind: templateFrame})
+		case hclsyntax.TokenCParen:
+			stack = popFrame(stack, parenFrame, callFrame)
+		case hclsyntax.TokenCBrack:
+			stack = popFrame(stack, bracketFrame)
+		case hclsyntax.TokenCBrace:
+			stack = popFrame(stack, braceFrame)
+		case hclsyntax.TokenTemplateSeqEnd:
+			stack = popFrame(stack, templateFrame)
+		case hclsyntax.TokenComma:
+			if len(stack) > 0 && stack[len(stack)-1].kind == callFrame {
+				stack[len(stack)-1].call.ArgIndex++
+			}
+		}
+
+		preceding = append(preceding, token)
+	}
+
+	return stack, preceding
+}
+
+// popFrame pops the last frame off the stack if it is of one
+// of the given kinds, leaving the stack intact otherwise
+// to tolerate unbalanced brackets
+func popFrame(stack []frame, kinds ...frameKind) []frame {
+	if len(stack) == 0 {
+		return stack
+	}
+	last := stack[len(stack)-1]
+	for _, kind := range kinds {
+		if last.kind == kind {
+			return stack[:len(stack)-1]
+		}
+	}
+	return stack
+}
+
+// isCallName reports whether token at the given index is an identifier
+// followed by an opening parenthesis, i.e. a name of a called function
+// rather than an attribute of a traversal
+func isCallName(tokens hclsyntax.Tokens, idx int) bool {
+	if idx < 0 || idx+1 >= len(tokens) {
+		return false
+	}
+	if tokens[idx].Type != hclsyntax.TokenIdent {
+		return false
+	}
+	if tokens[idx+1].Type != hclsyntax.TokenOParen {
+		return false
+	}
+	if idx > 0 && tokens[idx-1].Type == hclsyntax.TokenDot {
+		return false
+	}
+	return true
+}
+
+func trimTrailingNewlines(tokens hclsyntax.Tokens) hclsyntax.Tokens {
+	for len(tokens) > 0 && tokens[len(tokens)-1].Type == hclsyntax.TokenNewline {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
@@ -0,0 +1,1033 @@
+package functions
+
+import (
+	"github.com/hashicorp/go-version"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var (
+	v0_12_2  = version.Must(version.NewVersion("0.12.2"))
+	v0_12_7  = version.Must(version.NewVersion("0.12.7"))
+	v0_12_8  = version.Must(version.NewVersion("0.12.8"))
+	v0_12_10 = version.Must(version.NewVersion("0.12.10"))
+	v0_12_17 = version.Must(version.NewVersion("0.12.17"))
+	v0_12_20 = version.Must(version.NewVersion("0.12.20"))
+	v0_13_2  = version.Must(version.NewVersion("0.13.2"))
+	v0_14_0  = version.Must(version.NewVersion("0.14.0"))
+	v0_15_0  = version.Must(version.NewVersion("0.15.0"))
+	v1_3_0   = version.Must(version.NewVersion("1.3.0"))
+	v1_5_0   = version.Must(version.NewVersion("1.5.0"))
+)
+
+var (
+	anyType    = cty.DynamicPseudoType
+	listOfAny  = cty.List(cty.DynamicPseudoType)
+	listOfStr  = cty.List(cty.String)
+	mapOfAny   = cty.Map(cty.DynamicPseudoType)
+	setOfAny   = cty.Set(cty.DynamicPseudoType)
+	listOfBool = cty.List(cty.Bool)
+	listOfNum  = cty.List(cty.Number)
+)
+
+// catalog represents all known built-in functions
+// as documented at https://www.terraform.io/docs/language/functions/
+var catalog = []Function{
+	// Numeric Functions
+	{
+		Name:        "abs",
+		Description: "`abs` returns the absolute value of the given number.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "ceil",
+		Description: "`ceil` returns the closest whole number that is greater than or equal to the given value, which may be a fraction.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "floor",
+		Description: "`floor` returns the closest whole number that is less than or equal to the given value, which may be a fraction.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "log",
+		Description: "`log` returns the logarithm of a given number in a given base.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+			{Name: "base", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:          "max",
+		Description:   "`max` takes one or more numbers and returns the greatest number from the set.",
+		VariadicParam: &Parameter{Name: "numbers", Type: cty.Number},
+		ReturnType:    cty.Number,
+	},
+	{
+		Name:          "min",
+		Description:   "`min` takes one or more numbers and returns the smallest number from the set.",
+		VariadicParam: &Parameter{Name: "numbers", Type: cty.Number},
+		ReturnType:    cty.Number,
+	},
+	{
+		Name:        "parseint",
+		Description: "`parseint` parses the given string as a representation of an integer in the specified base and returns the resulting number. The base must be between 2 and 62 inclusive.",
+		Params: []Parameter{
+			{Name: "number", Type: cty.String},
+			{Name: "base", Type: cty.Number},
+		},
+		ReturnType:   cty.Number,
+		IntroducedIn: v0_12_10,
+	},
+	{
+		Name:        "pow",
+		Description: "`pow` calculates an exponent, by raising its first argument to the power of the second argument.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+			{Name: "power", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "signum",
+		Description: "`signum` determines the sign of a number, returning a number between -1 and 1 to represent the sign.",
+		Params: []Parameter{
+			{Name: "num", Type: cty.Number},
+		},
+		ReturnType: cty.Number,
+	},
+
+	// String Functions
+	{
+		Name:        "chomp",
+		Description: "`chomp` removes newline characters at the end of a string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "endswith",
+		Description: "`endswith` takes two values: a string to check and a suffix string. The function returns true if the first string ends with that exact suffix.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "suffix", Type: cty.String},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v1_3_0,
+	},
+	{
+		Name:        "format",
+		Description: "The `format` function produces a string by formatting a number of other values according to a specification string. It is similar to the `printf` function in C, and other similar functions in other programming languages.",
+		Params: []Parameter{
+			{Name: "format", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "args", Type: anyType},
+		ReturnType:    cty.String,
+	},
+	{
+		Name:        "formatlist",
+		Description: "`formatlist` produces a list of strings by formatting a number of other values according to a specification string.",
+		Params: []Parameter{
+			{Name: "format", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "args", Type: anyType},
+		ReturnType:    listOfStr,
+	},
+	{
+		Name:        "indent",
+		Description: "`indent` adds a given number of spaces to the beginnings of all but the first line in a given multi-line string.",
+		Params: []Parameter{
+			{Name: "spaces", Type: cty.Number},
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "join",
+		Description: "`join` produces a string by concatenating together all elements of a given list of strings with the given delimiter.",
+		Params: []Parameter{
+			{Name: "separator", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "lists", Type: listOfStr},
+		ReturnType:    cty.String,
+	},
+	{
+		Name:        "lower",
+		Description: "`lower` converts all cased letters in the given string to lowercase.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "regex",
+		Description: "`regex` applies a regular expression to a string and returns the matching substrings.",
+		Params: []Parameter{
+			{Name: "pattern", Type: cty.String},
+			{Name: "string", Type: cty.String},
+		},
+		ReturnType:   anyType,
+		IntroducedIn: v0_12_7,
+	},
+	{
+		Name:        "regexall",
+		Description: "`regexall` applies a regular expression to a string and returns a list of all matches.",
+		Params: []Parameter{
+			{Name: "pattern", Type: cty.String},
+			{Name: "string", Type: cty.String},
+		},
+		ReturnType:   listOfAny,
+		IntroducedIn: v0_12_7,
+	},
+	{
+		Name:        "replace",
+		Description: "`replace` searches a given string for another given substring, and replaces each occurrence with a given replacement string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "substr", Type: cty.String},
+			{Name: "replace", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "split",
+		Description: "`split` produces a list by dividing a given string at all occurrences of a given separator.",
+		Params: []Parameter{
+			{Name: "separator", Type: cty.String},
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: listOfStr,
+	},
+	{
+		Name:        "startswith",
+		Description: "`startswith` takes two values: a string to check and a prefix string. The function returns true if the string begins with that exact prefix.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "prefix", Type: cty.String},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v1_3_0,
+	},
+	{
+		Name:        "strcontains",
+		Description: "`strcontains` takes two values: a string to check and an expected substring. The function returns true if the string has the substring contained within it.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "substr", Type: cty.String},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v1_5_0,
+	},
+	{
+		Name:        "strrev",
+		Description: "`strrev` reverses the characters in a string. Note that the characters are treated as _Unicode characters_ (in technical terms, Unicode grapheme cluster boundaries are respected).",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "substr",
+		Description: "`substr` extracts a substring from a given string by offset and (maximum) length.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "offset", Type: cty.Number},
+			{Name: "length", Type: cty.Number},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "title",
+		Description: "`title` converts the first letter of each word in the given string to uppercase.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "trim",
+		Description: "`trim` removes the specified set of characters from the start and end of the given string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "cutset", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_12_17,
+	},
+	{
+		Name:        "trimprefix",
+		Description: "`trimprefix` removes the specified prefix from the start of the given string. If the string does not start with the prefix, the string is returned unchanged.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "prefix", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_12_17,
+	},
+	{
+		Name:        "trimspace",
+		Description: "`trimspace` removes any space characters from the start and end of the given string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "trimsuffix",
+		Description: "`trimsuffix` removes the specified suffix from the end of the given string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: "suffix", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_12_17,
+	},
+	{
+		Name:        "upper",
+		Description: "`upper` converts all cased letters in the given string to uppercase.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+
+	// Collection Functions
+	{
+		Name:        "alltrue",
+		Description: "`alltrue` returns `true` if all elements in a given collection are `true` or `\"true\"`. It also returns `true` if the collection is empty.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfBool},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v0_14_0,
+	},
+	{
+		Name:        "anytrue",
+		Description: "`anytrue` returns `true` if any element in a given collection is `true` or `\"true\"`. It also returns `false` if the collection is empty.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfBool},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v0_14_0,
+	},
+	{
+		Name:        "chunklist",
+		Description: "`chunklist` splits a single list into fixed-size chunks, returning a list of lists.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfAny},
+			{Name: "size", Type: cty.Number},
+		},
+		ReturnType: cty.List(listOfAny),
+	},
+	{
+		Name:          "coalesce",
+		Description:   "`coalesce` takes any number of arguments and returns the first one that isn't null or an empty string.",
+		VariadicParam: &Parameter{Name: "vals", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:          "coalescelist",
+		Description:   "`coalescelist` takes any number of list arguments and returns the first one that isn't empty.",
+		VariadicParam: &Parameter{Name: "vals", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:        "compact",
+		Description: "`compact` takes a list of strings and returns a new list with any empty string elements removed.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfStr},
+		},
+		ReturnType: listOfStr,
+	},
+	{
+		Name:          "concat",
+		Description:   "`concat` takes two or more lists and combines them into a single list.",
+		VariadicParam: &Parameter{Name: "seqs", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:        "contains",
+		Description: "`contains` determines whether a given list or set contains a given single value as one of its elements.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+			{Name: "value", Type: anyType},
+		},
+		ReturnType: cty.Bool,
+	},
+	{
+		Name:        "distinct",
+		Description: "`distinct` takes a list and returns a new list with any duplicate elements removed.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfAny},
+		},
+		ReturnType: listOfAny,
+	},
+	{
+		Name:        "element",
+		Description: "`element` retrieves a single element from a list.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+			{Name: "index", Type: cty.Number},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "flatten",
+		Description: "`flatten` takes a list and replaces any elements that are lists with a flattened sequence of the list contents.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "index",
+		Description: "`index` finds the element index for a given value in a list.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+			{Name: "value", Type: anyType},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "keys",
+		Description: "`keys` takes a map and returns a list containing the keys from that map.",
+		Params: []Parameter{
+			{Name: "inputMap", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "length",
+		Description: "`length` determines the length of a given list, map, or string.",
+		Params: []Parameter{
+			{Name: "value", Type: anyType},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:          "list",
+		Description:   "The `list` function is no longer available. Prior to Terraform v0.12 it was the only available syntax for writing a literal list inside an expression, but Terraform v0.12 introduced a new first-class syntax.",
+		VariadicParam: &Parameter{Name: "vals", Type: anyType},
+		ReturnType:    listOfAny,
+		RemovedIn:     v0_15_0,
+	},
+	{
+		Name:        "lookup",
+		Description: "`lookup` retrieves the value of a single element from a map, given its key. If the given key does not exist, the given default value is returned instead.",
+		Params: []Parameter{
+			{Name: "inputMap", Type: anyType},
+			{Name: "key", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "default", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:          "map",
+		Description:   "The `map` function is no longer available. Prior to Terraform v0.12 it was the only available syntax for writing a literal map inside an expression, but Terraform v0.12 introduced a new first-class syntax.",
+		VariadicParam: &Parameter{Name: "vals", Type: anyType},
+		ReturnType:    mapOfAny,
+		RemovedIn:     v0_15_0,
+	},
+	{
+		Name:        "matchkeys",
+		Description: "`matchkeys` constructs a new list by taking a subset of elements from one list whose indexes match the corresponding indexes of values in another list.",
+		Params: []Parameter{
+			{Name: "values", Type: listOfAny},
+			{Name: "keys", Type: listOfAny},
+			{Name: "searchset", Type: listOfAny},
+		},
+		ReturnType: listOfAny,
+	},
+	{
+		Name:          "merge",
+		Description:   "`merge` takes an arbitrary number of maps or objects, and returns a single map or object that contains a merged set of elements from all arguments.",
+		VariadicParam: &Parameter{Name: "maps", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:        "one",
+		Description: "`one` takes a list, set, or tuple value with either zero or one elements. If the collection is empty, `one` returns `null`. Otherwise, `one` returns the first element. If there are two or more elements then `one` will return an error.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+		},
+		ReturnType:   anyType,
+		IntroducedIn: v0_15_0,
+	},
+	{
+		Name:          "range",
+		Description:   "`range` generates a list of numbers using a start value, a limit value, and a step value.",
+		VariadicParam: &Parameter{Name: "params", Type: cty.Number},
+		ReturnType:    listOfNum,
+	},
+	{
+		Name:        "reverse",
+		Description: "`reverse` takes a sequence and produces a new sequence of the same length with all of the same elements as the given sequence but in reverse order.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "setintersection",
+		Description: "The `setintersection` function takes multiple sets and produces a single set containing only the elements that all of the given sets have in common. In other words, it computes the intersection of the sets.",
+		Params: []Parameter{
+			{Name: "first_set", Type: setOfAny},
+		},
+		VariadicParam: &Parameter{Name: "other_sets", Type: setOfAny},
+		ReturnType:    setOfAny,
+	},
+	{
+		Name:          "setproduct",
+		Description:   "The `setproduct` function finds all of the possible combinations of elements from all of the given sets by computing the Cartesian product.",
+		VariadicParam: &Parameter{Name: "sets", Type: anyType},
+		ReturnType:    anyType,
+	},
+	{
+		Name:        "setsubtract",
+		Description: "The `setsubtract` function returns a new set containing the elements from the first set that are not present in the second set. In other words, it computes the relative complement of the second set.",
+		Params: []Parameter{
+			{Name: "a", Type: setOfAny},
+			{Name: "b", Type: setOfAny},
+		},
+		ReturnType: setOfAny,
+	},
+	{
+		Name:        "setunion",
+		Description: "The `setunion` function takes multiple sets and produces a single set containing the elements from all of the given sets. In other words, it computes the union of the sets.",
+		Params: []Parameter{
+			{Name: "first_set", Type: setOfAny},
+		},
+		VariadicParam: &Parameter{Name: "other_sets", Type: setOfAny},
+		ReturnType:    setOfAny,
+	},
+	{
+		Name:        "slice",
+		Description: "`slice` extracts some consecutive elements from within a list.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+			{Name: "start_index", Type: cty.Number},
+			{Name: "end_index", Type: cty.Number},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "sort",
+		Description: "`sort` takes a list of strings and returns a new list with those strings sorted lexicographically.",
+		Params: []Parameter{
+			{Name: "list", Type: listOfStr},
+		},
+		ReturnType: listOfStr,
+	},
+	{
+		Name:        "sum",
+		Description: "`sum` takes a list or set of numbers and returns the sum of those numbers.",
+		Params: []Parameter{
+			{Name: "list", Type: anyType},
+		},
+		ReturnType:   cty.Number,
+		IntroducedIn: v0_13_2,
+	},
+	{
+		Name:        "transpose",
+		Description: "`transpose` takes a map of lists of strings and swaps the keys and values to produce a new map of lists of strings.",
+		Params: []Parameter{
+			{Name: "values", Type: cty.Map(listOfStr)},
+		},
+		ReturnType: cty.Map(listOfStr),
+	},
+	{
+		Name:        "values",
+		Description: "`values` takes a map and returns a list containing the values of the elements in that map.",
+		Params: []Parameter{
+			{Name: "mapping", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "zipmap",
+		Description: "`zipmap` constructs a map from a list of keys and a corresponding list of values.",
+		Params: []Parameter{
+			{Name: "keys", Type: listOfStr},
+			{Name: "values", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+
+	// Encoding Functions
+	{
+		Name:        "base64decode",
+		Description: "`base64decode` takes a string containing a Base64 character sequence and returns the original string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "base64encode",
+		Description: "`base64encode` applies Base64 encoding to a string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "base64gzip",
+		Description: "`base64gzip` compresses a string with gzip and then encodes the result in Base64 encoding.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "csvdecode",
+		Description: "`csvdecode` decodes a string containing CSV-formatted data and produces a list of maps representing that data.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "jsondecode",
+		Description: "`jsondecode` interprets a given string as JSON, returning a representation of the result of decoding that string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: anyType,
+	},
+	{
+		Name:        "jsonencode",
+		Description: "`jsonencode` encodes a given value to a string using JSON syntax.",
+		Params: []Parameter{
+			{Name: "val", Type: anyType},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "textdecodebase64",
+		Description: "`textdecodebase64` function decodes a string that was previously Base64-encoded, and then interprets the result as characters in a specified character encoding.",
+		Params: []Parameter{
+			{Name: "source", Type: cty.String},
+			{Name: "encoding", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_14_0,
+	},
+	{
+		Name:        "textencodebase64",
+		Description: "`textencodebase64` encodes the unicode characters in a given string using a specified character encoding, returning the result base64 encoded because Terraform language strings are always sequences of unicode characters.",
+		Params: []Parameter{
+			{Name: "string", Type: cty.String},
+			{Name: "encoding", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_14_0,
+	},
+	{
+		Name:        "urlencode",
+		Description: "`urlencode` applies URL encoding to a given string.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "yamldecode",
+		Description: "`yamldecode` parses a string as a subset of YAML, and produces a representation of its value.",
+		Params: []Parameter{
+			{Name: "src", Type: cty.String},
+		},
+		ReturnType:   anyType,
+		IntroducedIn: v0_12_2,
+	},
+	{
+		Name:        "yamlencode",
+		Description: "`yamlencode` encodes a given value to a string using [YAML 1.2](https://yaml.org/spec/1.2/spec.html) block syntax.",
+		Params: []Parameter{
+			{Name: "value", Type: anyType},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_12_2,
+	},
+
+	// Filesystem Functions
+	{
+		Name:        "abspath",
+		Description: "`abspath` takes a string containing a filesystem path and converts it to an absolute path. That is, if the path is not absolute, it will be joined with the current working directory.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "basename",
+		Description: "`basename` takes a string containing a filesystem path and removes all except the last portion from it.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "dirname",
+		Description: "`dirname` takes a string containing a filesystem path and removes the last portion from it.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "file",
+		Description: "`file` reads the contents of a file at the given path and returns them as a string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filebase64",
+		Description: "`filebase64` reads the contents of a file at the given path and returns them as a base64-encoded string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "fileexists",
+		Description: "`fileexists` determines whether a file exists at a given path.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.Bool,
+	},
+	{
+		Name:        "fileset",
+		Description: "`fileset` enumerates a set of regular file names given a path and pattern. The path is automatically removed from the resulting set of file names and any result still containing path separators always returns forward slash (`/`) as the path separator for cross-system compatibility.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+			{Name: "pattern", Type: cty.String},
+		},
+		ReturnType:   cty.Set(cty.String),
+		IntroducedIn: v0_12_8,
+	},
+	{
+		Name:        "pathexpand",
+		Description: "`pathexpand` takes a filesystem path that might begin with a `~` segment, and if so it replaces that segment with the current user's home directory path.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "templatefile",
+		Description: "`templatefile` reads the file at the given path and renders its content as a template using a supplied set of template variables.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+			{Name: "vars", Type: anyType},
+		},
+		ReturnType: anyType,
+	},
+
+	// Date and Time Functions
+	{
+		Name:        "formatdate",
+		Description: "`formatdate` converts a timestamp into a different time format.",
+		Params: []Parameter{
+			{Name: "format", Type: cty.String},
+			{Name: "time", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:         "plantimestamp",
+		Description:  "`plantimestamp` returns a UTC timestamp string in [RFC 3339](https://tools.ietf.org/html/rfc3339) format, fixed to a constant time representing the time of the plan.",
+		ReturnType:   cty.String,
+		IntroducedIn: v1_5_0,
+	},
+	{
+		Name:        "timeadd",
+		Description: "`timeadd` adds a duration to a timestamp, returning a new timestamp.",
+		Params: []Parameter{
+			{Name: "timestamp", Type: cty.String},
+			{Name: "duration", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "timecmp",
+		Description: "`timecmp` compares two timestamps and returns a number that represents the ordering of the instants those timestamps represent.",
+		Params: []Parameter{
+			{Name: "timestamp_a", Type: cty.String},
+			{Name: "timestamp_b", Type: cty.String},
+		},
+		ReturnType:   cty.Number,
+		IntroducedIn: v1_3_0,
+	},
+	{
+		Name:        "timestamp",
+		Description: "`timestamp` returns a UTC timestamp string in [RFC 3339](https://tools.ietf.org/html/rfc3339) format.",
+		ReturnType:  cty.String,
+	},
+
+	// Hash and Crypto Functions
+	{
+		Name:        "base64sha256",
+		Description: "`base64sha256` computes the SHA256 hash of a given string and encodes it with Base64. This is not equivalent to `base64encode(sha256(\"test\"))` since `sha256()` returns hexadecimal representation.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "base64sha512",
+		Description: "`base64sha512` computes the SHA512 hash of a given string and encodes it with Base64. This is not equivalent to `base64encode(sha512(\"test\"))` since `sha512()` returns hexadecimal representation.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "bcrypt",
+		Description: "`bcrypt` computes a hash of the given string using the Blowfish cipher, returning a string in [the _Modular Crypt Format_](https://passlib.readthedocs.io/en/stable/modular_crypt_format.html) usually expected in the shadow password file on many Unix systems.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "cost", Type: cty.Number},
+		ReturnType:    cty.String,
+	},
+	{
+		Name:        "filebase64sha256",
+		Description: "`filebase64sha256` is a variant of `base64sha256` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filebase64sha512",
+		Description: "`filebase64sha512` is a variant of `base64sha512` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filemd5",
+		Description: "`filemd5` is a variant of `md5` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filesha1",
+		Description: "`filesha1` is a variant of `sha1` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filesha256",
+		Description: "`filesha256` is a variant of `sha256` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "filesha512",
+		Description: "`filesha512` is a variant of `sha512` that hashes the contents of a given file rather than a literal string.",
+		Params: []Parameter{
+			{Name: "path", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "md5",
+		Description: "`md5` computes the MD5 hash of a given string and encodes it with hexadecimal digits.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "rsadecrypt",
+		Description: "`rsadecrypt` decrypts an RSA-encrypted ciphertext, returning the corresponding cleartext.",
+		Params: []Parameter{
+			{Name: "ciphertext", Type: cty.String},
+			{Name: "privatekey", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "sha1",
+		Description: "`sha1` computes the SHA1 hash of a given string and encodes it with hexadecimal digits.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "sha256",
+		Description: "`sha256` computes the SHA256 hash of a given string and encodes it with hexadecimal digits.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "sha512",
+		Description: "`sha512` computes the SHA512 hash of a given string and encodes it with hexadecimal digits.",
+		Params: []Parameter{
+			{Name: "str", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "uuid",
+		Description: "`uuid` generates a unique identifier string.",
+		ReturnType:  cty.String,
+	},
+	{
+		Name:        "uuidv5",
+		Description: "`uuidv5` generates a _name-based_ UUID, as described in [RFC 4122 section 4.3](https://tools.ietf.org/html/rfc4122#section-4.3), also known as a \"version 5\" UUID.",
+		Params: []Parameter{
+			{Name: "namespace", Type: cty.String},
+			{Name: "name", Type: cty.String},
+		},
+		ReturnType:   cty.String,
+		IntroducedIn: v0_12_2,
+	},
+
+	// IP Network Functions
+	{
+		Name:        "cidrhost",
+		Description: "`cidrhost` calculates a full host IP address for a given host number within a given IP network address prefix.",
+		Params: []Parameter{
+			{Name: "prefix", Type: cty.String},
+			{Name: "hostnum", Type: cty.Number},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "cidrnetmask",
+		Description: "`cidrnetmask` converts an IPv4 address prefix given in CIDR notation into a subnet mask address.",
+		Params: []Parameter{
+			{Name: "prefix", Type: cty.String},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "cidrsubnet",
+		Description: "`cidrsubnet` calculates a subnet address within given IP network address prefix.",
+		Params: []Parameter{
+			{Name: "prefix", Type: cty.String},
+			{Name: "newbits", Type: cty.Number},
+			{Name: "netnum", Type: cty.Number},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:        "cidrsubnets",
+		Description: "`cidrsubnets` calculates a sequence of consecutive IP address ranges within a particular CIDR prefix.",
+		Params: []Parameter{
+			{Name: "prefix", Type: cty.String},
+		},
+		VariadicParam: &Parameter{Name: "newbits", Type: cty.Number},
+		ReturnType:    listOfStr,
+		IntroducedIn:  v0_12_10,
+	},
+
+	// Type Conversion Functions
+	{
+		Name:        "can",
+		Description: "`can` evaluates the given expression and returns a boolean value indicating whether the expression produced a result without any errors.",
+		Params: []Parameter{
+			{Name: "expression", Type: anyType},
+		},
+		ReturnType:   cty.Bool,
+		IntroducedIn: v0_12_20,
+	},
+	{
+		Name:        "nonsensitive",
+		Description: "`nonsensitive` takes a sensitive value and returns a copy of that value with the sensitive marking removed, thereby exposing the sensitive value.",
+		Params: []Parameter{
+			{Name: "value", Type: anyType},
+		},
+		ReturnType:   anyType,
+		IntroducedIn: v0_15_0,
+	},
+	{
+		Name:        "sensitive",
+		Description: "`sensitive` takes any value and returns a copy of it marked so that Terraform will treat it as sensitive, with the same meaning and behavior as for [sensitive input variables](https://www.terraform.io/docs/language/values/variables.html#suppressing-values-in-cli-output).",
+		Params: []Parameter{
+			{Name: "value", Type: anyType},
+		},
+		ReturnType:   anyType,
+		IntroducedIn: v0_15_0,
+	},
+	{
+		Name:        "tobool",
+		Description: "`tobool` converts its argument to a boolean value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: cty.Bool,
+	},
+	{
+		Name:        "tolist",
+		Description: "`tolist` converts its argument to a list value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: listOfAny,
+	},
+	{
+		Name:        "tomap",
+		Description: "`tomap` converts its argument to a map value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: mapOfAny,
+	},
+	{
+		Name:        "tonumber",
+		Description: "`tonumber` converts its argument to a number value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: cty.Number,
+	},
+	{
+		Name:        "toset",
+		Description: "`toset` converts its argument to a set value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: setOfAny,
+	},
+	{
+		Name:        "tostring",
+		Description: "`tostring` converts its argument to a string value.",
+		Params: []Parameter{
+			{Name: "v", Type: anyType},
+		},
+		ReturnType: cty.String,
+	},
+	{
+		Name:          "try",
+		Description:   "`try` evaluates all of its argument expressions in turn and returns the result of the first one that does not produce any errors.",
+		VariadicParam: &Parameter{Name: "expressions", Type: anyType},
+		ReturnType:    anyType,
+		IntroducedIn:  v0_12_20,
+	},
+}
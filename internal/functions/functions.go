@@ -0,0 +1,122 @@
+// Package functions provides a catalog of Terraform built-in functions
+// and helpers for finding function calls in configuration source.
+package functions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type Function struct {
+	Name        string
+	Description string
+
+	Params        []Parameter
+	VariadicParam *Parameter
+	ReturnType    cty.Type
+
+	// IntroducedIn is the first Terraform version providing the function
+	// (nil if available since the first supported version)
+	IntroducedIn *version.Version
+	// RemovedIn is the first Terraform version without the function
+	// (nil if the function is still available)
+	RemovedIn *version.Version
+}
+
+type Parameter struct {
+	Name        string
+	Description string
+	Type        cty.Type
+}
+
+func (p Parameter) String() string {
+	return fmt.Sprintf("%s %s", p.Name, typeString(p.Type))
+}
+
+// Signature returns human-readable signature of the function, e.g.
+//
+//	cidrhost(prefix string, hostnum number) string
+func (f Function) Signature() string {
+	params := f.ParamLabels()
+	return fmt.Sprintf("%s(%s) %s", f.Name, strings.Join(params, ", "),
+		typeString(f.ReturnType))
+}
+
+// ParamLabels returns labels of all parameters
+// as they appear in the signature
+func (f Function) ParamLabels() []string {
+	labels := make([]string, 0, len(f.Params)+1)
+	for _, p := range f.Params {
+		labels = append(labels, p.String())
+	}
+	if f.VariadicParam != nil {
+		labels = append(labels, "..."+f.VariadicParam.String())
+	}
+	return labels
+}
+
+// ParamIndex returns index of the parameter (label) corresponding
+// to the argument at given index, accounting for variadic parameter
+func (f Function) ParamIndex(argIdx int) (int, bool) {
+	if argIdx < len(f.Params) {
+		return argIdx, true
+	}
+	if f.VariadicParam != nil {
+		return len(f.Params), true
+	}
+	return 0, false
+}
+
+func (f Function) isAvailableIn(v *version.Version) bool {
+	if v == nil {
+		return f.RemovedIn == nil
+	}
+	if f.IntroducedIn != nil && v.LessThan(f.IntroducedIn) {
+		return false
+	}
+	if f.RemovedIn != nil && !v.LessThan(f.RemovedIn) {
+		return false
+	}
+	return true
+}
+
+type Functions map[string]Function
+
+// FunctionsForVersion returns all functions available
+// in the given version of Terraform, or functions available
+// in the latest known version if the version is unknown (nil)
+func FunctionsForVersion(v *version.Version) Functions {
+	funcs := make(Functions, len(catalog))
+	if v != nil {
+		// prereleases provide the same functions as the final release
+		v = v.Core()
+	}
+	for _, f := range catalog {
+		if f.isAvailableIn(v) {
+			funcs[f.Name] = f
+		}
+	}
+	return funcs
+}
+
+// Names returns sorted names of all functions
+func (fs Functions) Names() []string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func typeString(ty cty.Type) string {
+	if ty == cty.NilType {
+		return "any"
+	}
+	return typeexpr.TypeString(ty)
+}
@@ -0,0 +1,333 @@
+package functions
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestFunctionsForVersion(t *testing.T) {
+	testCases := []struct {
+		version       string
+		expectedFuncs []string
+		missingFuncs  []string
+	}{
+		{
+			"0.12.0",
+			[]string{"abs", "list", "map", "lookup"},
+			[]string{"yamlencode", "try", "sum", "one", "startswith", "strcontains"},
+		},
+		{
+			"0.12.20",
+			[]string{"yamlencode", "try", "can", "list"},
+			[]string{"sum", "alltrue", "sensitive"},
+		},
+		{
+			"0.15.0-beta1",
+			[]string{"sum", "alltrue", "sensitive", "one"},
+			[]string{"list", "map", "startswith"},
+		},
+		{
+			"1.5.2",
+			[]string{"startswith", "strcontains", "plantimestamp"},
+			[]string{"list", "map"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.version, func(t *testing.T) {
+			funcs := FunctionsForVersion(version.Must(version.NewVersion(tc.version)))
+			for _, name := range tc.expectedFuncs {
+				if _, ok := funcs[name]; !ok {
+					t.Errorf("expected %q to be available", name)
+				}
+			}
+			for _, name := range tc.missingFuncs {
+				if _, ok := funcs[name]; ok {
+					t.Errorf("expected %q to be unavailable", name)
+				}
+			}
+		})
+	}
+}
+
+func TestFunctionsForVersion_unknown(t *testing.T) {
+	funcs := FunctionsForVersion(nil)
+	if _, ok := funcs["strcontains"]; !ok {
+		t.Fatal("expected latest functions to be available")
+	}
+	if _, ok := funcs["list"]; ok {
+		t.Fatal("expected removed functions to be unavailable")
+	}
+}
+
+func TestFunction_Signature(t *testing.T) {
+	funcs := FunctionsForVersion(nil)
+
+	testCases := []struct {
+		name              string
+		expectedSignature string
+	}{
+		{"cidrhost", "cidrhost(prefix string, hostnum number) string"},
+		{"lookup", "lookup(inputMap any, key string, ...default any) any"},
+		{"max", "max(...numbers number) number"},
+		{"timestamp", "timestamp() string"},
+		{"zipmap", "zipmap(keys list(string), values any) any"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sig := funcs[tc.name].Signature()
+			if sig != tc.expectedSignature {
+				t.Fatalf("signature mismatch.\nexpected: %q\ngiven:    %q",
+					tc.expectedSignature, sig)
+			}
+		})
+	}
+}
+
+func TestFunction_ParamIndex(t *testing.T) {
+	funcs := FunctionsForVersion(nil)
+
+	idx, ok := funcs["lookup"].ParamIndex(4)
+	if !ok || idx != 2 {
+		t.Fatalf("expected variadic parameter (2), given: %d (%t)", idx, ok)
+	}
+
+	_, ok = funcs["abs"].ParamIndex(1)
+	if ok {
+		t.Fatal("expected no parameter for excess argument")
+	}
+}
+
+func TestCallAtPos(t *testing.T) {
+	testCases := []struct {
+		name         string
+		src          string
+		pos          hcl.Pos
+		expectedCall *Call
+	}{
+		{
+			"first argument",
+			`a = abs(`,
+			hcl.Pos{Line: 1, Column: 9, Byte: 8},
+			&Call{
+				Name: "abs",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+					End:      hcl.Pos{Line: 1, Column: 8, Byte: 7},
+				},
+				ArgIndex: 0,
+			},
+		},
+		{
+			"second argument of incomplete call",
+			`a = lookup(var.map, "key"
+`,
+			hcl.Pos{Line: 1, Column: 26, Byte: 25},
+			&Call{
+				Name: "lookup",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+					End:      hcl.Pos{Line: 1, Column: 11, Byte: 10},
+				},
+				ArgIndex: 1,
+			},
+		},
+		{
+			"commas in nested tuple",
+			`a = concat(["a", "b"], )`,
+			hcl.Pos{Line: 1, Column: 16, Byte: 15},
+			&Call{
+				Name: "concat",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+					End:      hcl.Pos{Line: 1, Column: 11, Byte: 10},
+				},
+				ArgIndex: 0,
+			},
+		},
+		{
+			"after nested tuple",
+			`a = concat(["a", "b"], )`,
+			hcl.Pos{Line: 1, Column: 24, Byte: 23},
+			&Call{
+				Name: "concat",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+					End:      hcl.Pos{Line: 1, Column: 11, Byte: 10},
+				},
+				ArgIndex: 1,
+			},
+		},
+		{
+			"nested call",
+			`a = upper(lower("x"))`,
+			hcl.Pos{Line: 1, Column: 18, Byte: 17},
+			&Call{
+				Name: "lower",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+					End:      hcl.Pos{Line: 1, Column: 16, Byte: 15},
+				},
+				ArgIndex: 0,
+			},
+		},
+		{
+			"call in template interpolation",
+			`a = "${upper(}"`,
+			hcl.Pos{Line: 1, Column: 14, Byte: 13},
+			&Call{
+				Name: "upper",
+				NameRange: hcl.Range{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 8, Byte: 7},
+					End:      hcl.Pos{Line: 1, Column: 13, Byte: 12},
+				},
+				ArgIndex: 0,
+			},
+		},
+		{
+			"after closed call",
+			`a = abs(1)`,
+			hcl.Pos{Line: 1, Column: 11, Byte: 10},
+			nil,
+		},
+		{
+			"plain parentheses",
+			`a = (1 + 2)`,
+			hcl.Pos{Line: 1, Column: 7, Byte: 6},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			call, _ := CallAtPos([]byte(tc.src), "test.tf", tc.pos)
+			if diff := cmp.Diff(tc.expectedCall, call); diff != "" {
+				t.Fatalf("unexpected call: %s", diff)
+			}
+		})
+	}
+}
+
+func TestNameAtPos(t *testing.T) {
+	src := []byte(`a = upper(var.name)
+b = var.upper
+`)
+
+	name, rng, ok := NameAtPos(src, "test.tf", hcl.Pos{Line: 1, Column: 7, Byte: 6})
+	if !ok {
+		t.Fatal("expected function name")
+	}
+	if name != "upper" {
+		t.Fatalf("unexpected name: %q", name)
+	}
+	expectedRange := hcl.Range{
+		Filename: "test.tf",
+		Start:    hcl.Pos{Line: 1, Column: 5, Byte: 4},
+		End:      hcl.Pos{Line: 1, Column: 10, Byte: 9},
+	}
+	if diff := cmp.Diff(expectedRange, rng); diff != "" {
+		t.Fatalf("unexpected range: %s", diff)
+	}
+
+	_, _, ok = NameAtPos(src, "test.tf", hcl.Pos{Line: 2, Column: 11, Byte: 30})
+	if ok {
+		t.Fatal("expected no function name for traversal")
+	}
+}
+
+func TestPrefixAtPos(t *testing.T) {
+	testCases := []struct {
+		name           string
+		src            string
+		pos            hcl.Pos
+		expectedPrefix string
+		expectedOk     bool
+	}{
+		{
+			"empty attribute value",
+			`a = `,
+			hcl.Pos{Line: 1, Column: 5, Byte: 4},
+			"",
+			true,
+		},
+		{
+			"partial name",
+			`a = up`,
+			hcl.Pos{Line: 1, Column: 7, Byte: 6},
+			"up",
+			true,
+		},
+		{
+			"argument",
+			`a = upper(lo`,
+			hcl.Pos{Line: 1, Column: 13, Byte: 12},
+			"lo",
+			true,
+		},
+		{
+			"second argument on new line",
+			`a = lookup(var.map,
+  tr`,
+			hcl.Pos{Line: 2, Column: 5, Byte: 24},
+			"tr",
+			true,
+		},
+		{
+			"template interpolation",
+			`a = "${up"`,
+			hcl.Pos{Line: 1, Column: 10, Byte: 9},
+			"up",
+			true,
+		},
+		{
+			"attribute name",
+			`ab`,
+			hcl.Pos{Line: 1, Column: 3, Byte: 2},
+			"",
+			false,
+		},
+		{
+			"traversal step",
+			`a = var.up`,
+			hcl.Pos{Line: 1, Column: 11, Byte: 10},
+			"",
+			false,
+		},
+		{
+			"object key",
+			`a = { b = 1, c`,
+			hcl.Pos{Line: 1, Column: 15, Byte: 14},
+			"",
+			false,
+		},
+		{
+			"string literal",
+			`a = "up"`,
+			hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix, _, ok := PrefixAtPos([]byte(tc.src), "test.tf", tc.pos)
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if prefix != tc.expectedPrefix {
+				t.Fatalf("expected prefix: %q, given: %q", tc.expectedPrefix, prefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,903 @@
+package hcl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// staticAttributes are attributes which Terraform does not evaluate
+// as templates in JSON syntax, but parses the string as an expression,
+// indexed by the type of the block they belong to
+var staticAttributes = map[string]map[string]bool{
+	"variable":  {"type": true},
+	"resource":  {"depends_on": true, "provider": true},
+	"data":      {"depends_on": true, "provider": true},
+	"module":    {"depends_on": true, "providers": true},
+	"output":    {"depends_on": true},
+	"lifecycle": {"ignore_changes": true, "replace_triggered_by": true},
+	"moved":     {"from": true, "to": true},
+}
+
+// ToJSON converts configuration in native syntax into JSON syntax.
+//
+// Expressions which have no equivalent JSON value are converted
+// into strings with a single interpolation sequence and comment
+// lines directly above a block are converted into its "//" property.
+//
+// Ranges of any other comments, which JSON cannot represent
+// and which are therefore dropped, are returned too.
+func ToJSON(src []byte, filename string) ([]byte, []hcl.Range, error) {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil, fmt.Errorf("unexpected body type: %T", f.Body)
+	}
+
+	c := &jsonConverter{
+		src:       src,
+		keptLines: make(map[int]bool, 0),
+	}
+	var buf bytes.Buffer
+	c.body(body, "").write(&buf, "")
+	buf.WriteString("\n")
+
+	return buf.Bytes(), c.droppedComments(filename), nil
+}
+
+// jsonValue represents a value written in JSON syntax,
+// keeping the order of object properties
+type jsonValue interface {
+	write(buf *bytes.Buffer, indent string)
+}
+
+// jsonRaw is an already encoded scalar value
+type jsonRaw string
+
+type jsonArray []jsonValue
+
+type jsonObject []jsonProperty
+
+type jsonProperty struct {
+	name  string
+	value jsonValue
+}
+
+func (v jsonRaw) write(buf *bytes.Buffer, indent string) {
+	buf.WriteString(string(v))
+}
+
+func (v jsonArray) write(buf *bytes.Buffer, indent string) {
+	if len(v) == 0 {
+		buf.WriteString("[]")
+		return
+	}
+
+	// arrays of scalar values are kept on a single line
+	isScalar := true
+	for _, elem := range v {
+		if _, ok := elem.(jsonRaw); !ok {
+			isScalar = false
+		}
+	}
+	if isScalar {
+		buf.WriteString("[")
+		for i, elem := range v {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			elem.write(buf, indent)
+		}
+		buf.WriteString("]")
+		return
+	}
+
+	buf.WriteString("[\n")
+	for i, elem := range v {
+		buf.WriteString(indent + "  ")
+		elem.write(buf, indent+"  ")
+		if i < len(v)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(indent + "]")
+}
+
+func (v jsonObject) write(buf *bytes.Buffer, indent string) {
+	if len(v) == 0 {
+		buf.WriteString("{}")
+		return
+	}
+
+	buf.WriteString("{\n")
+	for i, prop := range v {
+		buf.WriteString(indent + "  " + jsonString(prop.name) + ": ")
+		prop.value.write(buf, indent+"  ")
+		if i < len(v)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString(indent + "}")
+}
+
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// encoding a string cannot fail
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// jsonBlocks collects blocks of a single type,
+// nested by their labels in the order of appearance
+type jsonBlocks struct {
+	bodies []jsonValue
+	labels []string
+	nested map[string]*jsonBlocks
+}
+
+func (jb *jsonBlocks) add(labels []string, body jsonValue) {
+	if len(labels) == 0 {
+		jb.bodies = append(jb.bodies, body)
+		return
+	}
+	if jb.nested == nil {
+		jb.nested = make(map[string]*jsonBlocks)
+	}
+	nested, ok := jb.nested[labels[0]]
+	if !ok {
+		nested = &jsonBlocks{}
+		jb.nested[labels[0]] = nested
+		jb.labels = append(jb.labels, labels[0])
+	}
+	nested.add(labels[1:], body)
+}
+
+func (jb *jsonBlocks) value() jsonValue {
+	if jb.nested == nil {
+		if len(jb.bodies) == 1 {
+			return jb.bodies[0]
+		}
+		return jsonArray(jb.bodies)
+	}
+
+	obj := make(jsonObject, 0, len(jb.labels))
+	for _, label := range jb.labels {
+		obj = append(obj, jsonProperty{label, jb.nested[label].value()})
+	}
+	return obj
+}
+
+type jsonConverter struct {
+	src []byte
+
+	// keptLines are lines of comments kept as "//" properties
+	keptLines map[int]bool
+}
+
+func (c *jsonConverter) droppedComments(filename string) []hcl.Range {
+	tokens, _ := hclsyntax.LexConfig(c.src, filename, hcl.InitialPos)
+
+	dropped := make([]hcl.Range, 0)
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment && !c.keptLines[token.Range.Start.Line] {
+			dropped = append(dropped, token.Range)
+		}
+	}
+	return dropped
+}
+
+func (c *jsonConverter) body(body *hclsyntax.Body, blockType string) jsonObject {
+	type item struct {
+		rng   hcl.Range
+		attr  *hclsyntax.Attribute
+		block *hclsyntax.Block
+	}
+	items := make([]item, 0, len(body.Attributes)+len(body.Blocks))
+	for _, attr := range body.Attributes {
+		items = append(items, item{rng: attr.SrcRange, attr: attr})
+	}
+	for _, block := range body.Blocks {
+		items = append(items, item{rng: block.Range(), block: block})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].rng.Start.Byte < items[j].rng.Start.Byte
+	})
+
+	obj := make(jsonObject, 0, len(items))
+	blocks := make(map[string]*jsonBlocks, 0)
+	for _, it := range items {
+		if it.attr != nil {
+			obj = append(obj, jsonProperty{
+				name:  it.attr.Name,
+				value: c.expr(it.attr.Expr, staticAttributes[blockType][it.attr.Name]),
+			})
+			continue
+		}
+
+		blockBody := make(jsonObject, 0)
+		if comment := c.commentAbove(it.block); comment != "" {
+			blockBody = append(blockBody, jsonProperty{"//", jsonRaw(jsonString(comment))})
+		}
+		blockBody = append(blockBody, c.body(it.block.Body, it.block.Type)...)
+
+		jb, ok := blocks[it.block.Type]
+		if !ok {
+			jb = &jsonBlocks{}
+			blocks[it.block.Type] = jb
+			// blocks of the same type are grouped
+			// in the position of the first one
+			obj = append(obj, jsonProperty{name: it.block.Type})
+		}
+		jb.add(it.block.Labels, blockBody)
+	}
+
+	for i, prop := range obj {
+		if prop.value == nil {
+			obj[i].value = blocks[prop.name].value()
+		}
+	}
+
+	return obj
+}
+
+// commentAbove returns text of comment lines directly above the block
+func (c *jsonConverter) commentAbove(block *hclsyntax.Block) string {
+	lines := make([]string, 0)
+	line := block.Range().Start.Line - 1
+	end := bytes.LastIndexByte(c.src[:block.Range().Start.Byte], '\n')
+	for end > 0 {
+		start := bytes.LastIndexByte(c.src[:end], '\n') + 1
+		text, ok := commentText(strings.TrimSpace(string(c.src[start:end])))
+		if !ok {
+			break
+		}
+		lines = append([]string{text}, lines...)
+		c.keptLines[line] = true
+		line--
+		end = start - 1
+	}
+	return strings.Join(lines, "\n")
+}
+
+func commentText(line string) (string, bool) {
+	switch {
+	case strings.HasPrefix(line, "#"):
+		return strings.TrimSpace(line[1:]), true
+	case strings.HasPrefix(line, "//"):
+		return strings.TrimSpace(line[2:]), true
+	case strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/") && len(line) >= 4:
+		return strings.TrimSpace(line[2 : len(line)-2]), true
+	}
+	return "", false
+}
+
+func (c *jsonConverter) source(expr hclsyntax.Expression) string {
+	rng := expr.Range()
+	return string(c.src[rng.Start.Byte:rng.End.Byte])
+}
+
+// expr converts the expression into a JSON value, where static
+// expressions are converted into strings containing their source
+func (c *jsonConverter) expr(expr hclsyntax.Expression, static bool) jsonValue {
+	if static {
+		switch e := expr.(type) {
+		case *hclsyntax.TupleConsExpr:
+			arr := make(jsonArray, 0, len(e.Exprs))
+			for _, elem := range e.Exprs {
+				arr = append(arr, c.expr(elem, true))
+			}
+			return arr
+		case *hclsyntax.ObjectConsExpr:
+			if obj, ok := c.object(e, true); ok {
+				return obj
+			}
+		case *hclsyntax.TemplateExpr:
+			// legacy quoted static expressions, such as type = "string"
+			if e.IsStringLiteral() {
+				v, _ := e.Value(nil)
+				return jsonRaw(jsonString(v.AsString()))
+			}
+		}
+		return jsonRaw(jsonString(c.source(expr)))
+	}
+
+	switch e := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		switch {
+		case e.Val.IsNull():
+			return jsonRaw("null")
+		case e.Val.Type() == cty.Bool:
+			return jsonRaw(fmt.Sprintf("%t", e.Val.True()))
+		case e.Val.Type() == cty.Number:
+			if src := c.source(e); json.Valid([]byte(src)) {
+				return jsonRaw(src)
+			}
+		}
+	case *hclsyntax.TemplateExpr:
+		if tpl, ok := c.template(e); ok {
+			return jsonRaw(jsonString(tpl))
+		}
+	case *hclsyntax.TemplateWrapExpr:
+		return jsonRaw(jsonString("${" + c.source(e.Wrapped) + "}"))
+	case *hclsyntax.TupleConsExpr:
+		arr := make(jsonArray, 0, len(e.Exprs))
+		for _, elem := range e.Exprs {
+			arr = append(arr, c.expr(elem, false))
+		}
+		return arr
+	case *hclsyntax.ObjectConsExpr:
+		if obj, ok := c.object(e, false); ok {
+			return obj
+		}
+	}
+
+	return jsonRaw(jsonString("${" + c.source(expr) + "}"))
+}
+
+func (c *jsonConverter) object(expr *hclsyntax.ObjectConsExpr, static bool) (jsonObject, bool) {
+	obj := make(jsonObject, 0, len(expr.Items))
+	for _, item := range expr.Items {
+		key, ok := c.objectKey(item.KeyExpr)
+		if !ok {
+			return nil, false
+		}
+		if !static {
+			// keys are templates in JSON syntax
+			key = escapeTemplate(key)
+		}
+		obj = append(obj, jsonProperty{key, c.expr(item.ValueExpr, static)})
+	}
+	return obj, true
+}
+
+func (c *jsonConverter) objectKey(expr hclsyntax.Expression) (string, bool) {
+	keyExpr, ok := expr.(*hclsyntax.ObjectConsKeyExpr)
+	if !ok {
+		return "", false
+	}
+	if !keyExpr.ForceNonLiteral {
+		if name := hcl.ExprAsKeyword(keyExpr.Wrapped); name != "" {
+			return name, true
+		}
+	}
+	if tpl, ok := keyExpr.Wrapped.(*hclsyntax.TemplateExpr); ok && tpl.IsStringLiteral() {
+		v, _ := tpl.Value(nil)
+		return v.AsString(), true
+	}
+	return "", false
+}
+
+// template reconstructs the template from its parts, such that it
+// can be interpreted in JSON, which is only possible for templates
+// without directives
+func (c *jsonConverter) template(expr *hclsyntax.TemplateExpr) (string, bool) {
+	var sb strings.Builder
+	for _, part := range expr.Parts {
+		if lit, ok := part.(*hclsyntax.LiteralValueExpr); ok && lit.Val.Type() == cty.String {
+			sb.WriteString(escapeTemplate(lit.Val.AsString()))
+			continue
+		}
+
+		rng := part.Range()
+		before := strings.TrimRight(string(c.src[:rng.Start.Byte]), " \t\r\n")
+		after := strings.TrimLeft(string(c.src[rng.End.Byte:]), " \t\r\n")
+		if !(strings.HasSuffix(before, "${") || strings.HasSuffix(before, "${~")) ||
+			!(strings.HasPrefix(after, "}") || strings.HasPrefix(after, "~}")) {
+			return "", false
+		}
+		sb.WriteString("${" + c.source(part) + "}")
+	}
+	return sb.String(), true
+}
+
+func escapeTemplate(s string) string {
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
+
+// ToHCL converts configuration in JSON syntax into native syntax,
+// using the given schema to tell blocks from attributes.
+//
+// Strings consisting of a single interpolation sequence
+// are converted into the bare expression and multi-line
+// strings are converted into heredoc templates.
+//
+// Error is returned if a property cannot be told apart
+// as a block or an attribute, e.g. due to missing provider schema.
+func ToHCL(src []byte, filename string, bodySchema *schema.BodySchema) ([]byte, error) {
+	_, diags := hcljson.Parse(src, filename)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if jsonKind(src) != '{' {
+		return nil, fmt.Errorf("%s: expected an object at the root", filename)
+	}
+
+	c := &hclConverter{filename: filename}
+	var sb strings.Builder
+	err := c.body(&sb, src, bodySchema, bodySchema != nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return Format([]byte(sb.String()), strings.TrimSuffix(filename, ".json"))
+}
+
+type hclConverter struct {
+	filename string
+
+	depth        int
+	hasTopBlocks bool
+}
+
+// jsonProperties returns properties of the JSON object in their order
+func jsonProperties(raw []byte) ([]jsonRawProperty, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected object, given %s", raw)
+	}
+
+	props := make([]jsonRawProperty, 0)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		err = dec.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, jsonRawProperty{tok.(string), value})
+	}
+	return props, nil
+}
+
+type jsonRawProperty struct {
+	name  string
+	value json.RawMessage
+}
+
+func jsonStringValue(raw []byte) (string, bool) {
+	var s string
+	if jsonKind(raw) != '"' || json.Unmarshal(raw, &s) != nil {
+		return "", false
+	}
+	return s, true
+}
+
+func jsonElements(raw []byte) ([]json.RawMessage, error) {
+	var elems []json.RawMessage
+	err := json.Unmarshal(raw, &elems)
+	return elems, err
+}
+
+// jsonKind returns the first character of the JSON value,
+// i.e. '{' for objects, '[' for arrays and '"' for strings
+func jsonKind(raw []byte) byte {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return 0
+	}
+	return raw[0]
+}
+
+// isAttributeValue reports whether the JSON value can only
+// represent an attribute, i.e. not a block nor list of blocks
+func isAttributeValue(raw []byte) bool {
+	switch jsonKind(raw) {
+	case '{':
+		return false
+	case '[':
+		elems, err := jsonElements(raw)
+		if err != nil {
+			return false
+		}
+		for _, elem := range elems {
+			if jsonKind(elem) == '{' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// body writes the body of the given JSON object, where isKnown
+// reports whether the schema describes the whole body, such that
+// unknown properties can be assumed to be attributes
+func (c *hclConverter) body(sb *strings.Builder, raw []byte, bodySchema *schema.BodySchema, isKnown bool, blockType string) error {
+	props, err := jsonProperties(raw)
+	if err != nil {
+		return err
+	}
+
+	for _, prop := range props {
+		if prop.name == "//" {
+			// comments of blocks are written above them
+			if blockType == "" {
+				c.comment(sb, prop.value)
+			}
+			continue
+		}
+
+		if bodySchema != nil {
+			if bs, ok := bodySchema.Blocks[prop.name]; ok {
+				err := c.blocks(sb, prop.name, []string{}, prop.value, bs)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			if prop.name == "dynamic" {
+				err := c.dynamicBlocks(sb, prop.value, bodySchema)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		isAttribute := isKnown || isAttributeValue(prop.value)
+		if bodySchema != nil {
+			_, ok := bodySchema.Attributes[prop.name]
+			isAttribute = isAttribute || ok || bodySchema.AnyAttribute != nil
+		}
+		if !isAttribute {
+			return fmt.Errorf("%s: unknown schema for %q, cannot tell whether it is a block or an attribute",
+				c.filename, prop.name)
+		}
+		if !hclsyntax.ValidIdentifier(prop.name) {
+			return fmt.Errorf("%s: invalid attribute name %q", c.filename, prop.name)
+		}
+
+		var expr string
+		if s, ok := jsonStringValue(prop.value); ok && !staticAttributes[blockType][prop.name] &&
+			strings.Contains(s, "\n") && strings.HasSuffix(s, "\n") {
+			if _, ok := wrappedExpr(s); !ok {
+				// heredoc is only used directly as attribute value,
+				// where the closing marker can be on its own line
+				expr = heredoc(s)
+			}
+		}
+		if expr == "" {
+			expr, err = c.expr(prop.value, staticAttributes[blockType][prop.name])
+			if err != nil {
+				return err
+			}
+		}
+		sb.WriteString(prop.name + " = " + expr + "\n")
+	}
+
+	return nil
+}
+
+func (c *hclConverter) comment(sb *strings.Builder, raw []byte) {
+	text, ok := jsonStringValue(raw)
+	if !ok {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+	}
+}
+
+// blocks writes blocks of the given type, collecting labels
+// from nested objects until all labels of the schema are known
+func (c *hclConverter) blocks(sb *strings.Builder, blockType string, labels []string, raw []byte, bs *schema.BlockSchema) error {
+	if jsonKind(raw) == '[' {
+		elems, err := jsonElements(raw)
+		if err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			err := c.blocks(sb, blockType, labels, elem, bs)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if jsonKind(raw) != '{' {
+		return fmt.Errorf("%s: expected object for %q block, given %s", c.filename, blockType, raw)
+	}
+
+	if len(labels) == len(bs.Labels) {
+		return c.block(sb, blockType, labels, raw, bs)
+	}
+
+	props, err := jsonProperties(raw)
+	if err != nil {
+		return err
+	}
+	for _, prop := range props {
+		if prop.name == "//" {
+			c.comment(sb, prop.value)
+			continue
+		}
+		nestedLabels := append(append([]string{}, labels...), prop.name)
+		err := c.blocks(sb, blockType, nestedLabels, prop.value, bs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *hclConverter) block(sb *strings.Builder, blockType string, labels []string, raw []byte, bs *schema.BlockSchema) error {
+	props, err := jsonProperties(raw)
+	if err != nil {
+		return err
+	}
+
+	// top-level blocks are separated by an empty line
+	if c.depth == 0 {
+		if c.hasTopBlocks {
+			sb.WriteString("\n")
+		}
+		c.hasTopBlocks = true
+	}
+
+	for _, prop := range props {
+		if prop.name == "//" {
+			c.comment(sb, prop.value)
+		}
+	}
+
+	bodySchema, isKnown := bs.Body, bs.Body != nil && len(bs.DependentBody) == 0
+	if len(bs.DependentBody) > 0 {
+		f, diags := hcljson.Parse(raw, c.filename)
+		if diags.HasErrors() {
+			return diags
+		}
+		depSchema, _, ok := decoder.NewBlockSchema(bs).DependentBodySchema(&hcl.Block{
+			Type:   blockType,
+			Labels: labels,
+			Body:   f.Body,
+		})
+		if ok {
+			bodySchema, isKnown = mergeBodySchemas(bs.Body, depSchema), true
+		}
+	}
+
+	sb.WriteString(blockType)
+	for _, label := range labels {
+		sb.WriteString(" " + quotedString(label))
+	}
+	sb.WriteString(" {\n")
+	c.depth++
+	err = c.body(sb, raw, bodySchema, isKnown, blockType)
+	c.depth--
+	if err != nil {
+		return err
+	}
+	sb.WriteString("}\n")
+
+	return nil
+}
+
+// dynamicBlocks writes dynamic blocks, whose content
+// follows the schema of the block type given as the label
+func (c *hclConverter) dynamicBlocks(sb *strings.Builder, raw []byte, bodySchema *schema.BodySchema) error {
+	props, err := jsonProperties(raw)
+	if err != nil {
+		return err
+	}
+	for _, prop := range props {
+		contentSchema := &schema.BlockSchema{}
+		if bs, ok := bodySchema.Blocks[prop.name]; ok {
+			contentSchema = bs.Copy()
+			contentSchema.Labels = nil
+		}
+		dynamicSchema := &schema.BlockSchema{
+			Labels: []*schema.LabelSchema{{Name: "type"}},
+			Body: &schema.BodySchema{
+				Blocks: map[string]*schema.BlockSchema{
+					"content": contentSchema,
+				},
+				Attributes: map[string]*schema.AttributeSchema{
+					"for_each": {},
+					"iterator": {},
+					"labels":   {},
+				},
+			},
+		}
+		err := c.blocks(sb, "dynamic", []string{prop.name}, prop.value, dynamicSchema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mergeBodySchemas(base, dependent *schema.BodySchema) *schema.BodySchema {
+	if base == nil {
+		return dependent
+	}
+	merged := base.Copy()
+	if dependent == nil {
+		return merged
+	}
+	if merged.Attributes == nil {
+		merged.Attributes = make(map[string]*schema.AttributeSchema)
+	}
+	for name, attr := range dependent.Attributes {
+		merged.Attributes[name] = attr
+	}
+	if merged.Blocks == nil {
+		merged.Blocks = make(map[string]*schema.BlockSchema)
+	}
+	for name, block := range dependent.Blocks {
+		merged.Blocks[name] = block
+	}
+	if dependent.AnyAttribute != nil {
+		merged.AnyAttribute = dependent.AnyAttribute
+	}
+	return merged
+}
+
+// expr converts the JSON value into an expression, where strings
+// of static attributes are parsed as expressions
+func (c *hclConverter) expr(raw []byte, static bool) (string, error) {
+	switch jsonKind(raw) {
+	case '"':
+		s, ok := jsonStringValue(raw)
+		if !ok {
+			return "", fmt.Errorf("%s: invalid string %s", c.filename, raw)
+		}
+		return c.stringExpr(s, static), nil
+	case '[':
+		elems, err := jsonElements(raw)
+		if err != nil {
+			return "", err
+		}
+		exprs := make([]string, 0, len(elems))
+		isMultiLine := false
+		for _, elem := range elems {
+			expr, err := c.expr(elem, static)
+			if err != nil {
+				return "", err
+			}
+			isMultiLine = isMultiLine || strings.Contains(expr, "\n")
+			exprs = append(exprs, expr)
+		}
+		if isMultiLine {
+			return "[\n" + strings.Join(exprs, ",\n") + ",\n]", nil
+		}
+		return "[" + strings.Join(exprs, ", ") + "]", nil
+	case '{':
+		props, err := jsonProperties(raw)
+		if err != nil {
+			return "", err
+		}
+		if len(props) == 0 {
+			return "{}", nil
+		}
+		var sb strings.Builder
+		sb.WriteString("{\n")
+		for _, prop := range props {
+			value, err := c.expr(prop.value, static)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(c.objectKey(prop.name, static) + " = " + value + "\n")
+		}
+		sb.WriteString("}")
+		return sb.String(), nil
+	}
+
+	// numbers, booleans and null share the syntax
+	return string(bytes.TrimSpace(raw)), nil
+}
+
+func (c *hclConverter) objectKey(key string, static bool) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	if !static {
+		if expr, ok := wrappedExpr(key); ok {
+			return "(" + expr + ")"
+		}
+		return quotedString(key)
+	}
+	return quotedString(escapeTemplate(key))
+}
+
+func (c *hclConverter) stringExpr(s string, static bool) string {
+	if static {
+		_, diags := hclsyntax.ParseExpression([]byte(s), c.filename, hcl.InitialPos)
+		if !diags.HasErrors() {
+			return s
+		}
+		return quotedString(escapeTemplate(s))
+	}
+
+	if expr, ok := wrappedExpr(s); ok {
+		if strings.Contains(expr, "\n") {
+			return "(" + expr + ")"
+		}
+		return expr
+	}
+
+	return quotedString(s)
+}
+
+// wrappedExpr returns source of the expression if the template
+// consists of a single interpolation sequence
+func wrappedExpr(tpl string) (string, bool) {
+	expr, diags := hclsyntax.ParseTemplate([]byte(tpl), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", false
+	}
+	wrapExpr, ok := expr.(*hclsyntax.TemplateWrapExpr)
+	if !ok {
+		return "", false
+	}
+	rng := wrapExpr.Wrapped.Range()
+	return strings.TrimSpace(tpl[rng.Start.Byte:rng.End.Byte]), true
+}
+
+func heredoc(s string) string {
+	marker := "EOT"
+	for i := 2; ; i++ {
+		conflicts := false
+		for _, line := range strings.Split(s, "\n") {
+			if strings.TrimSpace(line) == marker {
+				conflicts = true
+			}
+		}
+		if !conflicts {
+			break
+		}
+		marker = fmt.Sprintf("EOT%d", i)
+	}
+	return "<<" + marker + "\n" + s + marker
+}
+
+// quotedString quotes the string, keeping any template
+// sequences, which share the syntax with JSON
+func quotedString(s string) string {
+	var sb strings.Builder
+	sb.WriteString(`"`)
+	for _, r := range s {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteString(`"`)
+	return sb.String()
+}
@@ -0,0 +1,415 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/schema"
+)
+
+func TestToJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			"blocks and expressions",
+			`# web server
+resource "aws_instance" "web" {
+  count     = 2
+  ami       = "ami-${var.env}"
+  tags      = { Name = "web", Env = var.env }
+  user_data = <<EOT
+#!/bin/bash
+echo "${var.env}" $${HOME}
+EOT
+
+  ebs_block_device {
+    device_name = "sda"
+  }
+  ebs_block_device {
+    device_name = "sdb"
+  }
+
+  lifecycle {
+    ignore_changes = [tags]
+  }
+  depends_on = [aws_eip.ip]
+}
+
+resource "aws_instance" "db" {
+  ami = var.ami
+}
+
+variable "env" {
+  type    = list(string)
+  default = ["a", 1, true, null]
+}
+
+locals {
+  names    = [for n in var.names : upper(n)]
+  greeting = "%{if var.formal}Hello%{else}Hi%{endif}"
+}
+`,
+			`{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "//": "web server",
+        "count": 2,
+        "ami": "ami-${var.env}",
+        "tags": {
+          "Name": "web",
+          "Env": "${var.env}"
+        },
+        "user_data": "#!/bin/bash\necho \"${var.env}\" $${HOME}\n",
+        "ebs_block_device": [
+          {
+            "device_name": "sda"
+          },
+          {
+            "device_name": "sdb"
+          }
+        ],
+        "lifecycle": {
+          "ignore_changes": ["tags"]
+        },
+        "depends_on": ["aws_eip.ip"]
+      },
+      "db": {
+        "ami": "${var.ami}"
+      }
+    }
+  },
+  "variable": {
+    "env": {
+      "type": "list(string)",
+      "default": ["a", 1, true, null]
+    }
+  },
+  "locals": {
+    "names": "${[for n in var.names : upper(n)]}",
+    "greeting": "${\"%{if var.formal}Hello%{else}Hi%{endif}\"}"
+  }
+}
+`,
+		},
+		{
+			"empty",
+			``,
+			`{}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, dropped, err := ToJSON([]byte(tc.src), "main.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, string(out)); diff != "" {
+				t.Fatalf("unexpected JSON: %s", diff)
+			}
+			if len(dropped) > 0 {
+				t.Fatalf("unexpected dropped comments: %#v", dropped)
+			}
+		})
+	}
+}
+
+func TestToJSON_droppedComments(t *testing.T) {
+	src := `# web server
+resource "aws_instance" "web" {
+  # pinned image
+  ami  = "ami-123"
+  type = "t2.micro" // cheapest
+  /* block
+     comment */
+}
+
+# end of file
+`
+	out, dropped, err := ToJSON([]byte(src), "main.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "//": "web server",
+        "ami": "ami-123",
+        "type": "t2.micro"
+      }
+    }
+  }
+}
+`
+	if diff := cmp.Diff(expected, string(out)); diff != "" {
+		t.Fatalf("unexpected JSON: %s", diff)
+	}
+
+	droppedLines := make([]int, 0)
+	for _, rng := range dropped {
+		droppedLines = append(droppedLines, rng.Start.Line)
+	}
+	expectedLines := []int{3, 5, 6, 10}
+	if diff := cmp.Diff(expectedLines, droppedLines); diff != "" {
+		t.Fatalf("unexpected dropped comments: %s", diff)
+	}
+}
+
+func TestToJSON_invalid(t *testing.T) {
+	_, _, err := ToJSON([]byte(`resource "aws_instance" {`), "main.tf")
+	if err == nil {
+		t.Fatal("expected error for invalid configuration")
+	}
+}
+
+func TestToHCL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			"blocks and expressions",
+			`{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "//": "web server",
+        "count": 2,
+        "ami": "ami-${var.env}",
+        "tags": {
+          "Name": "web",
+          "Env": "${var.env}"
+        },
+        "user_data": "#!/bin/bash\necho \"${var.env}\" $${HOME}\n",
+        "ebs_block_device": [
+          {
+            "device_name": "sda"
+          },
+          {
+            "device_name": "sdb"
+          }
+        ],
+        "lifecycle": {
+          "ignore_changes": ["tags"]
+        },
+        "depends_on": ["aws_eip.ip"]
+      },
+      "db": {
+        "ami": "${var.ami}"
+      }
+    }
+  },
+  "variable": {
+    "env": {
+      "type": "list(string)",
+      "default": ["a", 1, true, null]
+    }
+  },
+  "locals": {
+    "names": "${[for n in var.names : upper(n)]}",
+    "greeting": "${\"%{if var.formal}Hello%{else}Hi%{endif}\"}",
+    "path": "C:\\Temp\t${var.name}"
+  }
+}
+`,
+			`# web server
+resource "aws_instance" "web" {
+  count = 2
+  ami   = "ami-${var.env}"
+  tags = {
+    Name = "web"
+    Env  = var.env
+  }
+  user_data = <<EOT
+#!/bin/bash
+echo "${var.env}" $${HOME}
+EOT
+  ebs_block_device {
+    device_name = "sda"
+  }
+  ebs_block_device {
+    device_name = "sdb"
+  }
+  lifecycle {
+    ignore_changes = [tags]
+  }
+  depends_on = [aws_eip.ip]
+}
+
+resource "aws_instance" "db" {
+  ami = var.ami
+}
+
+variable "env" {
+  type    = list(string)
+  default = ["a", 1, true, null]
+}
+
+locals {
+  names    = [for n in var.names : upper(n)]
+  greeting = "%{if var.formal}Hello%{else}Hi%{endif}"
+  path     = "C:\\Temp\t${var.name}"
+}
+`,
+		},
+		{
+			"dynamic blocks",
+			`{
+  "resource": {
+    "aws_instance": {
+      "web": {
+        "dynamic": {
+          "ebs_block_device": {
+            "for_each": "${var.devices}",
+            "content": {
+              "device_name": "${ebs_block_device.value}"
+            }
+          }
+        }
+      }
+    }
+  }
+}
+`,
+			`resource "aws_instance" "web" {
+  dynamic "ebs_block_device" {
+    for_each = var.devices
+    content {
+      device_name = ebs_block_device.value
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := ToHCL([]byte(tc.src), "main.tf.json", testConvertSchema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, string(out)); diff != "" {
+				t.Fatalf("unexpected HCL: %s", diff)
+			}
+		})
+	}
+}
+
+func TestToHCL_unknownSchema(t *testing.T) {
+	src := `{
+  "resource": {
+    "google_compute_instance": {
+      "vm": {
+        "boot_disk": {
+          "auto_delete": true
+        }
+      }
+    }
+  }
+}
+`
+	_, err := ToHCL([]byte(src), "main.tf.json", testConvertSchema)
+	if err == nil {
+		t.Fatal("expected error for block without known schema")
+	}
+}
+
+func TestToJSON_roundTrip(t *testing.T) {
+	src := `# web server
+resource "aws_instance" "web" {
+  count     = 2
+  ami       = "ami-${var.env}"
+  user_data = <<EOT
+#!/bin/bash
+echo "${var.env}" $${HOME}
+EOT
+  lifecycle {
+    ignore_changes = [tags]
+  }
+}
+`
+	out, _, err := ToJSON([]byte(src), "main.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err = ToHCL(out, "main.tf.json", testConvertSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(src, string(out)); diff != "" {
+		t.Fatalf("unexpected HCL: %s", diff)
+	}
+}
+
+var testConvertSchema = &schema.BodySchema{
+	Blocks: map[string]*schema.BlockSchema{
+		"resource": {
+			Labels: []*schema.LabelSchema{
+				{Name: "type", IsDepKey: true},
+				{Name: "name"},
+			},
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"count":      {},
+					"depends_on": {},
+				},
+				Blocks: map[string]*schema.BlockSchema{
+					"lifecycle": {
+						Body: &schema.BodySchema{
+							Attributes: map[string]*schema.AttributeSchema{
+								"ignore_changes": {},
+							},
+						},
+					},
+				},
+			},
+			DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+				schema.NewSchemaKey(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: "aws_instance"},
+					},
+				}): {
+					Attributes: map[string]*schema.AttributeSchema{
+						"ami":       {},
+						"tags":      {},
+						"user_data": {},
+					},
+					Blocks: map[string]*schema.BlockSchema{
+						"ebs_block_device": {
+							Body: &schema.BodySchema{
+								Attributes: map[string]*schema.AttributeSchema{
+									"device_name": {},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"variable": {
+			Labels: []*schema.LabelSchema{
+				{Name: "name"},
+			},
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"type":    {},
+					"default": {},
+				},
+			},
+		},
+		"locals": {
+			Body: &schema.BodySchema{
+				AnyAttribute: &schema.AttributeSchema{},
+			},
+		},
+	},
+}
@@ -121,6 +121,10 @@ func diffLines(filename string, beforeLines, afterLines source.Lines) filesystem
 				if beforeStart == beforeEnd {
 					line := beforeLines[beforeStart]
 					insertRng = line.Range().Ptr()
+
+					// inserting to the beginning of the line,
+					// which is represented as 0-length range
+					insertRng.End = insertRng.Start
 				} else {
 					for i, line := range beforeLines[beforeStart:beforeEnd] {
 						if i == 0 {
@@ -178,6 +178,27 @@ ccc`,
 				},
 			},
 		},
+		{
+			"line insertion before existing line",
+			`resource "aws_vpc" "name" {
+  attr2 = "two"
+}`,
+			`resource "aws_vpc" "name" {
+  attr1 = "one"
+  attr2 = "two"
+}`,
+			filesystem.DocumentChanges{
+				&fileChange{
+					newText: `  attr1 = "one"
+`,
+					rng: &hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 1, Byte: 28},
+						End:      hcl.Pos{Line: 2, Column: 1, Byte: 28},
+					},
+				},
+			},
+		},
 		{
 			"empty to newline",
 			``,
@@ -0,0 +1,252 @@
+package hcl
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type FoldingRangeKind uint
+
+const (
+	FoldingRangeKindRegion FoldingRangeKind = iota
+	FoldingRangeKindComment
+)
+
+// FoldingRange represents a foldable range of a file.
+//
+// For bracketed regions (blocks, objects, tuples and heredocs)
+// the range spans between the opening and closing token,
+// excluding the tokens themselves.
+type FoldingRange struct {
+	Range hcl.Range
+	Kind  FoldingRangeKind
+}
+
+// FoldingRanges returns all multi-line foldable ranges of the given file
+// ordered by position, i.e. blocks, heredocs, multi-line objects
+// and tuples and runs of consecutive comments
+func FoldingRanges(f *hcl.File, filename string) []FoldingRange {
+	ranges := make([]FoldingRange, 0)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		// JSON
+		ranges = append(ranges, jsonFoldingRanges(f.Bytes, filename)...)
+		sortFoldingRanges(ranges)
+		return ranges
+	}
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch n := node.(type) {
+		case *hclsyntax.Block:
+			ranges = appendRegion(ranges, n.OpenBraceRange, n.CloseBraceRange)
+		case *hclsyntax.ObjectConsExpr:
+			ranges = appendBracketed(ranges, n.SrcRange)
+		case *hclsyntax.TupleConsExpr:
+			ranges = appendBracketed(ranges, n.SrcRange)
+		}
+		return nil
+	})
+
+	tokens, _ := hclsyntax.LexConfig(f.Bytes, filename, hcl.InitialPos)
+	ranges = append(ranges, heredocFoldingRanges(tokens)...)
+	ranges = append(ranges, commentFoldingRanges(tokens)...)
+
+	sortFoldingRanges(ranges)
+	return ranges
+}
+
+func sortFoldingRanges(ranges []FoldingRange) {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].Range.Start.Byte < ranges[j].Range.Start.Byte
+	})
+}
+
+// appendRegion appends range between the end of the opening
+// and start of the closing range, if it spans multiple lines
+func appendRegion(ranges []FoldingRange, open, close hcl.Range) []FoldingRange {
+	if open.End.Line >= close.Start.Line {
+		return ranges
+	}
+	return append(ranges, FoldingRange{
+		Range: hcl.Range{
+			Filename: open.Filename,
+			Start:    open.End,
+			End:      close.Start,
+		},
+		Kind: FoldingRangeKindRegion,
+	})
+}
+
+// appendBracketed appends range of a bracketed expression
+// such as object or tuple, excluding the brackets
+func appendBracketed(ranges []FoldingRange, rng hcl.Range) []FoldingRange {
+	if rng.Start.Line >= rng.End.Line {
+		return ranges
+	}
+
+	open := hcl.Range{
+		Filename: rng.Filename,
+		Start:    rng.Start,
+		End: hcl.Pos{
+			Line:   rng.Start.Line,
+			Column: rng.Start.Column + 1,
+			Byte:   rng.Start.Byte + 1,
+		},
+	}
+	close := hcl.Range{
+		Filename: rng.Filename,
+		Start: hcl.Pos{
+			Line:   rng.End.Line,
+			Column: rng.End.Column - 1,
+			Byte:   rng.End.Byte - 1,
+		},
+		End: rng.End,
+	}
+	return appendRegion(ranges, open, close)
+}
+
+func heredocFoldingRanges(tokens hclsyntax.Tokens) []FoldingRange {
+	ranges := make([]FoldingRange, 0)
+
+	openings := make([]hclsyntax.Token, 0)
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			openings = append(openings, token)
+		case hclsyntax.TokenCHeredoc:
+			if len(openings) == 0 {
+				continue
+			}
+			open := openings[len(openings)-1]
+			openings = openings[:len(openings)-1]
+
+			// opening token includes the trailing newline
+			// and we want to fold from the end of the marker
+			markerLen := len(bytes.TrimRight(open.Bytes, "\r\n"))
+			ranges = append(ranges, FoldingRange{
+				Range: hcl.Range{
+					Filename: open.Range.Filename,
+					Start: hcl.Pos{
+						Line:   open.Range.Start.Line,
+						Column: open.Range.Start.Column + markerLen,
+						Byte:   open.Range.Start.Byte + markerLen,
+					},
+					End: token.Range.Start,
+				},
+				Kind: FoldingRangeKindRegion,
+			})
+		}
+	}
+
+	return ranges
+}
+
+func commentFoldingRanges(tokens hclsyntax.Tokens) []FoldingRange {
+	ranges := make([]FoldingRange, 0)
+
+	var current *hcl.Range
+	lastLine, lastCodeLine := 0, 0
+	flush := func() {
+		if current != nil && current.Start.Line < current.End.Line {
+			ranges = append(ranges, FoldingRange{
+				Range: *current,
+				Kind:  FoldingRangeKindComment,
+			})
+		}
+		current = nil
+	}
+
+	for _, token := range tokens {
+		switch token.Type {
+		case hclsyntax.TokenComment:
+			rng := commentRange(token)
+			if rng.Start.Line == lastCodeLine {
+				// trailing comment after code
+				flush()
+				continue
+			}
+			if current != nil && rng.Start.Line == lastLine+1 {
+				current.End = rng.End
+			} else {
+				flush()
+				current = &rng
+			}
+			lastLine = rng.End.Line
+		case hclsyntax.TokenNewline:
+			// newlines after comments do not break the run
+		default:
+			flush()
+			lastCodeLine = token.Range.End.Line
+		}
+	}
+	flush()
+
+	return ranges
+}
+
+// commentRange returns range of the comment excluding
+// the trailing newline which is part of single-line comments
+func commentRange(token hclsyntax.Token) hcl.Range {
+	rng := token.Range
+	trimmed := bytes.TrimRight(token.Bytes, "\r\n")
+	if len(trimmed) == len(token.Bytes) {
+		return rng
+	}
+
+	rng.End = hcl.Pos{
+		Line:   rng.Start.Line,
+		Column: rng.Start.Column + len(trimmed),
+		Byte:   rng.Start.Byte + len(trimmed),
+	}
+	return rng
+}
+
+// jsonFoldingRanges returns folding ranges of multi-line
+// objects and arrays found in JSON source
+func jsonFoldingRanges(src []byte, filename string) []FoldingRange {
+	ranges := make([]FoldingRange, 0)
+
+	pos := hcl.InitialPos
+	openings := make([]hcl.Range, 0)
+	inString, escaped := false, false
+
+	for i, b := range src {
+		rng := hcl.Range{
+			Filename: filename,
+			Start:    pos,
+			End: hcl.Pos{
+				Line:   pos.Line,
+				Column: pos.Column + 1,
+				Byte:   i + 1,
+			},
+		}
+
+		switch {
+		case inString && escaped:
+			escaped = false
+		case inString && b == '\\':
+			escaped = true
+		case b == '"':
+			inString = !inString
+		case inString:
+		case b == '{' || b == '[':
+			openings = append(openings, rng)
+		case (b == '}' || b == ']') && len(openings) > 0:
+			open := openings[len(openings)-1]
+			openings = openings[:len(openings)-1]
+			ranges = appendRegion(ranges, open, rng)
+		}
+
+		if b == '\n' {
+			pos = hcl.Pos{Line: pos.Line + 1, Column: 1, Byte: i + 1}
+		} else {
+			pos = hcl.Pos{Line: pos.Line, Column: pos.Column + 1, Byte: i + 1}
+		}
+	}
+
+	return ranges
+}
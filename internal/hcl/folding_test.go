@@ -0,0 +1,153 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/json"
+)
+
+func TestFoldingRanges(t *testing.T) {
+	testCases := []struct {
+		name           string
+		filename       string
+		cfg            string
+		expectedRanges []FoldingRange
+	}{
+		{
+			"single-line block",
+			"test.tf",
+			`variable "test" {}
+`,
+			[]FoldingRange{},
+		},
+		{
+			"block with nested object, tuple and heredoc",
+			"test.tf",
+			`resource "aws_instance" "web" {
+  tags = {
+    Name = "web"
+  }
+  subnets = [
+    "a",
+  ]
+  user_data = <<EOT
+echo hello
+EOT
+}
+`,
+			[]FoldingRange{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 32, Byte: 31},
+						End:      hcl.Pos{Line: 11, Column: 1, Byte: 126},
+					},
+				},
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 2, Column: 11, Byte: 42},
+						End:      hcl.Pos{Line: 4, Column: 3, Byte: 62},
+					},
+				},
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 5, Column: 14, Byte: 77},
+						End:      hcl.Pos{Line: 7, Column: 3, Byte: 89},
+					},
+				},
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 8, Column: 20, Byte: 110},
+						End:      hcl.Pos{Line: 10, Column: 1, Byte: 122},
+					},
+				},
+			},
+		},
+		{
+			"comment groups",
+			"test.tf",
+			`# first
+# second
+
+// single
+
+/*
+  block
+*/
+variable "test" {} # trailing
+# after trailing
+`,
+			[]FoldingRange{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+						End:      hcl.Pos{Line: 2, Column: 9, Byte: 16},
+					},
+					Kind: FoldingRangeKindComment,
+				},
+				{
+					Range: hcl.Range{
+						Filename: "test.tf",
+						Start:    hcl.Pos{Line: 6, Column: 1, Byte: 29},
+						End:      hcl.Pos{Line: 8, Column: 3, Byte: 42},
+					},
+					Kind: FoldingRangeKindComment,
+				},
+			},
+		},
+		{
+			"JSON",
+			"test.tf.json",
+			`{
+  "variable": {
+    "test": {}
+  },
+  "locals": { "foo": "{bar" }
+}
+`,
+			[]FoldingRange{
+				{
+					Range: hcl.Range{
+						Filename: "test.tf.json",
+						Start:    hcl.Pos{Line: 1, Column: 2, Byte: 1},
+						End:      hcl.Pos{Line: 6, Column: 1, Byte: 68},
+					},
+				},
+				{
+					Range: hcl.Range{
+						Filename: "test.tf.json",
+						Start:    hcl.Pos{Line: 2, Column: 16, Byte: 17},
+						End:      hcl.Pos{Line: 4, Column: 3, Byte: 35},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var f *hcl.File
+			var diags hcl.Diagnostics
+			if tc.filename == "test.tf.json" {
+				f, diags = json.Parse([]byte(tc.cfg), tc.filename)
+			} else {
+				f, diags = hclsyntax.ParseConfig([]byte(tc.cfg), tc.filename, hcl.InitialPos)
+			}
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+
+			ranges := FoldingRanges(f, tc.filename)
+			if diff := cmp.Diff(tc.expectedRanges, ranges); diff != "" {
+				t.Fatalf("unexpected ranges: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package hcl
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// Format formats the given configuration the same way
+// as terraform fmt would, without the need for Terraform CLI.
+//
+// Aside from the canonical HCL formatting (indentation
+// and alignment) it also unwraps interpolation-only expressions
+// and normalizes legacy (quoted) variable type constraints.
+func Format(src []byte, filename string) ([]byte, error) {
+	_, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	f, diags := hclwrite.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	formatBody(f.Body(), nil)
+
+	return f.Bytes(), nil
+}
+
+func formatBody(body *hclwrite.Body, inBlocks []string) {
+	for name, attr := range body.Attributes() {
+		if len(inBlocks) == 1 && inBlocks[0] == "variable" && name == "type" {
+			body.SetAttributeRaw(name, formatTypeExpr(attr.Expr().BuildTokens(nil)))
+			continue
+		}
+		body.SetAttributeRaw(name, formatValueExpr(attr.Expr().BuildTokens(nil)))
+	}
+
+	for _, block := range body.Blocks() {
+		// normalize labels, e.g. remove any interleaved
+		// inline comments and use quoted label syntax
+		block.SetLabels(block.Labels())
+
+		nestedBlocks := append(inBlocks, block.Type())
+		formatBody(block.Body(), nestedBlocks)
+	}
+}
+
+// formatValueExpr unwraps interpolation-only expressions,
+// such as "${var.foo}" which is equivalent to var.foo
+func formatValueExpr(tokens hclwrite.Tokens) hclwrite.Tokens {
+	if len(tokens) < 5 {
+		// not enough tokens for "${ ... }"
+		return tokens
+	}
+	oQuote := tokens[0]
+	oBrace := tokens[1]
+	cBrace := tokens[len(tokens)-2]
+	cQuote := tokens[len(tokens)-1]
+	if oQuote.Type != hclsyntax.TokenOQuote ||
+		oBrace.Type != hclsyntax.TokenTemplateInterp ||
+		cBrace.Type != hclsyntax.TokenTemplateSeqEnd ||
+		cQuote.Type != hclsyntax.TokenCQuote {
+		return tokens
+	}
+
+	inside := tokens[2 : len(tokens)-2]
+
+	// make sure this is a single interpolation sequence,
+	// i.e. not something like "${foo}${bar}" or "${foo}-bar"
+	quotes := 0
+	for _, token := range inside {
+		switch token.Type {
+		case hclsyntax.TokenOQuote:
+			quotes++
+			continue
+		case hclsyntax.TokenCQuote:
+			quotes--
+			continue
+		}
+		if quotes > 0 {
+			// nested templates are part of a nested expression
+			continue
+		}
+		switch token.Type {
+		case hclsyntax.TokenTemplateInterp,
+			hclsyntax.TokenTemplateSeqEnd,
+			hclsyntax.TokenQuotedLit:
+			return tokens
+		}
+	}
+
+	trimmed := trimNewlines(inside)
+
+	// multi-line expressions (e.g. conditionals) need to be
+	// wrapped in parentheses to remain valid after unwrapping
+	isMultiLine := false
+	hasLeadingParen, hasTrailingParen := false, false
+	for i, token := range trimmed {
+		switch {
+		case i == 0 && token.Type == hclsyntax.TokenOParen:
+			hasLeadingParen = true
+		case token.Type == hclsyntax.TokenNewline:
+			isMultiLine = true
+		case i == len(trimmed)-1 && token.Type == hclsyntax.TokenCParen:
+			hasTrailingParen = true
+		}
+	}
+	if isMultiLine && !(hasLeadingParen && hasTrailingParen) {
+		wrapped := make(hclwrite.Tokens, 0, len(trimmed)+2)
+		wrapped = append(wrapped, &hclwrite.Token{
+			Type:  hclsyntax.TokenOParen,
+			Bytes: []byte("("),
+		})
+		wrapped = append(wrapped, trimmed...)
+		wrapped = append(wrapped, &hclwrite.Token{
+			Type:  hclsyntax.TokenCParen,
+			Bytes: []byte(")"),
+		})
+		return wrapped
+	}
+
+	return trimmed
+}
+
+// formatTypeExpr normalizes legacy type constraints
+// such as "string" or bare collection types (list, map, set)
+func formatTypeExpr(tokens hclwrite.Tokens) hclwrite.Tokens {
+	switch len(tokens) {
+	case 1:
+		kwTok := tokens[0]
+		if kwTok.Type != hclsyntax.TokenIdent {
+			return tokens
+		}
+
+		// collection types without an explicit element type
+		// imply "any" as the element type
+		switch string(kwTok.Bytes) {
+		case "list", "map", "set":
+			return collectionTypeTokens(string(kwTok.Bytes), "any")
+		}
+		return tokens
+
+	case 3:
+		oQuote := tokens[0]
+		strTok := tokens[1]
+		cQuote := tokens[2]
+		if oQuote.Type != hclsyntax.TokenOQuote ||
+			strTok.Type != hclsyntax.TokenQuotedLit ||
+			cQuote.Type != hclsyntax.TokenCQuote {
+			return tokens
+		}
+
+		// Terraform 0.11 and earlier had no "any" type
+		// and converted collection elements to string
+		switch string(strTok.Bytes) {
+		case "string":
+			return hclwrite.Tokens{
+				{
+					Type:  hclsyntax.TokenIdent,
+					Bytes: []byte("string"),
+				},
+			}
+		case "list", "map":
+			return collectionTypeTokens(string(strTok.Bytes), "string")
+		}
+		return tokens
+	}
+
+	return tokens
+}
+
+func collectionTypeTokens(collectionType, elemType string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{
+			Type:  hclsyntax.TokenIdent,
+			Bytes: []byte(collectionType),
+		},
+		{
+			Type:  hclsyntax.TokenOParen,
+			Bytes: []byte("("),
+		},
+		{
+			Type:  hclsyntax.TokenIdent,
+			Bytes: []byte(elemType),
+		},
+		{
+			Type:  hclsyntax.TokenCParen,
+			Bytes: []byte(")"),
+		},
+	}
+}
+
+func trimNewlines(tokens hclwrite.Tokens) hclwrite.Tokens {
+	if len(tokens) == 0 {
+		return nil
+	}
+	var start, end int
+	for start = 0; start < len(tokens); start++ {
+		if tokens[start].Type != hclsyntax.TokenNewline {
+			break
+		}
+	}
+	for end = len(tokens); end > 0; end-- {
+		if tokens[end-1].Type != hclsyntax.TokenNewline {
+			break
+		}
+	}
+	return tokens[start:end]
+}
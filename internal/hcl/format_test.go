@@ -0,0 +1,120 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			"alignment and indentation",
+			`resource "aws_vpc" "name" {
+cidr_block = "sdf"
+  tags = {
+    "key" = "value"
+    sdfasd = 1
+  }
+}
+`,
+			`resource "aws_vpc" "name" {
+  cidr_block = "sdf"
+  tags = {
+    "key"  = "value"
+    sdfasd = 1
+  }
+}
+`,
+		},
+		{
+			"interpolation-only expressions",
+			`locals {
+  a = "${var.foo}"
+  b = "${var.foo}-bar"
+  c = "${var.foo}${var.bar}"
+  d = "${
+    var.enabled ? 1 : 0
+  }"
+  e = "${var.enabled ?
+  1 : 0}"
+}
+`,
+			`locals {
+  a = var.foo
+  b = "${var.foo}-bar"
+  c = "${var.foo}${var.bar}"
+  d = var.enabled ? 1 : 0
+  e = (var.enabled ?
+  1 : 0)
+}
+`,
+		},
+		{
+			"legacy variable types",
+			`variable "a" {
+  type = "string"
+}
+variable "b" {
+  type = "list"
+}
+variable "c" {
+  type = map
+}
+`,
+			`variable "a" {
+  type = string
+}
+variable "b" {
+  type = list(string)
+}
+variable "c" {
+  type = map(any)
+}
+`,
+		},
+		{
+			"types outside of variables",
+			`locals {
+  type = "string"
+}
+`,
+			`locals {
+  type = "string"
+}
+`,
+		},
+		{
+			"block labels",
+			`resource aws_instance "web" {
+}
+`,
+			`resource "aws_instance" "web" {
+}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted, err := Format([]byte(tc.src), "test.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, string(formatted)); diff != "" {
+				t.Fatalf("unexpected output: %s", diff)
+			}
+		})
+	}
+}
+
+func TestFormat_invalid(t *testing.T) {
+	_, err := Format([]byte(`resource "aws_instance" "web" {`), "test.tf")
+	if err == nil {
+		t.Fatal("expected invalid configuration to return error")
+	}
+}
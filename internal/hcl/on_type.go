@@ -0,0 +1,220 @@
+package hcl
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// FormatEnclosingBlock formats the innermost block enclosing pos
+// and returns the whole source with only that block formatted,
+// leaving the rest of the source intact. If the innermost block
+// is not closed yet, only the line at pos is formatted.
+//
+// Unlike Format this works with incomplete configuration as it is
+// being typed. If the line at pos is blank (e.g. after newline was
+// typed) it is indented according to its nesting level.
+func FormatEnclosingBlock(src []byte, filename string, pos hcl.Pos) ([]byte, bool) {
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+
+	startByte, endByte, ok := enclosingRegion(tokens, src, pos)
+	if !ok {
+		return nil, false
+	}
+
+	// expand the region to whole lines
+	startByte = bytes.LastIndexByte(src[:startByte], '\n') + 1
+	if idx := bytes.IndexByte(src[endByte:], '\n'); idx >= 0 {
+		endByte += idx + 1
+	} else {
+		endByte = len(src)
+	}
+
+	// the whole region is formatted to get indentation right,
+	// which doesn't add or remove any lines
+	original := src[startByte:endByte]
+	formatted := hclwrite.Format(original)
+
+	if !isInHeredoc(tokens, pos) {
+		lineIdx := bytes.Count(src[startByte:pos.Byte], []byte{'\n'})
+		formatted = indentBlankLine(formatted, lineIdx, nestingLevel(tokens, startByte, pos))
+	}
+
+	originalLines := bytes.SplitAfter(original, []byte{'\n'})
+	formattedLines := bytes.SplitAfter(formatted, []byte{'\n'})
+	if len(originalLines) != len(formattedLines) {
+		return nil, false
+	}
+
+	// only lines of the innermost block are replaced
+	firstLine := bytes.Count(src[startByte:pos.Byte], []byte{'\n'})
+	lastLine := firstLine
+	if blockStart, blockEnd, ok := innermostBlock(tokens, pos); ok {
+		firstLine = bytes.Count(src[startByte:blockStart], []byte{'\n'})
+		lastLine = bytes.Count(src[startByte:blockEnd], []byte{'\n'})
+	}
+
+	result := make([]byte, 0, len(src))
+	result = append(result, src[:startByte]...)
+	for i, line := range originalLines {
+		if i >= firstLine && i <= lastLine {
+			line = formattedLines[i]
+		}
+		result = append(result, line...)
+	}
+	result = append(result, src[endByte:]...)
+
+	return result, true
+}
+
+// enclosingRegion returns byte offsets of the outermost pair
+// of brackets enclosing pos (including the closing bracket
+// which was just typed). Unclosed regions span until EOF.
+func enclosingRegion(tokens hclsyntax.Tokens, src []byte, pos hcl.Pos) (int, int, bool) {
+	depth, openByte := 0, 0
+	for _, token := range tokens {
+		switch {
+		case isOpeningToken(token.Type):
+			if depth == 0 {
+				openByte = token.Range.Start.Byte
+			}
+			depth++
+		case isClosingToken(token.Type):
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 && openByte < pos.Byte && pos.Byte <= token.Range.End.Byte {
+				return openByte, token.Range.End.Byte, true
+			}
+		}
+	}
+
+	if depth > 0 && openByte < pos.Byte {
+		return openByte, len(src), true
+	}
+
+	return 0, 0, false
+}
+
+// innermostBlock returns byte offsets of the innermost pair
+// of braces enclosing pos. It returns false if there is no such
+// pair or if the innermost opening brace is not closed yet.
+func innermostBlock(tokens hclsyntax.Tokens, pos hcl.Pos) (int, int, bool) {
+	opened := make([]hclsyntax.Token, 0)
+	startByte, endByte := -1, -1
+	for _, token := range tokens {
+		switch {
+		case isOpeningToken(token.Type):
+			opened = append(opened, token)
+		case isClosingToken(token.Type):
+			if len(opened) == 0 {
+				continue
+			}
+			open := opened[len(opened)-1]
+			opened = opened[:len(opened)-1]
+
+			if open.Type == hclsyntax.TokenOBrace &&
+				open.Range.Start.Byte < pos.Byte &&
+				pos.Byte <= token.Range.End.Byte &&
+				open.Range.Start.Byte > startByte {
+				startByte, endByte = open.Range.Start.Byte, token.Range.End.Byte
+			}
+		}
+	}
+
+	for _, open := range opened {
+		if open.Type == hclsyntax.TokenOBrace &&
+			open.Range.Start.Byte < pos.Byte &&
+			open.Range.Start.Byte > startByte {
+			return 0, 0, false
+		}
+	}
+
+	if startByte < 0 {
+		return 0, 0, false
+	}
+
+	return startByte, endByte, true
+}
+
+// nestingLevel returns number of brackets opened
+// between startByte and pos
+func nestingLevel(tokens hclsyntax.Tokens, startByte int, pos hcl.Pos) int {
+	level := 0
+	for _, token := range tokens {
+		if token.Range.Start.Byte < startByte {
+			continue
+		}
+		if token.Range.End.Byte > pos.Byte {
+			break
+		}
+		switch {
+		case isOpeningToken(token.Type):
+			level++
+		case isClosingToken(token.Type) && level > 0:
+			level--
+		}
+	}
+	return level
+}
+
+func isInHeredoc(tokens hclsyntax.Tokens, pos hcl.Pos) bool {
+	inHeredoc := false
+	for _, token := range tokens {
+		if token.Range.Start.Byte >= pos.Byte {
+			break
+		}
+		switch token.Type {
+		case hclsyntax.TokenOHeredoc:
+			inHeredoc = true
+		case hclsyntax.TokenCHeredoc:
+			inHeredoc = false
+		}
+	}
+	return inHeredoc
+}
+
+// indentBlankLine indents line at the given index
+// if the line contains only whitespace
+func indentBlankLine(src []byte, lineIdx, level int) []byte {
+	lines := bytes.SplitAfter(src, []byte{'\n'})
+	if lineIdx >= len(lines) {
+		return src
+	}
+
+	line := lines[lineIdx]
+	if len(bytes.TrimSpace(line)) > 0 {
+		return src
+	}
+
+	lineEnd := line[len(bytes.TrimRight(line, "\r\n")):]
+	lines[lineIdx] = append(bytes.Repeat([]byte("  "), level), lineEnd...)
+
+	return bytes.Join(lines, nil)
+}
+
+func isOpeningToken(tokType hclsyntax.TokenType) bool {
+	switch tokType {
+	case hclsyntax.TokenOBrace,
+		hclsyntax.TokenOBrack,
+		hclsyntax.TokenOParen,
+		hclsyntax.TokenTemplateInterp,
+		hclsyntax.TokenTemplateControl:
+		return true
+	}
+	return false
+}
+
+func isClosingToken(tokType hclsyntax.TokenType) bool {
+	switch tokType {
+	case hclsyntax.TokenCBrace,
+		hclsyntax.TokenCBrack,
+		hclsyntax.TokenCParen,
+		hclsyntax.TokenTemplateSeqEnd:
+		return true
+	}
+	return false
+}
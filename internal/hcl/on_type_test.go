@@ -0,0 +1,153 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestFormatEnclosingBlock(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		pos        hcl.Pos
+		expectedOk bool
+		expected   string
+	}{
+		{
+			"newline in block",
+			`locals {
+  a = 1
+  bbb = 2
+
+}
+
+variable "x" {
+default = 1
+}
+`,
+			hcl.Pos{Line: 4, Column: 1, Byte: 27},
+			true,
+			`locals {
+  a   = 1
+  bbb = 2
+  
+}
+
+variable "x" {
+default = 1
+}
+`,
+		},
+		{
+			"newline in nested incomplete block",
+			`resource "aws_instance" "web" {
+  ebs_block_device {
+  device_name = "sda"
+
+`,
+			hcl.Pos{Line: 4, Column: 1, Byte: 75},
+			true,
+			`resource "aws_instance" "web" {
+  ebs_block_device {
+  device_name = "sda"
+    
+`,
+		},
+		{
+			"newline after unclosed brace in the middle",
+			`variable "x" {
+
+output "y" {
+value = 1
+}
+`,
+			hcl.Pos{Line: 2, Column: 1, Byte: 15},
+			true,
+			`variable "x" {
+  
+output "y" {
+value = 1
+}
+`,
+		},
+		{
+			"closing brace of nested block",
+			`resource "aws_instance" "web" {
+ami = "ami-123"
+  ebs_block_device {
+  device_name = "sda"
+      }
+}
+`,
+			hcl.Pos{Line: 5, Column: 8, Byte: 98},
+			true,
+			`resource "aws_instance" "web" {
+ami = "ami-123"
+  ebs_block_device {
+    device_name = "sda"
+  }
+}
+`,
+		},
+		{
+			"closing brace",
+			`locals {
+a = 1
+    bbb = 2
+    }
+`,
+			hcl.Pos{Line: 4, Column: 6, Byte: 32},
+			true,
+			`locals {
+  a   = 1
+  bbb = 2
+}
+`,
+		},
+		{
+			"heredoc",
+			`locals {
+  a = <<EOT
+foo
+
+EOT
+}
+`,
+			hcl.Pos{Line: 4, Column: 1, Byte: 25},
+			true,
+			`locals {
+  a = <<EOT
+foo
+
+EOT
+}
+`,
+		},
+		{
+			"outside of block",
+			`a = 1
+
+`,
+			hcl.Pos{Line: 2, Column: 1, Byte: 6},
+			false,
+			``,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			formatted, ok := FormatEnclosingBlock([]byte(tc.src), "test.tf", tc.pos)
+			if ok != tc.expectedOk {
+				t.Fatalf("expected ok: %t, given: %t", tc.expectedOk, ok)
+			}
+			if !ok {
+				return
+			}
+			if diff := cmp.Diff(tc.expected, string(formatted)); diff != "" {
+				t.Fatalf("unexpected output: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package hcl
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// SelectionRanges returns ranges of all syntax nodes enclosing
+// the given position, ordered from the innermost to the outermost,
+// i.e. identifier, traversal, expression(s), attribute, block body,
+// block and finally the whole file.
+func SelectionRanges(f *hcl.File, filename string, pos hcl.Pos) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+
+	body, ok := f.Body.(*hclsyntax.Body)
+	if ok {
+		hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+			if !rangeContainsPos(node.Range(), pos) {
+				return nil
+			}
+			ranges = append(ranges, node.Range())
+
+			switch n := node.(type) {
+			case *hclsyntax.Attribute:
+				ranges = append(ranges, n.NameRange)
+			case *hclsyntax.Block:
+				ranges = append(ranges, n.TypeRange)
+				ranges = append(ranges, n.LabelRanges...)
+			case *hclsyntax.ObjectConsExpr:
+				for _, item := range n.Items {
+					ranges = append(ranges, hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range()))
+				}
+			case *hclsyntax.ScopeTraversalExpr:
+				ranges = append(ranges, traversalRanges(n.Traversal)...)
+			case *hclsyntax.RelativeTraversalExpr:
+				ranges = append(ranges, traversalRanges(n.Traversal)...)
+			}
+			return nil
+		})
+	}
+
+	ranges = append(ranges, fileRange(f.Bytes, filename))
+
+	return nestedRangesAtPos(ranges, pos)
+}
+
+// nestedRangesAtPos filters out ranges which do not contain pos
+// and sorts the rest by size
+func nestedRangesAtPos(ranges []hcl.Range, pos hcl.Pos) []hcl.Range {
+	matching := make([]hcl.Range, 0)
+	for _, rng := range ranges {
+		if !rangeContainsPos(rng, pos) {
+			continue
+		}
+		if rangesContain(matching, rng) {
+			continue
+		}
+		matching = append(matching, rng)
+	}
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].End.Byte-matching[i].Start.Byte <
+			matching[j].End.Byte-matching[j].Start.Byte
+	})
+
+	// each range must contain the previous one
+	nested := make([]hcl.Range, 0, len(matching))
+	for _, rng := range matching {
+		if len(nested) > 0 {
+			prev := nested[len(nested)-1]
+			if rng.Start.Byte > prev.Start.Byte || rng.End.Byte < prev.End.Byte {
+				continue
+			}
+		}
+		nested = append(nested, rng)
+	}
+
+	return nested
+}
+
+func rangesContain(ranges []hcl.Range, rng hcl.Range) bool {
+	for _, r := range ranges {
+		if r.Start.Byte == rng.Start.Byte && r.End.Byte == rng.End.Byte {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeContainsPos reports whether the range contains pos
+// including the end position, so that an identifier is selectable
+// when the cursor is placed right after it
+func rangeContainsPos(rng hcl.Range, pos hcl.Pos) bool {
+	return pos.Byte >= rng.Start.Byte && pos.Byte <= rng.End.Byte
+}
+
+// traversalRanges returns ranges of individual step names,
+// excluding any leading dots
+func traversalRanges(traversal hcl.Traversal) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			ranges = append(ranges, s.SrcRange)
+		case hcl.TraverseAttr:
+			rng := s.SrcRange
+			if rng.End.Byte-rng.Start.Byte == len(s.Name)+1 {
+				rng.Start.Byte++
+				rng.Start.Column++
+			}
+			ranges = append(ranges, rng)
+		default:
+			ranges = append(ranges, step.SourceRange())
+		}
+	}
+	return ranges
+}
+
+func fileRange(src []byte, filename string) hcl.Range {
+	end := hcl.Pos{
+		Line:   bytes.Count(src, []byte{'\n'}) + 1,
+		Column: 1,
+		Byte:   len(src),
+	}
+	lastLine := src[bytes.LastIndexByte(src, '\n')+1:]
+	end.Column += utf8.RuneCount(lastLine)
+
+	return hcl.Range{
+		Filename: filename,
+		Start:    hcl.InitialPos,
+		End:      end,
+	}
+}
@@ -0,0 +1,99 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestSelectionRanges(t *testing.T) {
+	cfg := `resource "aws_instance" "web" {
+  ami = var.ami_id
+}
+`
+	f, diags := hclsyntax.ParseConfig([]byte(cfg), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	testCases := []struct {
+		name           string
+		pos            hcl.Pos
+		expectedRanges []hcl.Range
+	}{
+		{
+			"traversal step",
+			hcl.Pos{Line: 2, Column: 14, Byte: 45},
+			[]hcl.Range{
+				// ami_id
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 13, Byte: 44},
+					End:      hcl.Pos{Line: 2, Column: 19, Byte: 50},
+				},
+				// var.ami_id
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 9, Byte: 40},
+					End:      hcl.Pos{Line: 2, Column: 19, Byte: 50},
+				},
+				// attribute
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 2, Column: 3, Byte: 34},
+					End:      hcl.Pos{Line: 2, Column: 19, Byte: 50},
+				},
+				// block body
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 31, Byte: 30},
+					End:      hcl.Pos{Line: 3, Column: 2, Byte: 52},
+				},
+				// block
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 3, Column: 2, Byte: 52},
+				},
+				// file
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 4, Column: 1, Byte: 53},
+				},
+			},
+		},
+		{
+			"block label",
+			hcl.Pos{Line: 1, Column: 27, Byte: 26},
+			[]hcl.Range{
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 25, Byte: 24},
+					End:      hcl.Pos{Line: 1, Column: 30, Byte: 29},
+				},
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 3, Column: 2, Byte: 52},
+				},
+				{
+					Filename: "test.tf",
+					Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+					End:      hcl.Pos{Line: 4, Column: 1, Byte: 53},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ranges := SelectionRanges(f, "test.tf", tc.pos)
+			if diff := cmp.Diff(tc.expectedRanges, ranges); diff != "" {
+				t.Fatalf("unexpected ranges: %s", diff)
+			}
+		})
+	}
+}
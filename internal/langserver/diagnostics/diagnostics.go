@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"path/filepath"
+	"sort"
 	"sync"
 
 	"github.com/creachadair/jrpc2"
@@ -11,6 +12,7 @@ import (
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
 type diagContext struct {
@@ -28,6 +30,9 @@ type Notifier struct {
 	sessCtx        context.Context
 	diags          chan diagContext
 	closeDiagsOnce sync.Once
+
+	pullMu *sync.RWMutex
+	pull   bool
 }
 
 func NewNotifier(sessCtx context.Context, logger *log.Logger) *Notifier {
@@ -35,13 +40,38 @@ func NewNotifier(sessCtx context.Context, logger *log.Logger) *Notifier {
 		logger:  logger,
 		sessCtx: sessCtx,
 		diags:   make(chan diagContext, 50),
+		pullMu:  &sync.RWMutex{},
 	}
 	go n.notify()
 	return n
 }
 
+// pulledSources are sources of diagnostics which clients supporting
+// pull diagnostics obtain via textDocument/diagnostic instead
+var pulledSources = map[DiagnosticSource]bool{
+	"HCL":             true,
+	validation.Source: true,
+}
+
+// SetPullDiagnostics makes the notifier leave out diagnostics
+// which the client pulls, so that these aren't reported twice
+func (n *Notifier) SetPullDiagnostics(pull bool) {
+	n.pullMu.Lock()
+	defer n.pullMu.Unlock()
+	n.pull = pull
+}
+
+func (n *Notifier) pullDiagnostics() bool {
+	n.pullMu.RLock()
+	defer n.pullMu.RUnlock()
+	return n.pull
+}
+
 // PublishHCLDiags accepts a map of HCL diagnostics per file and queues them for publishing.
 // A dir path is passed which is joined with the filename keys of the map, to form a file URI.
+//
+// Diagnostics pulled by the client are not published, and neither
+// are files which have no diagnostics from any other source.
 func (n *Notifier) PublishHCLDiags(ctx context.Context, dirPath string, diags Diagnostics) {
 	select {
 	case <-n.sessCtx.Done():
@@ -52,10 +82,19 @@ func (n *Notifier) PublishHCLDiags(ctx context.Context, dirPath string, diags Di
 	default:
 	}
 
-	for filename, ds := range diags {
+	pull := n.pullDiagnostics()
+	for filename, ds := range diags.files {
+		published := false
 		fileDiags := make([]lsp.Diagnostic, 0)
-		for source, diags := range ds {
-			fileDiags = append(fileDiags, ilsp.HCLDiagsToLSP(diags, string(source))...)
+		for source, sourceDiags := range ds {
+			if pull && pulledSources[source] {
+				continue
+			}
+			published = true
+			fileDiags = append(fileDiags, diags.toLSP(sourceDiags, source)...)
+		}
+		if pull && !published {
+			continue
 		}
 
 		n.diags <- diagContext{
@@ -77,26 +116,90 @@ func (n *Notifier) notify() {
 	}
 }
 
-type Diagnostics map[string]map[DiagnosticSource]hcl.Diagnostics
+// Diagnostics represents HCL diagnostics per file and source,
+// along with codes of problems found by validation
+type Diagnostics struct {
+	files map[string]map[DiagnosticSource]hcl.Diagnostics
+	codes map[*hcl.Diagnostic]validation.Code
+}
 
 func NewDiagnostics() Diagnostics {
-	return make(Diagnostics, 0)
+	return Diagnostics{
+		files: make(map[string]map[DiagnosticSource]hcl.Diagnostics, 0),
+		codes: make(map[*hcl.Diagnostic]validation.Code, 0),
+	}
 }
 
 // EmptyRootDiagnostic allows emptying any diagnostics for
 // the whole directory which were published previously.
 func (d Diagnostics) EmptyRootDiagnostic() Diagnostics {
-	d[""] = make(map[DiagnosticSource]hcl.Diagnostics, 0)
+	d.files[""] = make(map[DiagnosticSource]hcl.Diagnostics, 0)
 	return d
 }
 
 func (d Diagnostics) Append(src string, diagsMap map[string]hcl.Diagnostics) Diagnostics {
 	for uri, uriDiags := range diagsMap {
-		if _, ok := d[uri]; !ok {
-			d[uri] = make(map[DiagnosticSource]hcl.Diagnostics, 0)
+		if _, ok := d.files[uri]; !ok {
+			d.files[uri] = make(map[DiagnosticSource]hcl.Diagnostics, 0)
 		}
-		d[uri][DiagnosticSource(src)] = uriDiags
+		d.files[uri][DiagnosticSource(src)] = uriDiags
 	}
 
 	return d
 }
+
+// AppendProblems appends diagnostics of problems found by validation,
+// keeping their codes for conversion to LSP
+func (d Diagnostics) AppendProblems(problems []validation.Problem) Diagnostics {
+	for _, p := range problems {
+		d.codes[p.Diagnostic] = p.Code
+	}
+	return d.Append(validation.Source, validation.Diagnostics(problems))
+}
+
+// Filenames returns sorted names of files with diagnostics
+func (d Diagnostics) Filenames() []string {
+	filenames := make([]string, 0, len(d.files))
+	for filename := range d.files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+	return filenames
+}
+
+// ForFile returns diagnostics of the given file converted to LSP,
+// ordered by source so that the result is stable across calls
+func (d Diagnostics) ForFile(filename string) []lsp.Diagnostic {
+	ds := d.files[filename]
+
+	sources := make([]string, 0, len(ds))
+	for source := range ds {
+		sources = append(sources, string(source))
+	}
+	sort.Strings(sources)
+
+	fileDiags := make([]lsp.Diagnostic, 0)
+	for _, source := range sources {
+		fileDiags = append(fileDiags, d.toLSP(ds[DiagnosticSource(source)], DiagnosticSource(source))...)
+	}
+	return fileDiags
+}
+
+// toLSP converts diagnostics, including codes
+// of problems found by validation and tags of deprecations
+func (d Diagnostics) toLSP(diags hcl.Diagnostics, source DiagnosticSource) []lsp.Diagnostic {
+	lspDiags := ilsp.HCLDiagsToLSP(diags, string(source))
+	if source != validation.Source {
+		return lspDiags
+	}
+
+	for i, diag := range diags {
+		if code, ok := d.codes[diag]; ok {
+			lspDiags[i].Code = string(code)
+			if code.IsDeprecation() {
+				lspDiags[i].Tags = []lsp.DiagnosticTag{lsp.Deprecated}
+			}
+		}
+	}
+	return lspDiags
+}
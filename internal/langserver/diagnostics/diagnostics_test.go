@@ -4,10 +4,13 @@ import (
 	"context"
 	"io/ioutil"
 	"log"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
 var discardLogger = log.New(ioutil.Discard, "", 0)
@@ -58,6 +61,55 @@ func TestPublish_DoesNotSendAfterClose(t *testing.T) {
 	n.PublishHCLDiags(context.Background(), t.TempDir(), diags)
 }
 
+func TestPublish_pullDiagnostics(t *testing.T) {
+	n := &Notifier{
+		logger:  discardLogger,
+		sessCtx: context.Background(),
+		diags:   make(chan diagContext, 50),
+		pullMu:  &sync.RWMutex{},
+	}
+	n.SetPullDiagnostics(true)
+
+	diags := NewDiagnostics()
+	diags.EmptyRootDiagnostic()
+	diags.Append("HCL", map[string]hcl.Diagnostics{
+		"main.tf": {
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Something went wrong",
+			},
+		},
+	})
+	n.PublishHCLDiags(context.Background(), "/test", diags)
+	if len(n.diags) != 0 {
+		t.Fatalf("expected no diagnostics to be published, %d given", len(n.diags))
+	}
+
+	diags.Append("terraform validate", map[string]hcl.Diagnostics{
+		"main.tf": {
+			{
+				Severity: hcl.DiagWarning,
+				Summary:  "Beware",
+			},
+		},
+	})
+	n.PublishHCLDiags(context.Background(), "/test", diags)
+	if len(n.diags) != 1 {
+		t.Fatalf("expected diagnostics of one file to be published, %d given", len(n.diags))
+	}
+
+	expectedDiags := []lsp.Diagnostic{
+		{
+			Severity: lsp.SeverityWarning,
+			Source:   "terraform validate",
+			Message:  "Beware",
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, (<-n.diags).diags); diff != "" {
+		t.Fatalf("unexpected published diagnostics: %s", diff)
+	}
+}
+
 func TestDiagnostics_Append(t *testing.T) {
 	diags := NewDiagnostics()
 	diags.Append("foo", map[string]hcl.Diagnostics{
@@ -94,7 +146,7 @@ func TestDiagnostics_Append(t *testing.T) {
 		},
 	})
 
-	expectedDiags := Diagnostics{
+	expectedDiags := map[string]map[DiagnosticSource]hcl.Diagnostics{
 		"first.tf": map[DiagnosticSource]hcl.Diagnostics{
 			DiagnosticSource("foo"): {
 				&hcl.Diagnostic{
@@ -129,7 +181,48 @@ func TestDiagnostics_Append(t *testing.T) {
 			},
 		},
 	}
-	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+	if diff := cmp.Diff(expectedDiags, diags.files); diff != "" {
+		t.Fatalf("diagnostics mismatch: %s", diff)
+	}
+}
+
+func TestDiagnostics_ForFile_validation(t *testing.T) {
+	diags := NewDiagnostics()
+	diags.AppendProblems([]validation.Problem{
+		{
+			Code:     validation.UndeclaredVariable,
+			Filename: "main.tf",
+			Diagnostic: &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Reference to undeclared input variable",
+			},
+		},
+		{
+			Code:     validation.InterpolationOnlyExpression,
+			Filename: "main.tf",
+			Diagnostic: &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Interpolation-only expressions are deprecated",
+			},
+		},
+	})
+
+	expectedDiags := []lsp.Diagnostic{
+		{
+			Severity: lsp.SeverityError,
+			Code:     "undeclared-variable",
+			Source:   validation.Source,
+			Message:  "Reference to undeclared input variable",
+		},
+		{
+			Severity: lsp.SeverityWarning,
+			Code:     "interpolation-only-expression",
+			Source:   validation.Source,
+			Message:  "Interpolation-only expressions are deprecated",
+			Tags:     []lsp.DiagnosticTag{lsp.Deprecated},
+		},
+	}
+	if diff := cmp.Diff(expectedDiags, diags.ForFile("main.tf")); diff != "" {
 		t.Fatalf("diagnostics mismatch: %s", diff)
 	}
 }
@@ -49,3 +49,19 @@ func HCLDiagsFromJSON(jsonDiags []tfjson.Diagnostic) map[string]hcl.Diagnostics
 
 	return diagsMap
 }
+
+// ValidateDiagsFromJSON converts diagnostics of terraform validate
+// and adds empty diagnostics for any of the given module files
+// without problems, so that results of any previous validation
+// are cleared for these files once published
+func ValidateDiagsFromJSON(jsonDiags []tfjson.Diagnostic, filenames []string) map[string]hcl.Diagnostics {
+	diagsMap := HCLDiagsFromJSON(jsonDiags)
+
+	for _, filename := range filenames {
+		if _, ok := diagsMap[filename]; !ok {
+			diagsMap[filename] = hcl.Diagnostics{}
+		}
+	}
+
+	return diagsMap
+}
@@ -0,0 +1,45 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+func TestValidateDiagsFromJSON(t *testing.T) {
+	jsonDiags := []tfjson.Diagnostic{
+		{
+			Severity: "error",
+			Summary:  "Unsupported argument",
+			Range: &tfjson.Range{
+				Filename: "main.tf",
+				Start:    tfjson.Pos{Line: 2, Column: 3, Byte: 20},
+				End:      tfjson.Pos{Line: 2, Column: 7, Byte: 24},
+			},
+		},
+	}
+
+	// variables.tf had problems in previous validation,
+	// which need to be cleared once published
+	diags := ValidateDiagsFromJSON(jsonDiags, []string{"main.tf", "variables.tf"})
+
+	expectedDiags := map[string]hcl.Diagnostics{
+		"main.tf": {
+			{
+				Severity: hcl.DiagError,
+				Summary:  "Unsupported argument",
+				Subject: &hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 2, Column: 3, Byte: 20},
+					End:      hcl.Pos{Line: 2, Column: 7, Byte: 24},
+				},
+			},
+		},
+		"variables.tf": {},
+	}
+	if diff := cmp.Diff(expectedDiags, diags); diff != "" {
+		t.Fatalf("unexpected diagnostics: %s", diff)
+	}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/creachadair/jrpc2/code"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+func (h *logHandler) TextDocumentPrepareCallHierarchy(ctx context.Context, params lsp.CallHierarchyPrepareParams) ([]lsp.CallHierarchyItem, error) {
+	items := make([]lsp.CallHierarchyItem, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return items, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return items, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return items, err
+	}
+
+	fPos, err := ilsp.FilePositionFromDocumentPosition(params.TextDocumentPositionParams, file)
+	if err != nil {
+		return items, err
+	}
+
+	call, ok, err := refactor.ModuleCallAtPos(mf, file.Dir(), fPos.Filename(), fPos.Position())
+	if err != nil {
+		return items, err
+	}
+	if !ok {
+		return items, nil
+	}
+
+	return append(items, callHierarchyItem(call)), nil
+}
+
+func (h *logHandler) CallHierarchyIncomingCalls(ctx context.Context, params lsp.CallHierarchyIncomingCallsParams) ([]lsp.CallHierarchyIncomingCall, error) {
+	calls := make([]lsp.CallHierarchyIncomingCall, 0)
+
+	mm, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return calls, err
+	}
+
+	call, err := h.moduleCallForItem(mm, params.Item)
+	if err != nil {
+		return calls, err
+	}
+
+	err = loadCallersOfModule(mm, call.CallerPath)
+	if err != nil {
+		return calls, err
+	}
+
+	incoming, err := refactor.IncomingModuleCalls(mm, call)
+	if err != nil {
+		return calls, err
+	}
+
+	for _, c := range incoming {
+		calls = append(calls, lsp.CallHierarchyIncomingCall{
+			From:       callHierarchyItem(c),
+			FromRanges: []lsp.Range{ilsp.HCLRangeToLSP(c.NameRange)},
+		})
+	}
+
+	return calls, nil
+}
+
+func (h *logHandler) CallHierarchyOutgoingCalls(ctx context.Context, params lsp.CallHierarchyOutgoingCallsParams) ([]lsp.CallHierarchyOutgoingCall, error) {
+	calls := make([]lsp.CallHierarchyOutgoingCall, 0)
+
+	mm, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return calls, err
+	}
+
+	call, err := h.moduleCallForItem(mm, params.Item)
+	if err != nil {
+		return calls, err
+	}
+
+	if call.Path == "" {
+		// module is either not installed or not local
+		return calls, nil
+	}
+
+	err = loadModuleConfiguration(mm, call.Path)
+	if err != nil {
+		return calls, err
+	}
+
+	outgoing, err := refactor.OutgoingModuleCalls(mm, call)
+	if err != nil {
+		return calls, err
+	}
+
+	for _, c := range outgoing {
+		calls = append(calls, lsp.CallHierarchyOutgoingCall{
+			To:         callHierarchyItem(c),
+			FromRanges: []lsp.Range{params.Item.SelectionRange},
+		})
+	}
+
+	return calls, nil
+}
+
+// moduleCallForItem returns module call represented by the item
+func (h *logHandler) moduleCallForItem(mf module.ModuleFinder, item lsp.CallHierarchyItem) (*refactor.ModuleCall, error) {
+	data, err := ilsp.DecodeCallHierarchyItemData(item.Data)
+	if err != nil {
+		return nil, code.InvalidParams.Err()
+	}
+
+	modPath, err := uri.PathFromURI(data.ModuleURI)
+	if err != nil {
+		return nil, code.InvalidParams.Err()
+	}
+
+	call, ok, err := refactor.ModuleCallByName(mf, modPath, data.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		h.logger.Printf("module call %q no longer found in %s", data.Name, modPath)
+		return nil, code.InvalidParams.Err()
+	}
+
+	return call, nil
+}
+
+func callHierarchyItem(call *refactor.ModuleCall) lsp.CallHierarchyItem {
+	return lsp.CallHierarchyItem{
+		Name:           call.Name,
+		Kind:           lsp.Module,
+		Detail:         call.SourceAddr,
+		URI:            lsp.DocumentURI(uri.FromPath(filepath.Join(call.CallerPath, call.Range.Filename))),
+		Range:          ilsp.HCLRangeToLSP(call.Range),
+		SelectionRange: ilsp.HCLRangeToLSP(call.NameRange),
+		Data: ilsp.CallHierarchyItemData{
+			ModuleURI: uri.FromPath(call.CallerPath),
+			Name:      call.Name,
+		},
+	}
+}
+
+// loadModuleConfiguration ensures that configuration of the module
+// is parsed, since modules outside of the workspace may not have
+// been loaded yet
+func loadModuleConfiguration(mm module.ModuleManager, modPath string) error {
+	mod, err := mm.ModuleByPath(modPath)
+	if err != nil {
+		if !module.IsModuleNotFound(err) {
+			return err
+		}
+		mod, err = mm.AddModule(modPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if mod.ModuleParsingState == op.OpStateLoaded {
+		return nil
+	}
+
+	return mm.EnqueueModuleOpWait(modPath, op.OpTypeParseModuleConfiguration)
+}
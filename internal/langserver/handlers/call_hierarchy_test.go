@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_callHierarchy(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+	baseDir := filepath.Join(rootDir, "base")
+	baseDirUri := uri.FromPath(baseDir)
+	devDir := filepath.Join(rootDir, "dev")
+	devDirUri := uri.FromPath(devDir)
+
+	createModuleCalling(t, "../base", devDir)
+	err := os.MkdirAll(baseDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseCfg := `module "db" {
+  source = "../db"
+}
+`
+	err = os.WriteFile(filepath.Join(baseDir, "main.tf"), []byte(baseCfg), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, baseCfg, baseDirUri)})
+
+	devItem := fmt.Sprintf(`{
+		"name": "local",
+		"kind": 2,
+		"detail": "../base",
+		"uri": "%s/module.tf",
+		"range": {
+			"start": {"line": 1, "character": 0},
+			"end": {"line": 3, "character": 1}
+		},
+		"selectionRange": {
+			"start": {"line": 1, "character": 8},
+			"end": {"line": 1, "character": 13}
+		},
+		"data": {
+			"moduleUri": %q,
+			"name": "local"
+		}
+	}`, devDirUri, devDirUri)
+	dbItem := fmt.Sprintf(`{
+		"name": "db",
+		"kind": 2,
+		"detail": "../db",
+		"uri": "%s/main.tf",
+		"range": {
+			"start": {"line": 0, "character": 0},
+			"end": {"line": 2, "character": 1}
+		},
+		"selectionRange": {
+			"start": {"line": 0, "character": 8},
+			"end": {"line": 0, "character": 10}
+		},
+		"data": {
+			"moduleUri": %q,
+			"name": "db"
+		}
+	}`, baseDirUri, baseDirUri)
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareCallHierarchy",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 4
+			}
+		}`, baseDirUri)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [%s]
+		}`, dbItem))
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "callHierarchy/incomingCalls",
+		ReqParams: fmt.Sprintf(`{
+			"item": %s
+		}`, dbItem)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"from": %s,
+					"fromRanges": [
+						{
+							"start": {"line": 1, "character": 8},
+							"end": {"line": 1, "character": 13}
+						}
+					]
+				}
+			]
+		}`, devItem))
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "callHierarchy/outgoingCalls",
+		ReqParams: fmt.Sprintf(`{
+			"item": %s
+		}`, devItem)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": [
+				{
+					"to": %s,
+					"fromRanges": [
+						{
+							"start": {"line": 1, "character": 8},
+							"end": {"line": 1, "character": 13}
+						}
+					]
+				}
+			]
+		}`, dbItem))
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "callHierarchy/outgoingCalls",
+		ReqParams: fmt.Sprintf(`{
+			"item": %s
+		}`, dbItem)}, `{
+			"jsonrpc": "2.0",
+			"id": 6,
+			"result": []
+		}`)
+}
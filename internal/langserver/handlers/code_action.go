@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
-	"github.com/hashicorp/terraform-ls/internal/langserver/errors"
+	ihcl "github.com/hashicorp/terraform-ls/internal/hcl"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
-	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
-func (h *logHandler) TextDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) []lsp.CodeAction {
+func (h *logHandler) TextDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) []lsp.ExtendedCodeAction {
 	ca, err := h.textDocumentCodeAction(ctx, params)
 	if err != nil {
 		h.logger.Printf("code action failed: %s", err)
@@ -20,8 +23,8 @@ func (h *logHandler) TextDocumentCodeAction(ctx context.Context, params lsp.Code
 	return ca
 }
 
-func (h *logHandler) textDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) ([]lsp.CodeAction, error) {
-	var ca []lsp.CodeAction
+func (h *logHandler) textDocumentCodeAction(ctx context.Context, params lsp.CodeActionParams) ([]lsp.ExtendedCodeAction, error) {
+	var ca []lsp.ExtendedCodeAction
 
 	wantedCodeActions := ilsp.SupportedCodeActions.Only(params.Context.Only)
 	if len(wantedCodeActions) == 0 {
@@ -47,29 +50,463 @@ func (h *logHandler) textDocumentCodeAction(ctx context.Context, params lsp.Code
 	for action := range wantedCodeActions {
 		switch action {
 		case lsp.Source, lsp.SourceFixAll, ilsp.SourceFormatAll, ilsp.SourceFormatAllTerraformLs:
-			tfExec, err := module.TerraformExecutorForModule(ctx, fh.Dir())
+			format, err := h.formatterForDocument(ctx, fh)
 			if err != nil {
-				return ca, errors.EnrichTfExecError(err)
+				return ca, err
 			}
 
-			h.logger.Printf("formatting document via %q", tfExec.GetExecPath())
-
-			edits, err := formatDocument(ctx, tfExec, original, file)
+			edits, err := formatDocument(ctx, format, original, file)
 			if err != nil {
 				return ca, err
 			}
 
-			ca = append(ca, lsp.CodeAction{
-				Title: "Format Document",
-				Kind:  lsp.SourceFixAll,
-				Edit: lsp.WorkspaceEdit{
+			ca = append(ca, lsp.ExtendedCodeAction{
+				CodeAction: lsp.CodeAction{
+					Title: "Format Document",
+					Kind:  lsp.SourceFixAll,
+				},
+				Edit: &lsp.ExtendedWorkspaceEdit{
 					Changes: map[string][]lsp.TextEdit{
 						string(fh.URI()): edits,
 					},
 				},
 			})
+		case ilsp.SourceFixAllUpgrade:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			upgrades, err := legacySyntaxUpgrades(ctx, fh)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, upgrades...)
+		case lsp.QuickFix:
+			fixes, err := quickFixes(ctx, fh, params.Context.Diagnostics)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, fixes...)
+		case lsp.RefactorExtract:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			extractions, err := extractions(ctx, file, params.Range)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, extractions...)
+		case ilsp.RefactorExtractModule:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			extractions, err := moduleExtractions(ctx, file, params.Range)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, extractions...)
+		case lsp.RefactorRewrite:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			rewrites, err := rewrites(ctx, file, params.Range)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, rewrites...)
+		case ilsp.SourceGenerateVariables, ilsp.SourceGenerateTfvars:
+			generated, err := generatedFiles(ctx, file, action)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, generated...)
+		case ilsp.SourceGenerateOutputs:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			outputs, err := generatedOutputs(ctx, file, params.Range)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, outputs...)
+		case ilsp.SourceOrganizeAttributes:
+			if file.LanguageID() != ilsp.Terraform.String() {
+				continue
+			}
+			organized, err := organizedAttributes(ctx, file, original)
+			if err != nil {
+				return ca, err
+			}
+			ca = append(ca, organized...)
 		}
 	}
 
 	return ca, nil
 }
+
+// quickFixes returns fixes of problems represented by the given diagnostics,
+// as long as they still match problems found in the document
+func quickFixes(ctx context.Context, fh ilsp.FileHandler, diags []lsp.Diagnostic) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	if len(diags) == 0 {
+		return ca, nil
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	mod, err := mf.ModuleByPath(fh.Dir())
+	if err != nil {
+		return ca, err
+	}
+	bodySchema, err := mf.SchemaForModule(mod.Path)
+	if err != nil {
+		bodySchema = nil
+	}
+
+	problems := validation.Validate(mod, bodySchema)
+
+	for _, diag := range diags {
+		if diag.Source != validation.Source {
+			continue
+		}
+		for _, p := range problems {
+			if p.Filename != fh.Filename() || !problemMatchesDiagnostic(p, diag) {
+				continue
+			}
+
+			for _, fix := range validation.QuickFixes.Fixes(mod, p) {
+				if len(fix.NewFiles) > 0 && !ilsp.SupportsFileCreation(cc) {
+					continue
+				}
+				ca = append(ca, lsp.ExtendedCodeAction{
+					CodeAction: lsp.CodeAction{
+						Title:       fix.Title,
+						Kind:        lsp.QuickFix,
+						Diagnostics: []lsp.Diagnostic{diag},
+					},
+					Edit: ilsp.ExtendedWorkspaceEdit(fix.Edits, fix.NewFiles),
+				})
+			}
+		}
+	}
+
+	return ca, nil
+}
+
+// legacySyntaxUpgrades returns action replacing all syntax
+// of the module deprecated since Terraform 0.12, if there is any
+func legacySyntaxUpgrades(ctx context.Context, fh ilsp.FileHandler) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+	mod, err := mf.ModuleByPath(fh.Dir())
+	if err != nil {
+		return ca, err
+	}
+
+	fix, ok := validation.UpgradeLegacySyntax(mod)
+	if !ok {
+		return ca, nil
+	}
+
+	ca = append(ca, lsp.ExtendedCodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: fix.Title,
+			Kind:  ilsp.SourceFixAllUpgrade,
+		},
+		Edit: ilsp.ExtendedWorkspaceEdit(fix.Edits, fix.NewFiles),
+	})
+
+	return ca, nil
+}
+
+// extractions returns actions extracting the selected expression
+// into a local value or an input variable, where possible
+func extractions(ctx context.Context, file ilsp.File, lspRng lsp.Range) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	rng, err := ilsp.HCLRangeFromLSP(lspRng, file)
+	if err != nil {
+		return ca, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return ca, err
+	}
+
+	targets := []struct {
+		target refactor.ExtractTarget
+		title  string
+	}{
+		{refactor.ExtractToLocal, "Extract to local value %q"},
+		{refactor.ExtractToVariable, "Extract to input variable %q"},
+	}
+	for _, t := range targets {
+		extraction, ok := refactor.Extract(mod, file.Filename(), rng, t.target)
+		if !ok {
+			continue
+		}
+
+		ca = append(ca, lsp.ExtendedCodeAction{
+			CodeAction: lsp.CodeAction{
+				Title: fmt.Sprintf(t.title, extraction.Name),
+				Kind:  lsp.RefactorExtract,
+			},
+			Edit: fileChangesEdit(extraction.Files),
+		})
+	}
+
+	return ca, nil
+}
+
+// moduleExtractions returns action moving the blocks
+// within the range into a new module
+func moduleExtractions(ctx context.Context, file ilsp.File, lspRng lsp.Range) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return ca, err
+	}
+	if !ilsp.SupportsFileCreation(cc) {
+		return ca, nil
+	}
+
+	rng, err := ilsp.HCLRangeFromLSP(lspRng, file)
+	if err != nil {
+		return ca, err
+	}
+
+	renameOpts, err := lsctx.RenameOptions(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	extraction, ok, err := refactor.ExtractModule(mf, file.Dir(), file.Filename(), rng,
+		renameOpts.MovedBlocksFilename())
+	if err != nil {
+		return ca, err
+	}
+	if !ok {
+		return ca, nil
+	}
+
+	ca = append(ca, lsp.ExtendedCodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: fmt.Sprintf("Extract to module %q", extraction.Name),
+			Kind:  ilsp.RefactorExtractModule,
+		},
+		Edit: ilsp.ExtendedWorkspaceEdit(extraction.Edits, extraction.NewFiles),
+	})
+
+	return ca, nil
+}
+
+// rewrites returns actions rewriting the block at the beginning of the range
+func rewrites(ctx context.Context, file ilsp.File, lspRng lsp.Range) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	rng, err := ilsp.HCLRangeFromLSP(lspRng, file)
+	if err != nil {
+		return ca, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return ca, err
+	}
+
+	renameOpts, err := lsctx.RenameOptions(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	conv, ok := refactor.ConvertCountToForEach(mod, file.Filename(), rng.Start,
+		renameOpts.MovedBlocksFilename())
+	if ok {
+		ca = append(ca, lsp.ExtendedCodeAction{
+			CodeAction: lsp.CodeAction{
+				Title: fmt.Sprintf("Convert count of %s to for_each", conv.Address),
+				Kind:  lsp.RefactorRewrite,
+			},
+			Edit: fileChangesEdit(conv.Files),
+		})
+	}
+
+	return ca, nil
+}
+
+// generatedFiles returns action declaring all undeclared variables
+// of the module, or assigning all required variables in terraform.tfvars
+func generatedFiles(ctx context.Context, file ilsp.File, kind lsp.CodeActionKind) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return ca, err
+	}
+
+	var title string
+	var edits refactor.Edits
+	var newFiles []string
+	var ok bool
+	switch kind {
+	case ilsp.SourceGenerateVariables:
+		title = "Declare undeclared variables in variables.tf"
+		edits, newFiles, ok = refactor.DeclareVariables(mod)
+	case ilsp.SourceGenerateTfvars:
+		title = "Assign required variables in terraform.tfvars"
+		edits, newFiles, ok = refactor.GenerateVarsFile(mod)
+	}
+	if !ok {
+		return ca, nil
+	}
+	if len(newFiles) > 0 && !ilsp.SupportsFileCreation(cc) {
+		return ca, nil
+	}
+
+	ca = append(ca, lsp.ExtendedCodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: title,
+			Kind:  kind,
+		},
+		Edit: ilsp.ExtendedWorkspaceEdit(edits, newFiles),
+	})
+
+	return ca, nil
+}
+
+// generatedOutputs returns action exposing attributes of the resource,
+// data source or module call within the range as outputs
+func generatedOutputs(ctx context.Context, file ilsp.File, lspRng lsp.Range) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ca, err
+	}
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	rng, err := ilsp.HCLRangeFromLSP(lspRng, file)
+	if err != nil {
+		return ca, err
+	}
+
+	bodySchema, err := mf.SchemaForModule(file.Dir())
+	if err != nil {
+		bodySchema = nil
+	}
+
+	gen, ok, err := refactor.GenerateOutputs(mf, bodySchema, file.Dir(), file.Filename(), rng)
+	if err != nil {
+		return ca, err
+	}
+	if !ok {
+		return ca, nil
+	}
+	if len(gen.NewFiles) > 0 && !ilsp.SupportsFileCreation(cc) {
+		return ca, nil
+	}
+
+	ca = append(ca, lsp.ExtendedCodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: fmt.Sprintf("Generate outputs for %s in outputs.tf", gen.Address),
+			Kind:  ilsp.SourceGenerateOutputs,
+		},
+		Edit: ilsp.ExtendedWorkspaceEdit(gen.Edits, gen.NewFiles),
+	})
+
+	return ca, nil
+}
+
+// organizedAttributes returns action reordering attributes
+// and blocks of the document in the configured order
+func organizedAttributes(ctx context.Context, file ilsp.File, original []byte) ([]lsp.ExtendedCodeAction, error) {
+	ca := make([]lsp.ExtendedCodeAction, 0)
+
+	opts, err := lsctx.OrganizeAttributesOptions(ctx)
+	if err != nil {
+		return ca, err
+	}
+
+	organized, ok := refactor.OrganizeAttributes(original, file.Filename(), opts.AttributesOrder())
+	if !ok {
+		return ca, nil
+	}
+
+	changes := ihcl.Diff(file, original, organized)
+
+	ca = append(ca, lsp.ExtendedCodeAction{
+		CodeAction: lsp.CodeAction{
+			Title: "Organize attributes",
+			Kind:  ilsp.SourceOrganizeAttributes,
+		},
+		Edit: &lsp.ExtendedWorkspaceEdit{
+			Changes: map[string][]lsp.TextEdit{
+				string(file.URI()): ilsp.TextEditsFromDocumentChanges(changes),
+			},
+		},
+	})
+
+	return ca, nil
+}
+
+// fileChangesEdit returns minimal edits of the changed files
+func fileChangesEdit(files map[string]refactor.FileChange) *lsp.ExtendedWorkspaceEdit {
+	changes := make(map[string][]lsp.TextEdit, len(files))
+	for path, fc := range files {
+		docChanges := ihcl.Diff(ilsp.FileHandlerFromPath(path), fc.Before, fc.After)
+		changes[uri.FromPath(path)] = ilsp.TextEditsFromDocumentChanges(docChanges)
+	}
+
+	return &lsp.ExtendedWorkspaceEdit{
+		Changes: changes,
+	}
+}
+
+func problemMatchesDiagnostic(p validation.Problem, diag lsp.Diagnostic) bool {
+	code, ok := diag.Code.(string)
+	if !ok || code != string(p.Code) {
+		return false
+	}
+
+	pDiag := ilsp.HCLDiagsToLSP([]*hcl.Diagnostic{p.Diagnostic}, validation.Source)[0]
+	return pDiag.Range == diag.Range && pDiag.Message == diag.Message
+}
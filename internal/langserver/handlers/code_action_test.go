@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/go-version"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/hashicorp/terraform-ls/internal/langserver"
 	"github.com/hashicorp/terraform-ls/internal/langserver/session"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
@@ -145,3 +149,991 @@ func TestLangServer_codeAction_basic(t *testing.T) {
 			]
 		}`, tmpDir.URI()))
 }
+
+func TestLangServer_codeAction_quickFix(t *testing.T) {
+	tmpDir := TempDir(t)
+	err := os.WriteFile(filepath.Join(tmpDir.Dir(), "variables.tf"), []byte(`variable "zone" {
+}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"diagnostic": {}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"region\" {\n  value = var.region\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" }
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": "b2ef9734d1707191",
+				"items": [
+					{
+						"range": {
+							"start": { "line": 1, "character": 10 },
+							"end": { "line": 1, "character": 20 }
+						},
+						"severity": 1,
+						"code": "undeclared-variable",
+						"source": "terraform-ls",
+						"message": "Reference to undeclared input variable: An input variable with the name \"region\" has not been declared. This variable can be declared with a variable \"region\" {} block."
+					}
+				]
+			}
+		}`)
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 10 },
+				"end": { "line": 1, "character": 20 }
+			},
+			"context": {
+				"diagnostics": [
+					{
+						"range": {
+							"start": { "line": 1, "character": 10 },
+							"end": { "line": 1, "character": 20 }
+						},
+						"severity": 1,
+						"code": "undeclared-variable",
+						"source": "terraform-ls",
+						"message": "Reference to undeclared input variable: An input variable with the name \"region\" has not been declared. This variable can be declared with a variable \"region\" {} block."
+					}
+				],
+				"only": ["quickfix"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"title": "Declare variable \"region\" in variables.tf",
+					"kind": "quickfix",
+					"diagnostics": [
+						{
+							"range": {
+								"start": { "line": 1, "character": 10 },
+								"end": { "line": 1, "character": 20 }
+							},
+							"severity": 1,
+							"code": "undeclared-variable",
+							"source": "terraform-ls",
+							"message": "Reference to undeclared input variable: An input variable with the name \"region\" has not been declared. This variable can be declared with a variable \"region\" {} block."
+						}
+					],
+					"edit": {
+						"changes": {
+							"%s/variables.tf": [
+								{
+									"range": {
+										"start": { "line": 2, "character": 0 },
+										"end": { "line": 2, "character": 0 }
+									},
+									"newText": "\nvariable \"region\" {\n}\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_quickFixCreatingFile(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create"]
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"region\" {\n  value = var.region\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 10 },
+				"end": { "line": 1, "character": 20 }
+			},
+			"context": {
+				"diagnostics": [
+					{
+						"range": {
+							"start": { "line": 1, "character": 10 },
+							"end": { "line": 1, "character": 20 }
+						},
+						"severity": 1,
+						"code": "undeclared-variable",
+						"source": "terraform-ls",
+						"message": "Reference to undeclared input variable: An input variable with the name \"region\" has not been declared. This variable can be declared with a variable \"region\" {} block."
+					}
+				],
+				"only": ["quickfix"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Declare variable \"region\" in variables.tf",
+					"kind": "quickfix",
+					"diagnostics": [
+						{
+							"range": {
+								"start": { "line": 1, "character": 10 },
+								"end": { "line": 1, "character": 20 }
+							},
+							"severity": 1,
+							"code": "undeclared-variable",
+							"source": "terraform-ls",
+							"message": "Reference to undeclared input variable: An input variable with the name \"region\" has not been declared. This variable can be declared with a variable \"region\" {} block."
+						}
+					],
+					"edit": {
+						"documentChanges": [
+							{
+								"kind": "create",
+								"uri": "%s/variables.tf",
+								"options": {
+									"ignoreIfExists": true
+								}
+							},
+							{
+								"textDocument": {
+									"uri": "%s/variables.tf",
+									"version": null
+								},
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 0, "character": 0 }
+										},
+										"newText": "variable \"region\" {\n}\n"
+									}
+								]
+							}
+						]
+					}
+				}
+			]
+		}`, tmpDir.URI(), tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_extract(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"port\" {\n  value = 8080\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 10 },
+				"end": { "line": 1, "character": 14 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["refactor.extract"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Extract to local value \"value\"",
+					"kind": "refactor.extract",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 0, "character": 0 },
+										"end": { "line": 1, "character": 0 }
+									},
+									"newText": "locals {\n"
+								},
+								{
+									"range": {
+										"start": { "line": 3, "character": 0 },
+										"end": { "line": 3, "character": 0 }
+									},
+									"newText": "\noutput \"port\" {\n  value = local.value\n}\n"
+								}
+							]
+						}
+					}
+				},
+				{
+					"title": "Extract to input variable \"value\"",
+					"kind": "refactor.extract",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 0, "character": 0 },
+										"end": { "line": 0, "character": 0 }
+									},
+									"newText": "variable \"value\" {\n  type    = number\n  default = 8080\n}\n\n"
+								},
+								{
+									"range": {
+										"start": { "line": 1, "character": 0 },
+										"end": { "line": 2, "character": 0 }
+									},
+									"newText": "  value = var.value\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI(), tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_countToForEach(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(nil))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "resource \"random_pet\" \"app\" {\n  count = 2\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 2 },
+				"end": { "line": 1, "character": 2 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["refactor.rewrite"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Convert count of random_pet.app to for_each",
+					"kind": "refactor.rewrite",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 1, "character": 0 },
+										"end": { "line": 2, "character": 0 }
+									},
+									"newText": "  for_each = { for i in range(2) : i =\u003e i }\n}\n\nmoved {\n  from = random_pet.app[0]\n  to   = random_pet.app[\"0\"]\n}\n\nmoved {\n  from = random_pet.app[1]\n  to   = random_pet.app[\"1\"]\n"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_generateVariables(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create"]
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"region\" {\n  value = var.region\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["source.generateVariables"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Declare undeclared variables in variables.tf",
+					"kind": "source.generateVariables",
+					"edit": {
+						"documentChanges": [
+							{
+								"kind": "create",
+								"uri": "%s/variables.tf",
+								"options": {
+									"ignoreIfExists": true
+								}
+							},
+							{
+								"textDocument": {
+									"uri": "%s/variables.tf",
+									"version": null
+								},
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 0, "character": 0 }
+										},
+										"newText": "variable \"region\" {\n  type = any\n}\n"
+									}
+								]
+							}
+						]
+					}
+				}
+			]
+		}`, tmpDir.URI(), tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_generateOutputs(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Dir())
+
+	var testSchema tfjson.ProviderSchemas
+	err := json.Unmarshal([]byte(testOutputsSchemaOutput), &testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+					{
+						Method:        "ProviderSchemas",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							&testSchema,
+							nil,
+						},
+					},
+				},
+			},
+		}}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create"]
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, `terraform {
+  required_providers {
+    test = {
+      source = "test/test"
+    }
+  }
+}
+
+resource "test_instance" "app" {
+}
+`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 8, "character": 0 },
+				"end": { "line": 8, "character": 0 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["source.generateOutputs"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Generate outputs for test_instance.app in outputs.tf",
+					"kind": "source.generateOutputs",
+					"edit": {
+						"documentChanges": [
+							{
+								"kind": "create",
+								"uri": "%s/outputs.tf",
+								"options": {
+									"ignoreIfExists": true
+								}
+							},
+							{
+								"textDocument": {
+									"uri": "%s/outputs.tf",
+									"version": null
+								},
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 0, "character": 0 }
+										},
+										"newText": "output \"app_id\" {\n  description = \"ID of the instance\"\n  value       = test_instance.app.id\n}\n\noutput \"app_token\" {\n  value     = test_instance.app.token\n  sensitive = true\n}\n"
+									}
+								]
+							}
+						]
+					}
+				}
+			]
+		}`, tmpDir.URI(), tmpDir.URI()))
+}
+
+var testOutputsSchemaOutput = `{
+  "format_version": "0.1",
+  "provider_schemas": {
+    "test/test": {
+      "resource_schemas": {
+        "test_instance": {
+          "version": 0,
+          "block": {
+            "attributes": {
+              "id": {
+                "type": "string",
+                "description": "ID of the instance",
+                "description_kind": "plaintext",
+                "optional": true,
+                "computed": true
+              },
+              "name": {
+                "type": "string",
+                "required": true
+              },
+              "token": {
+                "type": "string",
+                "computed": true,
+                "sensitive": true
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestLangServer_codeAction_upgradeLegacySyntax(t *testing.T) {
+	tmpDir := TempDir(t)
+	text := "variable \"name\" {\n  type = \"string\"\n}\n"
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+					{
+						Method:        "Format",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+							[]byte(text),
+						},
+						ReturnArguments: []interface{}{
+							[]byte(text),
+							nil,
+						},
+					}},
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": %q,
+			"uri": "%s/main.tf"
+		}
+	}`, text, tmpDir.URI())})
+	// formatting alone is computed against the same text,
+	// so the upgrade is not part of source.fixAll
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"context": { "diagnostics": [], "only": ["source.fixAll"] }
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Format Document",
+					"kind": "source.fixAll",
+					"edit": {
+						"changes": {
+							"%s/main.tf": []
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI()))
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"context": { "diagnostics": [], "only": ["source.fixAll.terraform-ls.upgrade"] }
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"title": "Upgrade legacy syntax in module",
+					"kind": "source.fixAll.terraform-ls.upgrade",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 1, "character": 9 },
+										"end": { "line": 1, "character": 17 }
+									},
+									"newText": "string"
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_organizeAttributes(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "resource \"aws_instance\" \"web\" {\n  ami   = \"ami-123\"\n  count = 2\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 0, "character": 0 },
+				"end": { "line": 0, "character": 0 }
+			},
+			"context": { "diagnostics": [], "only": ["source.organizeAttributes"] }
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Organize attributes",
+					"kind": "source.organizeAttributes",
+					"edit": {
+						"changes": {
+							"%s/main.tf": [
+								{
+									"range": {
+										"start": { "line": 1, "character": 0 },
+										"end": { "line": 1, "character": 0 }
+									},
+									"newText": "  count = 2\n"
+								},
+								{
+									"range": {
+										"start": { "line": 2, "character": 0 },
+										"end": { "line": 3, "character": 0 }
+									},
+									"newText": ""
+								}
+							]
+						}
+					}
+				}
+			]
+		}`, tmpDir.URI()))
+}
+
+func TestLangServer_codeAction_extractModule(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(nil))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	        "workspace": {
+	            "workspaceEdit": {
+	                "documentChanges": true,
+	                "resourceOperations": ["create"]
+	            }
+	        }
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "resource \"random_pet\" \"app\" {\n  length = 2\n}\n\noutput \"name\" {\n  value = random_pet.app.id\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/codeAction",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": { "uri": "%s/main.tf" },
+			"range": {
+				"start": { "line": 1, "character": 2 },
+				"end": { "line": 1, "character": 2 }
+			},
+			"context": {
+				"diagnostics": [],
+				"only": ["refactor.extract.module"]
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"title": "Extract to module \"app\"",
+					"kind": "refactor.extract.module",
+					"edit": {
+						"documentChanges": [
+							{
+								"kind": "create",
+								"uri": "%s/modules/app/main.tf",
+								"options": { "ignoreIfExists": true }
+							},
+							{
+								"kind": "create",
+								"uri": "%s/modules/app/outputs.tf",
+								"options": { "ignoreIfExists": true }
+							},
+							{
+								"textDocument": { "uri": "%s/main.tf", "version": null },
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 5, "character": 10 },
+											"end": { "line": 5, "character": 27 }
+										},
+										"newText": "module.app.app_id"
+									},
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 4, "character": 0 }
+										},
+										"newText": "module \"app\" {\n  source = \"./modules/app\"\n}\n\nmoved {\n  from = random_pet.app\n  to   = module.app.random_pet.app\n}\n\n"
+									}
+								]
+							},
+							{
+								"textDocument": { "uri": "%s/modules/app/main.tf", "version": null },
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 0, "character": 0 }
+										},
+										"newText": "resource \"random_pet\" \"app\" {\n  length = 2\n}\n"
+									}
+								]
+							},
+							{
+								"textDocument": { "uri": "%s/modules/app/outputs.tf", "version": null },
+								"edits": [
+									{
+										"range": {
+											"start": { "line": 0, "character": 0 },
+											"end": { "line": 0, "character": 0 }
+										},
+										"newText": "output \"app_id\" {\n  value = random_pet.app.id\n}\n"
+									}
+								]
+							}
+						]
+					}
+				}
+			]
+		}`, tmpDir.URI(), tmpDir.URI(), tmpDir.URI(), tmpDir.URI(), tmpDir.URI()))
+}
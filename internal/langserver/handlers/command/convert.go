@@ -0,0 +1,197 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/code"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/hcl"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+const convertVersion = 0
+
+type convertResponse struct {
+	FormatVersion int    `json:"v"`
+	URI           string `json:"uri"`
+	Applied       bool   `json:"applied"`
+
+	// DroppedComments is the number of comments which could
+	// not be converted, in which case the edit is not applied
+	DroppedComments int `json:"droppedComments"`
+
+	Content string `json:"content,omitempty"`
+}
+
+func ConvertToHCLHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	return convertFile(ctx, args, ".tf.json", ".tf", func(src []byte, path string) ([]byte, int, error) {
+		mf, err := lsctx.ModuleFinder(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		bodySchema, err := mf.SchemaForModule(filepath.Dir(path))
+		if err != nil {
+			return nil, 0, err
+		}
+		content, err := hcl.ToHCL(src, filepath.Base(path), bodySchema)
+		return content, 0, err
+	})
+}
+
+func ConvertToJSONHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
+	return convertFile(ctx, args, ".tf", ".tf.json", func(src []byte, path string) ([]byte, int, error) {
+		content, dropped, err := hcl.ToJSON(src, filepath.Base(path))
+		return content, len(dropped), err
+	})
+}
+
+// convertFunc returns the converted content along with
+// the number of comments which could not be converted
+type convertFunc func(src []byte, path string) ([]byte, int, error)
+
+// convertFile converts the file given by uri argument with the given
+// extension into a sibling file with the target extension, replacing
+// the original via workspace/applyEdit where the client supports it.
+//
+// Both files declaring the same objects would make the module invalid,
+// so the edit is only applied if the client can delete the original
+// and no comments were dropped during conversion. Otherwise just
+// the converted content is returned and the user is warned
+// about any dropped comments.
+func convertFile(ctx context.Context, args cmd.CommandArgs, ext, targetExt string, convert convertFunc) (interface{}, error) {
+	fileUri, ok := args.GetString("uri")
+	if !ok || fileUri == "" {
+		return nil, fmt.Errorf("%w: expected file uri argument to be set", code.InvalidParams.Err())
+	}
+
+	if !uri.IsURIValid(fileUri) {
+		return nil, fmt.Errorf("URI %q is not valid", fileUri)
+	}
+
+	path, err := uri.PathFromURI(fileUri)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ext) {
+		return nil, fmt.Errorf("%w: expected %s file, given %q", code.InvalidParams.Err(), ext, path)
+	}
+
+	targetPath := strings.TrimSuffix(path, ext) + targetExt
+	if _, err := os.Stat(targetPath); err == nil {
+		return nil, fmt.Errorf("%w: %q already exists", code.InvalidParams.Err(), targetPath)
+	}
+
+	src, err := readFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, droppedComments, err := convert(src, path)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := convertResponse{
+		FormatVersion:   convertVersion,
+		URI:             uri.FromPath(targetPath),
+		DroppedComments: droppedComments,
+	}
+
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if droppedComments > 0 {
+		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+			Type: lsp.Warning,
+			Message: fmt.Sprintf("%d comment(s) in %s could not be converted, "+
+				"the original file was left unchanged", droppedComments, filepath.Base(path)),
+		})
+	}
+
+	canApply := cc.Workspace.ApplyEdit &&
+		ilsp.SupportsFileCreation(cc) &&
+		ilsp.SupportsFileDeletion(cc)
+	if !canApply || droppedComments > 0 {
+		rsp.Content = string(content)
+		return rsp, nil
+	}
+
+	applied, err := applyConversion(ctx, path, targetPath, string(content))
+	if err != nil {
+		return nil, err
+	}
+	rsp.Applied = applied
+	if !applied {
+		rsp.Content = string(content)
+	}
+
+	return rsp, nil
+}
+
+// readFile reads the document if it is open, or the file from disk otherwise
+func readFile(ctx context.Context, path string) ([]byte, error) {
+	ds, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := ds.GetDocument(ilsp.FileHandlerFromPath(path))
+	if err != nil {
+		var unknownErr *filesystem.UnknownDocumentErr
+		if errors.As(err, &unknownErr) {
+			return os.ReadFile(path)
+		}
+		return nil, err
+	}
+	return doc.Text()
+}
+
+func applyConversion(ctx context.Context, path, targetPath, content string) (bool, error) {
+	targetUri := lsp.DocumentURI(uri.FromPath(targetPath))
+	edit := &lsp.ExtendedWorkspaceEdit{
+		DocumentChanges: []interface{}{
+			lsp.CreateFile{
+				Kind: "create",
+				URI:  targetUri,
+			},
+			lsp.UnversionedTextDocumentEdit{
+				TextDocument: lsp.UnversionedTextDocumentIdentifier{
+					URI: targetUri,
+				},
+				Edits: []lsp.TextEdit{
+					{NewText: content},
+				},
+			},
+			lsp.DeleteFile{
+				Kind: "delete",
+				URI:  lsp.DocumentURI(uri.FromPath(path)),
+			},
+		},
+	}
+
+	rsp, err := jrpc2.ServerFromContext(ctx).Callback(ctx, "workspace/applyEdit", lsp.ExtendedApplyWorkspaceEditParams{
+		Label: fmt.Sprintf("Convert %s to %s", filepath.Base(path), filepath.Base(targetPath)),
+		Edit:  edit,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var result lsp.ApplyWorkspaceEditResponse
+	err = rsp.UnmarshalResult(&result)
+	if err != nil {
+		return false, err
+	}
+	return result.Applied, nil
+}
@@ -14,6 +14,7 @@ import (
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
 func TerraformValidateHandler(ctx context.Context, args cmd.CommandArgs) (interface{}, error) {
@@ -66,11 +67,16 @@ func TerraformValidateHandler(ctx context.Context, args cmd.CommandArgs) (interf
 	}
 
 	diags := diagnostics.NewDiagnostics()
-	validateDiags := diagnostics.HCLDiagsFromJSON(jsonDiags)
+	filenames := make([]string, 0, len(mod.ModuleDiagnostics))
+	for filename := range mod.ModuleDiagnostics {
+		filenames = append(filenames, filename.String())
+	}
+	validateDiags := diagnostics.ValidateDiagsFromJSON(jsonDiags, filenames)
 	diags.EmptyRootDiagnostic()
 	diags.Append("terraform validate", validateDiags)
 	diags.Append("HCL", mod.ModuleDiagnostics.AsMap())
 	diags.Append("HCL", mod.VarsDiagnostics.AutoloadedOnly().AsMap())
+	diags.AppendProblems(validation.ModuleProblems(modMgr, mod))
 
 	notifier.PublishHCLDiags(ctx, mod.Path, diags)
 
@@ -3,7 +3,9 @@ package handlers
 import (
 	"context"
 
+	"github.com/creachadair/jrpc2/code"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/functions"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
@@ -11,6 +13,80 @@ import (
 func (h *logHandler) TextDocumentComplete(ctx context.Context, params lsp.CompletionParams) (lsp.CompletionList, error) {
 	var list lsp.CompletionList
 
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	doc, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return list, err
+	}
+
+	cache, err := lsctx.CompletionCache(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	list, err = h.completionList(ctx, params.TextDocumentPositionParams)
+	if err != nil {
+		return list, err
+	}
+
+	data := ilsp.CompletionItemData{
+		URI:      params.TextDocument.URI,
+		Position: params.Position,
+		Version:  doc.Version(),
+	}
+	cache.Store(data, list)
+
+	return ilsp.LazyCompletionList(list, data, cc.TextDocument.Completion), nil
+}
+
+func (h *logHandler) CompletionItemResolve(ctx context.Context, item lsp.CompletionItem) (lsp.CompletionItem, error) {
+	data, err := ilsp.DecodeCompletionItemData(item.Data)
+	if err != nil {
+		return item, code.InvalidParams.Err()
+	}
+
+	cache, err := lsctx.CompletionCache(ctx)
+	if err != nil {
+		return item, err
+	}
+
+	resolved, ok := cache.Item(data)
+	if ok && resolved.Label == item.Label && resolved.Kind == item.Kind {
+		return ilsp.ResolveCompletionItem(item, resolved), nil
+	}
+
+	// The list is no longer cached, e.g. as another one was completed since
+	list, err := h.completionList(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: lsp.TextDocumentIdentifier{URI: data.URI},
+		Position:     data.Position,
+	})
+	if err != nil {
+		return item, err
+	}
+
+	for _, resolved := range list.Items {
+		if resolved.Label == item.Label && resolved.Kind == item.Kind {
+			return ilsp.ResolveCompletionItem(item, resolved), nil
+		}
+	}
+
+	h.logger.Printf("unable to resolve completion item %q", item.Label)
+	return item, nil
+}
+
+// completionList returns fully resolved completion items at the given position
+func (h *logHandler) completionList(ctx context.Context, params lsp.TextDocumentPositionParams) (lsp.CompletionList, error) {
+	var list lsp.CompletionList
+
 	fs, err := lsctx.DocumentStorage(ctx)
 	if err != nil {
 		return list, err
@@ -54,7 +130,7 @@ func (h *logHandler) TextDocumentComplete(ctx context.Context, params lsp.Comple
 
 	d.PrefillRequiredFields = expFeatures.PrefillRequiredFields
 
-	fPos, err := ilsp.FilePositionFromDocumentPosition(params.TextDocumentPositionParams, file)
+	fPos, err := ilsp.FilePositionFromDocumentPosition(params, file)
 	if err != nil {
 		return list, err
 	}
@@ -62,5 +138,23 @@ func (h *logHandler) TextDocumentComplete(ctx context.Context, params lsp.Comple
 	h.logger.Printf("Looking for candidates at %q -> %#v", file.Filename(), fPos.Position())
 	candidates, err := d.CandidatesAtPos(file.Filename(), fPos.Position())
 	h.logger.Printf("received candidates: %#v", candidates)
-	return ilsp.ToCompletionList(candidates, cc.TextDocument), err
+	if err != nil {
+		return list, err
+	}
+
+	list = ilsp.ToCompletionList(candidates, cc.TextDocument)
+
+	if file.LanguageID() == ilsp.Terraform.String() {
+		text, err := file.Text()
+		if err != nil {
+			return list, err
+		}
+		prefix, rng, ok := functions.PrefixAtPos(text, fPos.Filename(), fPos.Position())
+		if ok {
+			funcs := functions.FunctionsForVersion(mod.TerraformVersion)
+			list.Items = append(list.Items, ilsp.FunctionCompletionItems(funcs, prefix, rng, cc.TextDocument)...)
+		}
+	}
+
+	return list, nil
 }
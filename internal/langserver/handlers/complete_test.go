@@ -886,3 +886,205 @@ func writeContentToFile(t *testing.T, path string, content string) {
 		t.Fatal(err)
 	}
 }
+
+func TestCompletion_functions(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"name\" {\n  value = up\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/completion",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 12,
+				"line": 1
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"isIncomplete": false,
+				"items": [
+					{
+						"label": "upper",
+						"labelDetails": {},
+						"kind": 3,
+						"detail": "upper(str string) string",
+						"documentation": "upper converts all cased letters in the given string to uppercase.",
+						"insertTextFormat": 1,
+						"textEdit": {
+							"range": {
+								"start": {"line": 1, "character": 10},
+								"end": {"line": 1, "character": 12}
+							},
+							"newText": "upper"
+						}
+					}
+				]
+			}
+		}`)
+}
+
+func TestCompletion_resolve(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	        "textDocument": {
+	            "completion": {
+	                "completionItem": {
+	                    "resolveSupport": {
+	                        "properties": ["detail", "documentation"]
+	                    }
+	                }
+	            }
+	        }
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "output \"name\" {\n  value = up\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/completion",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"character": 12,
+				"line": 1
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"isIncomplete": false,
+				"items": [
+					{
+						"label": "upper",
+						"labelDetails": {},
+						"kind": 3,
+						"insertTextFormat": 1,
+						"textEdit": {
+							"range": {
+								"start": {"line": 1, "character": 10},
+								"end": {"line": 1, "character": 12}
+							},
+							"newText": "upper"
+						},
+						"data": {
+							"uri": "%s/main.tf",
+							"position": {"line": 1, "character": 12},
+							"version": 0,
+							"index": 0
+						}
+					}
+				]
+			}
+		}`, tmpDir.URI()))
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "completionItem/resolve",
+		ReqParams: fmt.Sprintf(`{
+			"label": "upper",
+			"labelDetails": {},
+			"kind": 3,
+			"insertTextFormat": 1,
+			"textEdit": {
+				"range": {
+					"start": {"line": 1, "character": 10},
+					"end": {"line": 1, "character": 12}
+				},
+				"newText": "upper"
+			},
+			"data": {
+				"uri": "%s/main.tf",
+				"position": {"line": 1, "character": 12},
+				"version": 0,
+				"index": 0
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"label": "upper",
+				"labelDetails": {},
+				"kind": 3,
+				"detail": "upper(str string) string",
+				"documentation": "upper converts all cased letters in the given string to uppercase.",
+				"insertTextFormat": 1,
+				"textEdit": {
+					"range": {
+						"start": {"line": 1, "character": 10},
+						"end": {"line": 1, "character": 12}
+					},
+					"newText": "upper"
+				},
+				"data": {
+					"index": 0,
+					"position": {"character": 12, "line": 1},
+					"uri": "%s/main.tf",
+					"version": 0
+				}
+			}
+		}`, tmpDir.URI()))
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/langserver/diagnostics"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/hashicorp/terraform-ls/internal/validation"
+)
+
+func (h *logHandler) TextDocumentDiagnostic(ctx context.Context, params lsp.DocumentDiagnosticParams) (interface{}, error) {
+	modMgr, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fh := ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI)
+
+	mod, err := parsedModule(modMgr, fh.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	diags := moduleDiagnostics(modMgr, mod)
+	if vf, ok := ast.NewVarsFilename(fh.Filename()); ok && !vf.IsAutoloaded() {
+		diags.Append("HCL", mod.VarsDiagnostics.ForFile(vf).AsMap())
+	}
+
+	fileDiags := diags.ForFile(fh.Filename())
+	resultID := ilsp.DiagnosticsResultID(fileDiags)
+
+	if params.PreviousResultID == resultID {
+		return lsp.UnchangedDocumentDiagnosticReport{
+			Kind:     lsp.DiagnosticUnchanged,
+			ResultID: resultID,
+		}, nil
+	}
+
+	return lsp.FullDocumentDiagnosticReport{
+		Kind:     lsp.DiagnosticFull,
+		ResultID: resultID,
+		Items:    fileDiags,
+	}, nil
+}
+
+func (h *logHandler) WorkspaceDiagnostic(ctx context.Context, params lsp.WorkspaceDiagnosticParams) (lsp.WorkspaceDiagnosticReport, error) {
+	report := lsp.WorkspaceDiagnosticReport{
+		Items: make([]lsp.WorkspaceDocumentDiagnosticReport, 0),
+	}
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	modMgr, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	modules, err := modMgr.ListModules()
+	if err != nil {
+		return report, err
+	}
+
+	previousIDs := make(map[string]string, len(params.PreviousResultIds))
+	for _, prev := range params.PreviousResultIds {
+		fh := ilsp.FileHandlerFromDocumentURI(prev.URI)
+		previousIDs[fh.FullPath()] = prev.Value
+	}
+
+	for _, mod := range modules {
+		mod, err := parsedModule(modMgr, mod.Path)
+		if err != nil {
+			return report, err
+		}
+
+		diags := moduleDiagnostics(modMgr, mod)
+
+		for _, filename := range diags.Filenames() {
+			fullPath := filepath.Join(mod.Path, filename)
+			docURI := uri.FromPath(fullPath)
+
+			// version is only known for documents open in the editor
+			var version int32
+			doc, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(lsp.DocumentURI(docURI)))
+			if err == nil {
+				version = int32(doc.Version())
+			}
+
+			fileDiags := diags.ForFile(filename)
+			resultID := ilsp.DiagnosticsResultID(fileDiags)
+
+			if previousIDs[fullPath] == resultID {
+				report.Items = append(report.Items, lsp.WorkspaceUnchangedDocumentDiagnosticReport{
+					URI:     lsp.DocumentURI(docURI),
+					Version: version,
+					UnchangedDocumentDiagnosticReport: lsp.UnchangedDocumentDiagnosticReport{
+						Kind:     lsp.DiagnosticUnchanged,
+						ResultID: resultID,
+					},
+				})
+				continue
+			}
+
+			report.Items = append(report.Items, lsp.WorkspaceFullDocumentDiagnosticReport{
+				URI:     lsp.DocumentURI(docURI),
+				Version: version,
+				FullDocumentDiagnosticReport: lsp.FullDocumentDiagnosticReport{
+					Kind:     lsp.DiagnosticFull,
+					ResultID: resultID,
+					Items:    fileDiags,
+				},
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// parsedModule returns module with parsed configuration and variables,
+// which is not the case for modules only indexed by the walker
+func parsedModule(modMgr module.ModuleManager, modPath string) (module.Module, error) {
+	mod, err := modMgr.ModuleByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if mod.ModuleParsingState == op.OpStateUnknown {
+		err = modMgr.EnqueueModuleOpWait(mod.Path, op.OpTypeParseModuleConfiguration)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if mod.VarsParsingState == op.OpStateUnknown {
+		err = modMgr.EnqueueModuleOpWait(mod.Path, op.OpTypeParseVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return modMgr.ModuleByPath(modPath)
+}
+
+// moduleDiagnostics returns diagnostics of all module files
+// and automatically loaded variable files, same as when published
+func moduleDiagnostics(modMgr module.ModuleFinder, mod module.Module) diagnostics.Diagnostics {
+	diags := diagnostics.NewDiagnostics()
+	diags.Append("HCL", mod.ModuleDiagnostics.AsMap())
+	diags.Append("HCL", mod.VarsDiagnostics.AutoloadedOnly().AsMap())
+	diags.AppendProblems(validation.ModuleProblems(modMgr, mod))
+	return diags
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_textDocumentDiagnostic(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"diagnostic": {}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"name\" {\n  default = \n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": "8513e286de3afabc",
+				"items": [
+					{
+						"range": {
+							"start": {"line": 1, "character": 12},
+							"end": {"line": 2, "character": 0}
+						},
+						"severity": 1,
+						"source": "HCL",
+						"message": "Invalid expression: Expected the start of an expression, but found an invalid expression token."
+					}
+				]
+			}
+		}`)
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "8513e286de3afabc"
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"kind": "unchanged",
+				"resultId": "8513e286de3afabc"
+			}
+		}`)
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"previousResultIds": [
+				{
+					"uri": "%s/main.tf",
+					"value": "8513e286de3afabc"
+				}
+			]
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"items": [
+					{
+						"uri": "%s/main.tf",
+						"version": 0,
+						"kind": "unchanged",
+						"resultId": "8513e286de3afabc"
+					}
+				]
+			}
+		}`, tmpDir.URI()))
+}
+
+func TestLangServer_workspaceDiagnostic_indexedModule(t *testing.T) {
+	tmpDir := TempDir(t, ".terraform")
+	err := ioutil.WriteFile(filepath.Join(tmpDir.Dir(), "main.tf"), []byte(`variable "name" {
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"diagnostic": {}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "workspace/diagnostic",
+		ReqParams: `{"previousResultIds": []}`}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"result": {
+				"items": [
+					{
+						"uri": "%s/main.tf",
+						"version": 0,
+						"kind": "full",
+						"resultId": %q,
+						"items": [
+							{
+								"range": {
+									"start": {"line": 1, "character": 0},
+									"end": {"line": 1, "character": 0}
+								},
+								"severity": 1,
+								"source": "HCL",
+								"message": "Argument or block definition required: An argument or block definition is required here."
+							}
+						]
+					}
+				]
+			}
+		}`, tmpDir.URI(), unterminatedBlockResultID()))
+}
+
+// unterminatedBlockResultID returns result ID of the diagnostics
+// reported for a file with a block left open on its first line
+func unterminatedBlockResultID() string {
+	return ilsp.DiagnosticsResultID([]lsp.Diagnostic{
+		{
+			Range: lsp.Range{
+				Start: lsp.Position{Line: 1, Character: 0},
+				End:   lsp.Position{Line: 1, Character: 0},
+			},
+			Severity: lsp.SeverityError,
+			Source:   "HCL",
+			Message:  "Argument or block definition required: An argument or block definition is required here.",
+		},
+	})
+}
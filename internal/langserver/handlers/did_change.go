@@ -10,6 +10,7 @@ import (
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
 func TextDocumentDidChange(ctx context.Context, params lsp.DidChangeTextDocumentParams) error {
@@ -88,6 +89,11 @@ func TextDocumentDidChange(ctx context.Context, params lsp.DidChangeTextDocument
 		return err
 	}
 
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if err != nil {
+		return err
+	}
+
 	// obtain fresh module state after the above operations finished
 	mod, err = modMgr.ModuleByPath(fh.Dir())
 	if err != nil {
@@ -98,6 +104,9 @@ func TextDocumentDidChange(ctx context.Context, params lsp.DidChangeTextDocument
 	diags.EmptyRootDiagnostic()
 	diags.Append("HCL", mod.ModuleDiagnostics.AsMap())
 	diags.Append("HCL", mod.VarsDiagnostics.AutoloadedOnly().AsMap())
+	if expFeatures.ValidateOnChange {
+		diags.AppendProblems(validation.ModuleProblems(modMgr, mod))
+	}
 	if vf, ok := ast.NewVarsFilename(f.Filename()); ok && !vf.IsAutoloaded() {
 		diags.Append("HCL", mod.VarsDiagnostics.ForFile(vf).AsMap())
 	}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/creachadair/jrpc2"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/settings"
+)
+
+// configurationSection represents the section of client settings
+// which the server options are read from
+const configurationSection = "terraform-ls"
+
+func (svc *service) DidChangeConfiguration(ctx context.Context, params lsp.DidChangeConfigurationParams) error {
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := params.Settings
+	if cc.Workspace.Configuration {
+		// clients supporting pull model may not send
+		// any settings with the notification at all
+		input, err = svc.pullConfiguration(ctx)
+		if err != nil {
+			return err
+		}
+	} else if m, ok := input.(map[string]interface{}); ok {
+		if section, ok := m[configurationSection]; ok {
+			input = section
+		}
+	}
+
+	out, err := settings.DecodeOptions(input)
+	if err != nil {
+		return err
+	}
+	err = out.Options.Validate()
+	if err != nil {
+		return err
+	}
+
+	if len(out.UnusedKeys) > 0 {
+		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+			Type:    lsp.Warning,
+			Message: fmt.Sprintf("Unknown configuration options: %q", out.UnusedKeys),
+		})
+	}
+
+	return svc.reconfigure(ctx, out.Options)
+}
+
+func (svc *service) pullConfiguration(ctx context.Context) (interface{}, error) {
+	rsp, err := jrpc2.ServerFromContext(ctx).Callback(ctx, "workspace/configuration", lsp.ConfigurationParams{
+		Items: []lsp.ConfigurationItem{
+			{Section: configurationSection},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []interface{}
+	err = rsp.UnmarshalResult(&results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("expected 1 configuration item, %d received", len(results))
+	}
+
+	return results[0], nil
+}
+
+// reconfigure applies changed options to the running session
+// while retaining any open documents and indexed modules
+func (svc *service) reconfigure(ctx context.Context, cfgOpts *settings.Options) error {
+	// options are compared with the previous ones below,
+	// so reconfiguration must not run concurrently
+	svc.optionsMu.Lock()
+	defer svc.optionsMu.Unlock()
+
+	execOpts, err := svc.terraformExecOptions(cfgOpts)
+	if err != nil {
+		return err
+	}
+	// options are shared with the module manager and any running
+	// operations, which read a snapshot of them
+	svc.tfExecOpts.Set(execOpts)
+
+	err = lsctx.SetExperimentalFeatures(ctx, cfgOpts.ExperimentalFeatures)
+	if err != nil {
+		return err
+	}
+	err = lsctx.SetFormatter(ctx, cfgOpts.Formatter)
+	if err != nil {
+		return err
+	}
+	err = lsctx.SetRenameOptions(ctx, cfgOpts.Rename)
+	if err != nil {
+		return err
+	}
+	err = lsctx.SetOrganizeAttributesOptions(ctx, cfgOpts.OrganizeAttributes)
+	if err != nil {
+		return err
+	}
+
+	oldOpts := svc.options
+	svc.options = cfgOpts
+
+	if cfgOpts.CommandPrefix != oldOpts.CommandPrefix {
+		svc.logger.Printf("Ignoring changed command prefix %q, commands were already registered with %q",
+			cfgOpts.CommandPrefix, oldOpts.CommandPrefix)
+	}
+
+	rootDir, _ := lsctx.RootDirectory(ctx)
+
+	oldExcludeModulePaths := svc.excludeModulePaths(rootDir, oldOpts)
+	excludeModulePaths := svc.excludeModulePaths(rootDir, cfgOpts)
+	svc.walker.SetExcludeModulePaths(excludeModulePaths)
+
+	// newly excluded modules are no longer indexed
+	for _, modPath := range excludeModulePaths {
+		if containsString(oldExcludeModulePaths, modPath) {
+			continue
+		}
+		err := svc.removeModulesWithin(modPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	// modules of removed root module paths are no longer indexed,
+	// unless they're within the root directory indexed anyway
+	for _, rawPath := range oldOpts.ModulePaths {
+		if containsString(cfgOpts.ModulePaths, rawPath) {
+			continue
+		}
+		modPath, err := resolvePath(rootDir, rawPath)
+		if err != nil {
+			// path was ignored when added
+			continue
+		}
+		if rootDir != "" && isPathWithin(modPath, rootDir) {
+			continue
+		}
+		err = svc.removeModulesWithin(modPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	pathsToWalk := make([]string, 0)
+
+	// previously excluded modules are now indexed
+	for _, modPath := range oldExcludeModulePaths {
+		if !containsString(excludeModulePaths, modPath) {
+			pathsToWalk = append(pathsToWalk, modPath)
+		}
+	}
+
+	for _, rawPath := range cfgOpts.ModulePaths {
+		if containsString(oldOpts.ModulePaths, rawPath) {
+			continue
+		}
+		modPath, err := resolvePath(rootDir, rawPath)
+		if err != nil {
+			jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+				Type:    lsp.Warning,
+				Message: fmt.Sprintf("Ignoring module path %s: %s", rawPath, err),
+			})
+			continue
+		}
+
+		err = svc.watcher.AddModule(modPath)
+		if err != nil {
+			return err
+		}
+		pathsToWalk = append(pathsToWalk, modPath)
+	}
+
+	if len(pathsToWalk) == 0 {
+		return nil
+	}
+
+	for _, modPath := range pathsToWalk {
+		svc.logger.Printf("walking newly included path %s", modPath)
+		svc.walker.EnqueuePath(modPath)
+	}
+
+	if !svc.walker.IsWalking() {
+		// Walker runs asynchronously so we're intentionally *not*
+		// passing the request context here
+		return svc.walker.StartWalking(context.Background())
+	}
+
+	return nil
+}
+
+// removeModulesWithin removes modules in the given directory
+// or any of its subdirectories from the index and the watcher
+func (svc *service) removeModulesWithin(dirPath string) error {
+	mods, err := svc.modMgr.ListModules()
+	if err != nil {
+		return err
+	}
+	for _, mod := range mods {
+		if !isPathWithin(mod.Path, dirPath) {
+			continue
+		}
+		svc.logger.Printf("removing module %s", mod.Path)
+		err := svc.watcher.RemoveModule(mod.Path)
+		if err != nil {
+			return err
+		}
+		err = svc.modMgr.RemoveModule(mod.Path)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isPathWithin returns true if path is the given directory
+// or any path within it
+func isPathWithin(path, dirPath string) bool {
+	return path == dirPath || strings.HasPrefix(path, dirPath+string(filepath.Separator))
+}
+
+func containsString(slice []string, s string) bool {
+	for _, item := range slice {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_didChangeConfiguration_push(t *testing.T) {
+	tmpDir := TempDir(t, "excluded/.terraform")
+	excludedDir := filepath.Join(tmpDir.Dir(), "excluded")
+	err := ioutil.WriteFile(filepath.Join(excludedDir, "main.tf"), []byte(`variable "name" {
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				excludedDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "initializationOptions": {
+	    	"excludeModulePaths": ["excluded"]
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeConfiguration",
+		ReqParams: `{
+		"settings": {
+			"terraform-ls": {
+				"excludeModulePaths": []
+			}
+		}
+	}`})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/excluded/main.tf"
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": %q,
+				"items": [
+					{
+						"range": {
+							"start": {"line": 1, "character": 0},
+							"end": {"line": 1, "character": 0}
+						},
+						"severity": 1,
+						"source": "HCL",
+						"message": "Argument or block definition required: An argument or block definition is required here."
+					}
+				]
+			}
+		}`, unterminatedBlockResultID()))
+}
+
+func TestLangServer_didChangeConfiguration_pull(t *testing.T) {
+	tmpDir := TempDir(t, "excluded/.terraform")
+	excludedDir := filepath.Join(tmpDir.Dir(), "excluded")
+	err := ioutil.WriteFile(filepath.Join(excludedDir, "main.tf"), []byte(`variable "name" {
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				excludedDir: validTfMockCalls(),
+			},
+		},
+	}))
+	var requestedSection string
+	ls.OnCallback(func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+		var params struct {
+			Items []struct {
+				Section string `json:"section"`
+			} `json:"items"`
+		}
+		err := req.UnmarshalParams(&params)
+		if err != nil {
+			return nil, err
+		}
+		requestedSection = params.Items[0].Section
+
+		return []interface{}{
+			map[string]interface{}{
+				"excludeModulePaths": []string{},
+			},
+		}, nil
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"configuration": true
+	    	}
+	    },
+	    "initializationOptions": {
+	    	"excludeModulePaths": ["excluded"]
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method:    "workspace/didChangeConfiguration",
+		ReqParams: `{"settings": null}`})
+	if requestedSection != "terraform-ls" {
+		t.Fatalf("expected configuration of %q to be pulled, given: %q",
+			"terraform-ls", requestedSection)
+	}
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/excluded/main.tf"
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": %q,
+				"items": [
+					{
+						"range": {
+							"start": {"line": 1, "character": 0},
+							"end": {"line": 1, "character": 0}
+						},
+						"severity": 1,
+						"source": "HCL",
+						"message": "Argument or block definition required: An argument or block definition is required here."
+					}
+				]
+			}
+		}`, unterminatedBlockResultID()))
+}
+
+func TestLangServer_didChangeConfiguration_excludeIndexedModule(t *testing.T) {
+	tmpDir := TempDir(t, "excluded/.terraform")
+	excludedDir := filepath.Join(tmpDir.Dir(), "excluded")
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				excludedDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"name\" {}\n",
+			"uri": "%s/excluded/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeConfiguration",
+		ReqParams: `{
+		"settings": {
+			"terraform-ls": {
+				"excludeModulePaths": ["excluded"]
+			}
+		}
+	}`})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "workspace/diagnostic",
+		ReqParams: `{"previousResultIds": []}`}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"items": []
+			}
+		}`)
+}
+
+func TestLangServer_didChangeConfiguration_removeRootModulePath(t *testing.T) {
+	tmpDir := TempDir(t, "root", "other/.terraform")
+	rootDir := filepath.Join(tmpDir.Dir(), "root")
+	otherDir := filepath.Join(tmpDir.Dir(), "other")
+	err := ioutil.WriteFile(filepath.Join(otherDir, "main.tf"), []byte("variable \"name\" {}\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir:  validTfMockCalls(),
+				otherDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": "%s/root",
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeConfiguration",
+		ReqParams: fmt.Sprintf(`{
+		"settings": {
+			"terraform-ls": {
+				"rootModulePaths": [%q]
+			}
+		}
+	}`, otherDir)})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "workspace/diagnostic",
+		ReqParams: `{"previousResultIds": []}`}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"items": [
+					{
+						"uri": "%s/other/main.tf",
+						"version": 0,
+						"kind": "full",
+						"resultId": %q,
+						"items": []
+					}
+				]
+			}
+		}`, tmpDir.URI(), ilsp.DiagnosticsResultID([]lsp.Diagnostic{})))
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeConfiguration",
+		ReqParams: `{
+		"settings": {
+			"terraform-ls": {
+				"rootModulePaths": []
+			}
+		}
+	}`})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method:    "workspace/diagnostic",
+		ReqParams: `{"previousResultIds": []}`}, `{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"items": []
+			}
+		}`)
+}
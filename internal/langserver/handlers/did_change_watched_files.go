@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+func (lh *logHandler) DidChangeWatchedFiles(ctx context.Context, params lsp.DidChangeWatchedFilesParams) error {
+	watcher, err := lsctx.Watcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	fcw, ok := watcher.(module.FileChangeWatcher)
+	if !ok {
+		lh.logger.Printf("ignoring %d file changes, files are watched by the server",
+			len(params.Changes))
+		return nil
+	}
+
+	for _, change := range params.Changes {
+		fh := ilsp.FileHandlerFromDocumentURI(change.URI)
+		if !fh.Valid() {
+			lh.logger.Printf("ignoring change of invalid URI: %q", change.URI)
+			continue
+		}
+
+		err := fcw.ProcessFileChange(fh.FullPath(), fileChangeType(change.Type))
+		if err != nil {
+			lh.logger.Printf("failed to process change of %q: %s", change.URI, err)
+		}
+	}
+
+	return nil
+}
+
+func fileChangeType(changeType lsp.FileChangeType) module.FileChangeType {
+	switch changeType {
+	case lsp.Created:
+		return module.FileCreated
+	case lsp.Deleted:
+		return module.FileDeleted
+	}
+	return module.FileChanged
+}
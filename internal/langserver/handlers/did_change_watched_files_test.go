@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_didChangeWatchedFiles(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	err := os.Mkdir(filepath.Join(tmpDir.Dir(), ".terraform"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(tmpDir.Dir(), "main.tf"), []byte(`variable "name" {}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"didChangeWatchedFiles": {
+	    			"dynamicRegistration": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	// file changed outside of the editor
+	err = ioutil.WriteFile(filepath.Join(tmpDir.Dir(), "main.tf"), []byte(`variable "name" {
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didChangeWatchedFiles",
+		ReqParams: fmt.Sprintf(`{
+		"changes": [
+			{
+				"uri": "%s/main.tf",
+				"type": 2
+			}
+		]
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/diagnostic",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"kind": "full",
+				"resultId": %q,
+				"items": [
+					{
+						"range": {
+							"start": {"line": 1, "character": 0},
+							"end": {"line": 1, "character": 0}
+						},
+						"severity": 1,
+						"source": "HCL",
+						"message": "Argument or block definition required: An argument or block definition is required here."
+					}
+				]
+			}
+		}`, unterminatedBlockResultID()))
+}
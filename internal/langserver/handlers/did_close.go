@@ -23,6 +23,12 @@ func TextDocumentDidClose(ctx context.Context, params lsp.DidCloseTextDocumentPa
 		return err
 	}
 
+	cache, err := lsctx.SemanticTokensCache(ctx)
+	if err != nil {
+		return err
+	}
+	cache.Forget(params.TextDocument.URI)
+
 	if vf, ok := ast.NewVarsFilename(fh.Filename()); ok && !vf.IsAutoloaded() {
 		notifier, err := lsctx.DiagnosticsNotifier(ctx)
 		if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/validation"
 )
 
 func (lh *logHandler) TextDocumentDidOpen(ctx context.Context, params lsp.DidOpenTextDocumentParams) error {
@@ -75,10 +76,18 @@ func (lh *logHandler) TextDocumentDidOpen(ctx context.Context, params lsp.DidOpe
 		return err
 	}
 
+	expFeatures, err := lsctx.ExperimentalFeatures(ctx)
+	if err != nil {
+		return err
+	}
+
 	diags := diagnostics.NewDiagnostics()
 	diags.EmptyRootDiagnostic()
 	diags.Append("HCL", mod.ModuleDiagnostics.AsMap())
 	diags.Append("HCL", mod.VarsDiagnostics.AutoloadedOnly().AsMap())
+	if expFeatures.ValidateOnChange {
+		diags.AppendProblems(validation.ModuleProblems(modMgr, mod))
+	}
 	if vf, ok := ast.NewVarsFilename(f.Filename()); ok && !vf.IsAutoloaded() {
 		diags.Append("HCL", mod.VarsDiagnostics.ForFile(vf).AsMap())
 	}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentHighlight(ctx context.Context, params lsp.DocumentHighlightParams) ([]lsp.DocumentHighlight, error) {
+	list := make([]lsp.DocumentHighlight, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return list, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return list, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return list, err
+	}
+
+	schema, err := schemaForDocument(mf, file)
+	if err != nil {
+		return list, err
+	}
+
+	d, err := decoderForDocument(ctx, mod, file.LanguageID())
+	if err != nil {
+		return list, err
+	}
+	d.SetSchema(schema)
+
+	fPos, err := ilsp.FilePositionFromDocumentPosition(params.TextDocumentPositionParams, file)
+	if err != nil {
+		return list, err
+	}
+
+	fileTargets, err := d.ReferenceTargetsInFile(fPos.Filename())
+	if err != nil {
+		return list, err
+	}
+
+	// declaration under the cursor
+	refTargets := make(lang.ReferenceTargets, 0)
+	for _, refTarget := range fileTargets {
+		if refTarget.DefRangePtr != nil && refTarget.DefRangePtr.ContainsPos(fPos.Position()) {
+			refTargets = append(refTargets, refTarget)
+		}
+	}
+
+	// reference under the cursor
+	if len(refTargets) == 0 {
+		origin, err := d.ReferenceOriginAtPos(fPos.Filename(), fPos.Position())
+		if err != nil {
+			h.logger.Printf("unable to find origin at %s - %#v: %s",
+				fPos.Filename(), fPos.Position(), err)
+			return list, nil
+		}
+		if origin == nil {
+			return list, nil
+		}
+
+		refTarget, err := d.ReferenceTargetForOrigin(*origin)
+		if err != nil {
+			return list, err
+		}
+		if refTarget == nil {
+			return list, nil
+		}
+		refTargets = append(refTargets, *refTarget)
+	}
+
+	for _, refTarget := range refTargets {
+		if refTarget.RangePtr != nil && refTarget.RangePtr.Filename == fPos.Filename() {
+			if highlight, ok := ilsp.RefTargetToWriteHighlight(refTarget); ok {
+				list = appendHighlight(list, highlight)
+			}
+		}
+
+		origins, err := d.ReferenceOriginsTargeting(refTarget)
+		if err != nil {
+			return list, err
+		}
+
+		fileOrigins := make(lang.ReferenceOrigins, 0)
+		for _, origin := range origins {
+			if origin.Range.Filename == fPos.Filename() {
+				fileOrigins = append(fileOrigins, origin)
+			}
+		}
+		for _, highlight := range ilsp.RefOriginsToReadHighlights(fileOrigins) {
+			list = appendHighlight(list, highlight)
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Range.Start.Line != list[j].Range.Start.Line {
+			return list[i].Range.Start.Line < list[j].Range.Start.Line
+		}
+		return list[i].Range.Start.Character < list[j].Range.Start.Character
+	})
+
+	return list, nil
+}
+
+// appendHighlight appends the highlight unless the same range
+// is already highlighted, since there can be more targets
+// pointing to the same range (e.g. a block targettable
+// as type-less reference and as an object)
+func appendHighlight(list []lsp.DocumentHighlight, highlight lsp.DocumentHighlight) []lsp.DocumentHighlight {
+	for _, h := range list {
+		if h.Range == highlight.Range {
+			return list
+		}
+	}
+	return append(list, highlight)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_documentHighlight(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": `+fmt.Sprintf("%q",
+			`locals {
+  tags = {
+    Name = "foo"
+  }
+}
+
+output "first" {
+  value = local.tags
+}
+
+output "second" {
+  value = "${local.tags}"
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	expectedHighlights := `[
+		{
+			"range": {
+				"start": {
+					"line": 1,
+					"character": 2
+				},
+				"end": {
+					"line": 1,
+					"character": 6
+				}
+			},
+			"kind": 3
+		},
+		{
+			"range": {
+				"start": {
+					"line": 7,
+					"character": 10
+				},
+				"end": {
+					"line": 7,
+					"character": 20
+				}
+			},
+			"kind": 2
+		},
+		{
+			"range": {
+				"start": {
+					"line": 11,
+					"character": 13
+				},
+				"end": {
+					"line": 11,
+					"character": 23
+				}
+			},
+			"kind": 2
+		}
+	]`
+
+	// reference
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 7,
+				"character": 18
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": %s
+		}`, expectedHighlights))
+
+	// declaration
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/documentHighlight",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 3
+			}
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": %s
+		}`, expectedHighlights))
+}
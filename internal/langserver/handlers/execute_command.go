@@ -17,6 +17,8 @@ var handlers = cmd.Handlers{
 	cmd.Name("terraform.init"):     command.TerraformInitHandler,
 	cmd.Name("terraform.validate"): command.TerraformValidateHandler,
 	cmd.Name("module.calls"):       command.ModuleCallsHandler,
+	cmd.Name("convert.toHCL"):      command.ConvertToHCLHandler,
+	cmd.Name("convert.toJSON"):     command.ConvertToJSONHandler,
 }
 
 func (lh *logHandler) WorkspaceExecuteCommand(ctx context.Context, params lsp.ExecuteCommandParams) (interface{}, error) {
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/code"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/langserver/cmd"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_workspaceExecuteCommand_convert_argumentError(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q
+	}`, cmd.Name("convert.toJSON"))}, code.InvalidParams.Err())
+
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s/main.tf"]
+	}`, cmd.Name("convert.toHCL"), rootUri)}, code.InvalidParams.Err())
+}
+
+func TestLangServer_workspaceExecuteCommand_convertToJSON(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"name\" {\n  type = string\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, rootUri)})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s/main.tf"]
+	}`, cmd.Name("convert.toJSON"), rootUri)}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"v": 0,
+			"uri": "%s/main.tf.json",
+			"applied": false,
+			"droppedComments": 0,
+			"content": "{\n  \"variable\": {\n    \"name\": {\n      \"type\": \"string\"\n    }\n  }\n}\n"
+		}
+	}`, rootUri))
+}
+
+func TestLangServer_workspaceExecuteCommand_convertToJSON_droppedComments(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	ls.OnCallback(func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+		t.Errorf("unexpected callback: %s", req.Method())
+		return map[string]interface{}{"applied": false}, nil
+	})
+	messages := make(chan lsp.ShowMessageParams, 1)
+	ls.OnNotify(func(req *jrpc2.Request) {
+		if req.Method() != "window/showMessage" {
+			return
+		}
+		var params lsp.ShowMessageParams
+		err := req.UnmarshalParams(&params)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		messages <- params
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"applyEdit": true,
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create", "delete"]
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+		"processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"name\" {\n  # names are lowercase\n  type = string # no default\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, rootUri)})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s/main.tf"]
+	}`, cmd.Name("convert.toJSON"), rootUri)}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"v": 0,
+			"uri": "%s/main.tf.json",
+			"applied": false,
+			"droppedComments": 2,
+			"content": "{\n  \"variable\": {\n    \"name\": {\n      \"type\": \"string\"\n    }\n  }\n}\n"
+		}
+	}`, rootUri))
+
+	// the original file is left alone, as not all comments could be converted
+	select {
+	case msg := <-messages:
+		expectedMsg := "2 comment(s) in main.tf could not be converted, the original file was left unchanged"
+		if msg.Type != lsp.Warning || msg.Message != expectedMsg {
+			t.Fatalf("unexpected message: %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for warning")
+	}
+}
+
+func TestLangServer_workspaceExecuteCommand_convertToJSON_applyEdit(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	var resourceOps []string
+	ls.OnCallback(func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+		var params struct {
+			Edit struct {
+				DocumentChanges []json.RawMessage `json:"documentChanges"`
+			} `json:"edit"`
+		}
+		err := req.UnmarshalParams(&params)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range params.Edit.DocumentChanges {
+			var op struct {
+				Kind string `json:"kind"`
+			}
+			err := json.Unmarshal(change, &op)
+			if err != nil {
+				return nil, err
+			}
+			if op.Kind != "" {
+				resourceOps = append(resourceOps, op.Kind)
+			}
+		}
+		return map[string]interface{}{"applied": true}, nil
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"applyEdit": true,
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create", "delete"]
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+		"processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"name\" {\n  type = string\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, rootUri)})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s/main.tf"]
+	}`, cmd.Name("convert.toJSON"), rootUri)}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"v": 0,
+			"uri": "%s/main.tf.json",
+			"applied": true,
+			"droppedComments": 0
+		}
+	}`, rootUri))
+
+	// the original file is replaced in the same edit
+	expectedOps := []string{"create", "delete"}
+	if diff := cmp.Diff(expectedOps, resourceOps); diff != "" {
+		t.Fatalf("unexpected resource operations: %s", diff)
+	}
+}
+
+func TestLangServer_workspaceExecuteCommand_convertToHCL(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+
+	src := []byte(`{
+  "variable": {
+    "name": {
+      "type": "string"
+    }
+  },
+  "locals": {
+    "greeting": "Hello ${var.name}"
+  }
+}
+`)
+	err := os.WriteFile(filepath.Join(rootDir, "main.tf.json"), src, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+		"processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "",
+			"uri": "%s/outputs.tf"
+		}
+	}`, rootUri)})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/executeCommand",
+		ReqParams: fmt.Sprintf(`{
+		"command": %q,
+		"arguments": ["uri=%s/main.tf.json"]
+	}`, cmd.Name("convert.toHCL"), rootUri)}, fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id": 3,
+		"result": {
+			"v": 0,
+			"uri": "%s/main.tf",
+			"applied": false,
+			"droppedComments": 0,
+			"content": "variable \"name\" {\n  type = string\n}\n\nlocals {\n  greeting = \"Hello ${var.name}\"\n}\n"
+		}
+	}`, rootUri))
+}
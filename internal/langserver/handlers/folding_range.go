@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ihcl "github.com/hashicorp/terraform-ls/internal/hcl"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentFoldingRange(ctx context.Context, params lsp.FoldingRangeParams) ([]lsp.FoldingRange, error) {
+	ranges := make([]lsp.FoldingRange, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return ranges, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return ranges, err
+	}
+
+	f, ok := parsedFileForDocument(mod, file)
+	if !ok {
+		h.logger.Printf("file not parsed yet: %s", file.FullPath())
+		return ranges, nil
+	}
+
+	frs := ihcl.FoldingRanges(f, file.Filename())
+
+	return ilsp.FoldingRanges(frs, cc.TextDocument.FoldingRange.LineFoldingOnly), nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"strings"
 
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
@@ -9,10 +10,13 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/langserver/errors"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
-	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 )
 
+type formatFunc func(ctx context.Context, original []byte) ([]byte, error)
+
 func (h *logHandler) TextDocumentFormatting(ctx context.Context, params lsp.DocumentFormattingParams) ([]lsp.TextEdit, error) {
 	var edits []lsp.TextEdit
 
@@ -23,9 +27,9 @@ func (h *logHandler) TextDocumentFormatting(ctx context.Context, params lsp.Docu
 
 	fh := ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI)
 
-	tfExec, err := module.TerraformExecutorForModule(ctx, fh.Dir())
+	format, err := h.formatterForDocument(ctx, fh)
 	if err != nil {
-		return edits, errors.EnrichTfExecError(err)
+		return edits, err
 	}
 
 	file, err := fs.GetDocument(fh)
@@ -38,9 +42,7 @@ func (h *logHandler) TextDocumentFormatting(ctx context.Context, params lsp.Docu
 		return edits, err
 	}
 
-	h.logger.Printf("formatting document via %q", tfExec.GetExecPath())
-
-	edits, err = formatDocument(ctx, tfExec, original, file)
+	edits, err = formatDocument(ctx, format, original, file)
 	if err != nil {
 		return edits, err
 	}
@@ -48,10 +50,95 @@ func (h *logHandler) TextDocumentFormatting(ctx context.Context, params lsp.Docu
 	return edits, nil
 }
 
-func formatDocument(ctx context.Context, tfExec exec.TerraformExecutor, original []byte, file filesystem.Document) ([]lsp.TextEdit, error) {
+func (h *logHandler) TextDocumentRangeFormatting(ctx context.Context, params lsp.DocumentRangeFormattingParams) ([]lsp.TextEdit, error) {
 	var edits []lsp.TextEdit
 
-	formatted, err := tfExec.Format(ctx, original)
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return edits, err
+	}
+
+	fh := ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI)
+
+	format, err := h.formatterForDocument(ctx, fh)
+	if err != nil {
+		return edits, err
+	}
+
+	file, err := fs.GetDocument(fh)
+	if err != nil {
+		return edits, err
+	}
+
+	original, err := file.Text()
+	if err != nil {
+		return edits, err
+	}
+
+	// formatters can only format whole files, so we format
+	// the whole file and only pick changes within the range
+	formatted, err := format(ctx, original)
+	if err != nil {
+		return edits, err
+	}
+
+	changes := hcl.Diff(file, original, formatted)
+
+	return ilsp.TextEditsFromDocumentChanges(changesWithinLines(changes,
+		int(params.Range.Start.Line), int(params.Range.End.Line))), nil
+}
+
+// formatterForDocument returns function to format the document with,
+// which is either Terraform CLI, or the native formatter if preferred
+// by the user or if Terraform CLI is not available.
+// Attributes are organized prior to formatting if the user opted in.
+func (h *logHandler) formatterForDocument(ctx context.Context, fh ilsp.FileHandler) (formatFunc, error) {
+	format, err := h.formatter(ctx, fh)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := lsctx.OrganizeAttributesOptions(ctx)
+	if err != nil || !opts.OnFormat || !strings.HasSuffix(fh.Filename(), ".tf") {
+		return format, nil
+	}
+
+	filename := fh.Filename()
+	order := opts.AttributesOrder()
+	return func(ctx context.Context, original []byte) ([]byte, error) {
+		if organized, ok := refactor.OrganizeAttributes(original, filename, order); ok {
+			original = organized
+		}
+		return format(ctx, original)
+	}, nil
+}
+
+func (h *logHandler) formatter(ctx context.Context, fh ilsp.FileHandler) (formatFunc, error) {
+	formatter, _ := lsctx.Formatter(ctx)
+
+	if formatter != settings.NativeFormatter {
+		tfExec, err := module.TerraformExecutorForModule(ctx, fh.Dir())
+		if err == nil {
+			h.logger.Printf("formatting document via %q", tfExec.GetExecPath())
+			return tfExec.Format, nil
+		}
+		if !module.IsTerraformNotFound(err) {
+			return nil, errors.EnrichTfExecError(err)
+		}
+		h.logger.Printf("Terraform CLI not found, falling back to native formatter")
+	}
+
+	h.logger.Printf("formatting document natively")
+	filename := fh.Filename()
+	return func(_ context.Context, original []byte) ([]byte, error) {
+		return hcl.Format(original, filename)
+	}, nil
+}
+
+func formatDocument(ctx context.Context, format formatFunc, original []byte, file filesystem.Document) ([]lsp.TextEdit, error) {
+	var edits []lsp.TextEdit
+
+	formatted, err := format(ctx, original)
 	if err != nil {
 		return edits, err
 	}
@@ -60,3 +147,24 @@ func formatDocument(ctx context.Context, tfExec exec.TerraformExecutor, original
 
 	return ilsp.TextEditsFromDocumentChanges(changes), nil
 }
+
+// changesWithinLines filters out any changes affecting lines
+// outside of the given (0-based, inclusive) range of lines
+func changesWithinLines(changes filesystem.DocumentChanges, startLine, endLine int) filesystem.DocumentChanges {
+	filtered := make(filesystem.DocumentChanges, 0)
+	for _, change := range changes {
+		rng := change.Range()
+		if rng == nil {
+			continue
+		}
+		if rng.Start.Line < startLine {
+			continue
+		}
+		// line changes end at the beginning of the next line
+		if rng.End.Line > endLine && !(rng.End.Line == endLine+1 && rng.End.Column == 0) {
+			continue
+		}
+		filtered = append(filtered, change)
+	}
+	return filtered
+}
@@ -34,28 +34,38 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 				},
 				"completionProvider": {
 					"triggerCharacters": [".", "["],
+					"resolveProvider": true,
 					"completionItem":{}
 				},
 				"hoverProvider": true,
-				"signatureHelpProvider": {},
+				"signatureHelpProvider": {
+					"triggerCharacters": ["(", ","]
+				},
 				"declarationProvider": {},
 				"definitionProvider": true,
 				"referencesProvider": true,
+				"documentHighlightProvider": true,
 				"documentSymbolProvider": true,
 				"codeActionProvider": {
-					"codeActionKinds": ["source", "source.fixAll", "source.formatAll", "source.formatAll.terraform-ls"]
+					"codeActionKinds": ["quickfix", "refactor.extract", "refactor.extract.module", "refactor.rewrite", "source", "source.fixAll", "source.fixAll.terraform-ls.upgrade", "source.formatAll", "source.formatAll.terraform-ls", "source.generateOutputs", "source.generateTfvars", "source.generateVariables", "source.organizeAttributes"]
 				},
 				"codeLensProvider": {},
 				"documentLinkProvider": {},
 				"workspaceSymbolProvider": true,
 				"documentFormattingProvider": true,
+				"documentRangeFormattingProvider": true,
 				"documentOnTypeFormattingProvider": {
-					"firstTriggerCharacter": ""
+					"firstTriggerCharacter": "\n",
+					"moreTriggerCharacter": ["}"]
 				},
+				"renameProvider": true,
+				"foldingRangeProvider": true,
+				"selectionRangeProvider": true,
 				"executeCommandProvider": {
 					"commands": %s,
 					"workDoneProgress":true
 				},
+				"callHierarchyProvider": true,
 				"semanticTokensProvider": {
 					"legend": {
 						"tokenTypes": [],
@@ -64,11 +74,38 @@ func initializeResponse(t *testing.T, commandPrefix string) string {
 					"full": false
 				},
 				"workspace": {
+					"fileOperations": {
+						"didRename": {
+							"filters": [
+								{
+									"scheme": "file",
+									"pattern": {
+										"glob": "**",
+										"matches": "folder",
+										"options": {}
+									}
+								}
+							]
+						},
+						"willRename": {
+							"filters": [
+								{
+									"scheme": "file",
+									"pattern": {
+										"glob": "**",
+										"matches": "folder",
+										"options": {}
+									}
+								}
+							]
+						}
+					},
 					"workspaceFolders": {
 						"supported": true,
 						"changeNotifications": "workspace/didChangeWorkspaceFolders"
 					}
-				}
+				},
+				"inlayHintProvider": true
 			},
 			"serverInfo": {
 				"name": "terraform-ls"
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/functions"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
@@ -50,6 +51,20 @@ func (h *logHandler) TextDocumentHover(ctx context.Context, params lsp.TextDocum
 		return nil, err
 	}
 
+	if file.LanguageID() == ilsp.Terraform.String() {
+		text, err := file.Text()
+		if err != nil {
+			return nil, err
+		}
+		name, rng, ok := functions.NameAtPos(text, fPos.Filename(), fPos.Position())
+		if ok {
+			f, ok := functions.FunctionsForVersion(mod.TerraformVersion)[name]
+			if ok {
+				return ilsp.FunctionHover(f, rng, cc.TextDocument), nil
+			}
+		}
+	}
+
 	h.logger.Printf("Looking for hover data at %q -> %#v", file.Filename(), fPos.Position())
 	hoverData, err := d.HoverAtPos(file.Filename(), fPos.Position())
 	h.logger.Printf("received hover data: %#v", hoverData)
@@ -14,35 +14,55 @@ import (
 	"github.com/mitchellh/go-homedir"
 )
 
-func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams) (lsp.InitializeResult, error) {
-	serverCaps := lsp.InitializeResult{
-		Capabilities: lsp.ServerCapabilities{
-			TextDocumentSync: lsp.TextDocumentSyncOptions{
-				OpenClose: true,
-				Change:    lsp.Incremental,
-			},
-			CompletionProvider: lsp.CompletionOptions{
-				ResolveProvider:   false,
-				TriggerCharacters: []string{".", "["},
-			},
-			CodeActionProvider: lsp.CodeActionOptions{
-				CodeActionKinds: ilsp.SupportedCodeActions.AsSlice(),
-				ResolveProvider: false,
+func (svc *service) Initialize(ctx context.Context, params lsp.ExtendedInitializeParams) (lsp.ExtendedInitializeResult, error) {
+	serverCaps := lsp.ExtendedInitializeResult{
+		Capabilities: lsp.ExtendedServerCapabilities{
+			ServerCapabilities: lsp.ServerCapabilities{
+				TextDocumentSync: lsp.TextDocumentSyncOptions{
+					OpenClose: true,
+					Change:    lsp.Incremental,
+				},
+				CompletionProvider: lsp.CompletionOptions{
+					ResolveProvider:   true,
+					TriggerCharacters: []string{".", "["},
+				},
+				SignatureHelpProvider: lsp.SignatureHelpOptions{
+					TriggerCharacters: []string{"(", ","},
+				},
+				DocumentRangeFormattingProvider: true,
+				CallHierarchyProvider:           true,
+				DocumentOnTypeFormattingProvider: lsp.DocumentOnTypeFormattingOptions{
+					FirstTriggerCharacter: "\n",
+					MoreTriggerCharacter:  []string{"}"},
+				},
+				CodeActionProvider: lsp.CodeActionOptions{
+					CodeActionKinds: ilsp.SupportedCodeActions.AsSlice(),
+					ResolveProvider: false,
+				},
+				DeclarationProvider:        lsp.DeclarationOptions{},
+				DefinitionProvider:         true,
+				CodeLensProvider:           lsp.CodeLensOptions{},
+				ReferencesProvider:         true,
+				DocumentHighlightProvider:  true,
+				FoldingRangeProvider:       true,
+				SelectionRangeProvider:     true,
+				RenameProvider:             true,
+				HoverProvider:              true,
+				DocumentFormattingProvider: true,
+				DocumentSymbolProvider:     true,
+				WorkspaceSymbolProvider:    true,
 			},
-			DeclarationProvider:        lsp.DeclarationOptions{},
-			DefinitionProvider:         true,
-			CodeLensProvider:           lsp.CodeLensOptions{},
-			ReferencesProvider:         true,
-			HoverProvider:              true,
-			DocumentFormattingProvider: true,
-			DocumentSymbolProvider:     true,
-			WorkspaceSymbolProvider:    true,
-			Workspace: lsp.Workspace5Gn{
+			Workspace: lsp.ExtendedWorkspace{
 				WorkspaceFolders: lsp.WorkspaceFolders4Gn{
 					Supported:           true,
 					ChangeNotifications: "workspace/didChangeWorkspaceFolders",
 				},
+				FileOperations: &lsp.ExtendedFileOperationOptions{
+					WillRename: &ilsp.FolderRenameOptions,
+					DidRename:  &ilsp.FolderRenameOptions,
+				},
 			},
+			InlayHintProvider: true,
 		},
 	}
 
@@ -82,6 +102,25 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 		}
 	}
 
+	if clientCaps.TextDocument.Rename.PrepareSupport {
+		serverCaps.Capabilities.RenameProvider = lsp.RenameOptions{
+			PrepareProvider: true,
+		}
+	}
+
+	if params.ExtendedCapabilities.TextDocument.Diagnostic != nil {
+		serverCaps.Capabilities.DiagnosticProvider = lsp.DiagnosticOptions{
+			InterFileDependencies: true,
+			WorkspaceDiagnostics:  true,
+		}
+
+		notifier, err := lsctx.DiagnosticsNotifier(ctx)
+		if err != nil {
+			return serverCaps, err
+		}
+		notifier.SetPullDiagnostics(true)
+	}
+
 	err = lsctx.SetClientCapabilities(ctx, &clientCaps)
 	if err != nil {
 		return serverCaps, err
@@ -96,7 +135,7 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 		return serverCaps, err
 	}
 
-	err = svc.configureSessionDependencies(out.Options)
+	err = svc.configureSessionDependencies(out.Options, clientCaps)
 	if err != nil {
 		return serverCaps, err
 	}
@@ -110,7 +149,11 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 			TokenTypes:     ilsp.TokenTypesLegend(stCaps.TokenTypes).AsStrings(),
 			TokenModifiers: ilsp.TokenModifiersLegend(stCaps.TokenModifiers).AsStrings(),
 		},
-		Full: caps.FullRequest(),
+		Range: caps.RangeRequest(),
+		Full:  caps.FullRequest(),
+	}
+	if caps.FullDeltaRequest() {
+		semanticTokensOpts.Full = ilsp.SemanticTokensFullOptions{Delta: true}
 	}
 
 	serverCaps.Capabilities.SemanticTokensProvider = semanticTokensOpts
@@ -128,6 +171,12 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	// set experimental feature flags
 	lsctx.SetExperimentalFeatures(ctx, out.Options.ExperimentalFeatures)
 
+	// set preferred formatter
+	lsctx.SetFormatter(ctx, out.Options.Formatter)
+
+	lsctx.SetRenameOptions(ctx, out.Options.Rename)
+	lsctx.SetOrganizeAttributesOptions(ctx, out.Options.OrganizeAttributes)
+
 	if len(out.UnusedKeys) > 0 {
 		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
 			Type:    lsp.Warning,
@@ -144,17 +193,11 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 		})
 	}
 
-	var excludeModulePaths []string
-	for _, rawPath := range cfgOpts.ExcludeModulePaths {
-		modPath, err := resolvePath(rootDir, rawPath)
-		if err != nil {
-			svc.logger.Printf("Ignoring excluded module path %s: %s", rawPath, err)
-			continue
-		}
-		excludeModulePaths = append(excludeModulePaths, modPath)
-	}
+	svc.optionsMu.Lock()
+	svc.options = cfgOpts
+	svc.optionsMu.Unlock()
 
-	svc.walker.SetExcludeModulePaths(excludeModulePaths)
+	svc.walker.SetExcludeModulePaths(svc.excludeModulePaths(rootDir, cfgOpts))
 	svc.walker.EnqueuePath(fh.Dir())
 
 	// Walker runs asynchronously so we're intentionally *not*
@@ -209,6 +252,19 @@ func (svc *service) Initialize(ctx context.Context, params lsp.InitializeParams)
 	return serverCaps, nil
 }
 
+func (svc *service) excludeModulePaths(rootDir string, cfgOpts *settings.Options) []string {
+	var excludeModulePaths []string
+	for _, rawPath := range cfgOpts.ExcludeModulePaths {
+		modPath, err := resolvePath(rootDir, rawPath)
+		if err != nil {
+			svc.logger.Printf("Ignoring excluded module path %s: %s", rawPath, err)
+			continue
+		}
+		excludeModulePaths = append(excludeModulePaths, modPath)
+	}
+	return excludeModulePaths
+}
+
 func resolvePath(rootDir, rawPath string) (string, error) {
 	path, err := homedir.Expand(rawPath)
 	if err != nil {
@@ -3,9 +3,51 @@ package handlers
 import (
 	"context"
 
+	"github.com/creachadair/jrpc2"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
 )
 
-func Initialized(ctx context.Context, params lsp.InitializedParams) error {
+func (svc *service) Initialized(ctx context.Context, params lsp.InitializedParams) error {
+	if _, ok := svc.watcher.(module.FileChangeWatcher); ok {
+		svc.registerFileWatchers(ctx)
+	}
+
 	return nil
 }
+
+// registerFileWatchers asks the client to watch files
+// on behalf of the client-side watcher
+func (svc *service) registerFileWatchers(ctx context.Context) {
+	watchers := make([]lsp.FileSystemWatcher, 0, len(module.ClientWatcherPatterns))
+	for _, pattern := range module.ClientWatcherPatterns {
+		watchers = append(watchers, lsp.FileSystemWatcher{
+			GlobPattern: pattern,
+		})
+	}
+
+	params := lsp.RegistrationParams{
+		Registrations: []lsp.Registration{
+			{
+				ID:     "terraform-ls.watchedFiles",
+				Method: "workspace/didChangeWatchedFiles",
+				RegisterOptions: lsp.DidChangeWatchedFilesRegistrationOptions{
+					Watchers: watchers,
+				},
+			},
+		},
+	}
+
+	// The client may only respond after it processed this notification,
+	// so we cannot wait for the response here. The session context is used
+	// as the request context is cancelled once the handler returns.
+	srv := jrpc2.ServerFromContext(ctx)
+	go func() {
+		_, err := srv.Callback(svc.sessCtx, "client/registerCapability", params)
+		if err != nil {
+			svc.logger.Printf("failed to register file watchers: %s", err)
+			return
+		}
+		svc.logger.Printf("file watchers registered with the client")
+	}()
+}
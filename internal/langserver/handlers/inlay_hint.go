@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"os"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/eval"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentInlayHint(ctx context.Context, params lsp.InlayHintParams) ([]lsp.InlayHint, error) {
+	hints := make([]lsp.InlayHint, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return hints, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return hints, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return hints, err
+	}
+
+	if file.LanguageID() != ilsp.Terraform.String() {
+		// variable files only contain values
+		return hints, nil
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return hints, err
+	}
+
+	startLine, endLine := int(params.Range.Start.Line), int(params.Range.End.Line)
+	for _, hint := range eval.Hints(mod, file.Filename(), os.Environ()) {
+		line := hint.Pos.Line - 1
+		if line < startLine || line > endLine {
+			continue
+		}
+
+		inlayHint := lsp.InlayHint{
+			Position:    ilsp.HCLPosToLSP(hint.Pos),
+			Label:       hint.Label,
+			Tooltip:     hint.Tooltip,
+			PaddingLeft: true,
+		}
+		if hint.Kind == eval.TypeHint {
+			inlayHint.Kind = lsp.TypeInlayHint
+			inlayHint.PaddingLeft = false
+		}
+		hints = append(hints, inlayHint)
+	}
+
+	return hints, nil
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_inlayHint(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": `+fmt.Sprintf("%q",
+			`variable "name" {
+  default = "web"
+}
+
+locals {
+  prefix = "${var.name}-app"
+}
+
+output "prefix" {
+  value = local.prefix
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": {"line": 0, "character": 0},
+				"end": {"line": 11, "character": 0}
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"position": {"line": 0, "character": 15},
+					"label": "= \"web\"",
+					"tooltip": "Value from default",
+					"paddingLeft": true
+				},
+				{
+					"position": {"line": 5, "character": 22},
+					"label": "= \"web\"",
+					"tooltip": "Value from default",
+					"paddingLeft": true
+				},
+				{
+					"position": {"line": 9, "character": 22},
+					"label": ": string",
+					"kind": 1
+				}
+			]
+		}`)
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/inlayHint",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": {"line": 8, "character": 0},
+				"end": {"line": 10, "character": 0}
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": [
+				{
+					"position": {"line": 9, "character": 22},
+					"label": ": string",
+					"kind": 1
+				}
+			]
+		}`)
+}
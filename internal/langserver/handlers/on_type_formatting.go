@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"strings"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/hcl"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentOnTypeFormatting(ctx context.Context, params lsp.DocumentOnTypeFormattingParams) ([]lsp.TextEdit, error) {
+	edits := make([]lsp.TextEdit, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return edits, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return edits, err
+	}
+
+	// only native syntax of configuration can be formatted
+	if file.LanguageID() != ilsp.Terraform.String() || strings.HasSuffix(file.Filename(), ".json") {
+		return edits, nil
+	}
+
+	fPos, err := ilsp.FilePositionFromDocumentPosition(lsp.TextDocumentPositionParams{
+		TextDocument: params.TextDocument,
+		Position:     params.Position,
+	}, file)
+	if err != nil {
+		return edits, err
+	}
+
+	original, err := file.Text()
+	if err != nil {
+		return edits, err
+	}
+
+	formatted, ok := hcl.FormatEnclosingBlock(original, fPos.Filename(), fPos.Position())
+	if !ok {
+		return edits, nil
+	}
+
+	changes := hcl.Diff(file, original, formatted)
+
+	return ilsp.TextEditsFromDocumentChanges(changes), nil
+}
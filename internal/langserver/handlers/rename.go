@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/code"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+func (h *logHandler) TextDocumentPrepareRename(ctx context.Context, params lsp.PrepareRenameParams) (*lsp.Range, error) {
+	sym, err := h.symbolAtPos(ctx, params.TextDocumentPositionParams)
+	if err != nil {
+		return nil, err
+	}
+	if sym == nil {
+		return nil, nil
+	}
+
+	rng := ilsp.HCLRangeToLSP(sym.NameRange)
+	return &rng, nil
+}
+
+func (h *logHandler) TextDocumentRename(ctx context.Context, params lsp.RenameParams) (*lsp.ExtendedWorkspaceEdit, error) {
+	sym, err := h.symbolAtPos(ctx, lsp.TextDocumentPositionParams{
+		TextDocument: params.TextDocument,
+		Position:     params.Position,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sym == nil {
+		return nil, fmt.Errorf("%w: no renameable symbol at given position",
+			code.InvalidParams.Err())
+	}
+
+	mm, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadCallersOfModule(mm, sym.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	edits, err := refactor.Rename(mm, sym, params.NewName)
+	if err != nil {
+		var nameErr *refactor.InvalidNameError
+		var conflictErr *refactor.NameConflictError
+		if errors.As(err, &nameErr) || errors.As(err, &conflictErr) {
+			return nil, fmt.Errorf("%w: %s", code.InvalidParams.Err(), err)
+		}
+		return nil, err
+	}
+
+	h.logger.Printf("renaming %s %q to %q in %d files",
+		sym.Kind, sym.Name, params.NewName, len(edits))
+
+	// LSP rename carries no options besides the new name and code
+	// actions cannot ask for one, so moved blocks are opted into
+	// via settings and the user is told whenever they're added
+	renameOpts, err := lsctx.RenameOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !renameOpts.MovedBlocks {
+		return ilsp.ExtendedWorkspaceEdit(edits, nil), nil
+	}
+
+	cc, err := lsctx.ClientCapabilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	movedEdits, newFiles, err := refactor.MovedBlocks(mm, sym, params.NewName,
+		renameOpts.MovedBlocksFilename(), ilsp.SupportsFileCreation(cc))
+	if err != nil {
+		return nil, err
+	}
+	for path, fileEdits := range movedEdits {
+		for _, e := range fileEdits {
+			edits.Add(path, e.Range, e.NewText)
+		}
+	}
+
+	if len(movedEdits) > 0 {
+		jrpc2.ServerFromContext(ctx).Notify(ctx, "window/showMessage", &lsp.ShowMessageParams{
+			Type: lsp.Info,
+			Message: fmt.Sprintf("Renaming %s %q also adds a moved block, so that Terraform "+
+				"doesn't replace existing objects. This can be disabled via the rename.movedBlocks setting.",
+				sym.Kind, sym.Name),
+		})
+	}
+
+	return ilsp.ExtendedWorkspaceEdit(edits, newFiles), nil
+}
+
+func (h *logHandler) symbolAtPos(ctx context.Context, params lsp.TextDocumentPositionParams) (*refactor.Symbol, error) {
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+
+	fPos, err := ilsp.FilePositionFromDocumentPosition(params, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return refactor.SymbolAtPos(mf, file.Dir(), fPos.Filename(), fPos.Position())
+}
+
+// loadCallersOfModule ensures that configuration of all known callers
+// is parsed and decoded, since callers outside of the workspace
+// (or not yet opened) may not have been loaded yet
+func loadCallersOfModule(mm module.ModuleManager, modPath string) error {
+	callers, err := mm.CallersOfModule(modPath)
+	if err != nil {
+		return err
+	}
+
+	for _, caller := range callers {
+		if caller.RefOriginsState != op.OpStateUnknown {
+			continue
+		}
+
+		opTypes := []op.OpType{
+			op.OpTypeParseModuleConfiguration,
+			op.OpTypeParseVariables,
+			op.OpTypeLoadModuleMetadata,
+			op.OpTypeDecodeReferenceTargets,
+			op.OpTypeDecodeReferenceOrigins,
+		}
+		for _, opType := range opTypes {
+			err := mm.EnqueueModuleOpWait(caller.Path, opType)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
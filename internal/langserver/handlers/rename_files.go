@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/creachadair/jrpc2/code"
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+func (h *logHandler) WorkspaceWillRenameFiles(ctx context.Context, params lsp.RenameFilesParams) (lsp.WorkspaceEdit, error) {
+	var edit lsp.WorkspaceEdit
+
+	mm, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return edit, err
+	}
+
+	renames, err := pathRenames(params.Files)
+	if err != nil {
+		return edit, err
+	}
+
+	mods, err := mm.ListModules()
+	if err != nil {
+		return edit, err
+	}
+
+	// Both the moved modules and their callers
+	// need to be parsed to find all module blocks
+	for _, mod := range mods {
+		if !isRenamed(renames, mod.Path) {
+			continue
+		}
+		err = loadModuleConfiguration(mm, mod.Path)
+		if err != nil {
+			return edit, err
+		}
+		err = loadCallersOfModule(mm, mod.Path)
+		if err != nil {
+			return edit, err
+		}
+	}
+
+	edits, err := refactor.UpdateModuleSources(mm, renames)
+	if err != nil {
+		return edit, err
+	}
+
+	h.logger.Printf("updating module sources in %d files", len(edits))
+
+	return ilsp.WorkspaceEdit(edits), nil
+}
+
+func (h *logHandler) WorkspaceDidRenameFiles(ctx context.Context, params lsp.RenameFilesParams) error {
+	mm, err := lsctx.ModuleManager(ctx)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := lsctx.Watcher(ctx)
+	if err != nil {
+		return err
+	}
+
+	renames, err := pathRenames(params.Files)
+	if err != nil {
+		return err
+	}
+
+	mods, err := mm.ListModules()
+	if err != nil {
+		return err
+	}
+
+	for _, mod := range mods {
+		for _, r := range renames {
+			newPath, ok := r.Apply(mod.Path)
+			if !ok {
+				continue
+			}
+
+			h.logger.Printf("moving module %s to %s", mod.Path, newPath)
+			err := moveModule(mm, watcher, mod, newPath)
+			if err != nil {
+				h.logger.Printf("failed to move module %s: %s", mod.Path, err)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// moveModule replaces the module with one at the new path,
+// along with its watcher registration, and repeats any
+// operations which were previously performed on the module
+func moveModule(mm module.ModuleManager, watcher module.Watcher, mod module.Module, newPath string) error {
+	oldPath := mod.Path
+
+	err := mm.RemoveModule(oldPath)
+	if err != nil {
+		return err
+	}
+	_, err = mm.AddModule(newPath)
+	if err != nil {
+		return err
+	}
+
+	if watcher.IsModuleWatched(oldPath) {
+		err = watcher.RemoveModule(oldPath)
+		if err != nil {
+			return err
+		}
+		err = watcher.AddModule(newPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	parsingOps := []struct {
+		state  op.OpState
+		opType op.OpType
+	}{
+		{mod.ModuleParsingState, op.OpTypeParseModuleConfiguration},
+		{mod.VarsParsingState, op.OpTypeParseVariables},
+		{mod.MetaState, op.OpTypeLoadModuleMetadata},
+		{mod.RefTargetsState, op.OpTypeDecodeReferenceTargets},
+		{mod.RefOriginsState, op.OpTypeDecodeReferenceOrigins},
+	}
+	for _, o := range parsingOps {
+		if o.state == op.OpStateUnknown {
+			continue
+		}
+		err = mm.EnqueueModuleOpWait(newPath, o.opType)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Operations below may be slow (e.g. executing Terraform)
+	// so they are left to finish in the background
+	backgroundOps := []struct {
+		state  op.OpState
+		opType op.OpType
+	}{
+		{mod.ModManifestState, op.OpTypeParseModuleManifest},
+		{mod.TerraformVersionState, op.OpTypeGetTerraformVersion},
+		{mod.ProviderSchemaState, op.OpTypeObtainSchema},
+	}
+	for _, o := range backgroundOps {
+		if o.state == op.OpStateUnknown {
+			continue
+		}
+		err = mm.EnqueueModuleOp(newPath, o.opType, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pathRenames(files []lsp.FileRename) ([]refactor.PathRename, error) {
+	renames := make([]refactor.PathRename, 0, len(files))
+	for _, f := range files {
+		// paths are not resolved via cleanupPath as either
+		// the old or the new path does not exist at this point
+		oldPath, err := uri.PathFromURI(f.OldURI)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid URI %q: %s", code.InvalidParams.Err(), f.OldURI, err)
+		}
+		newPath, err := uri.PathFromURI(f.NewURI)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid URI %q: %s", code.InvalidParams.Err(), f.NewURI, err)
+		}
+
+		renames = append(renames, refactor.PathRename{
+			OldPath: toLowerVolumePath(filepath.Clean(oldPath)),
+			NewPath: toLowerVolumePath(filepath.Clean(newPath)),
+		})
+	}
+	return renames, nil
+}
+
+func isRenamed(renames []refactor.PathRename, path string) bool {
+	for _, r := range renames {
+		if _, ok := r.Apply(path); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_workspaceWillRenameFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+	baseDir := filepath.Join(rootDir, "base")
+	devDir := filepath.Join(rootDir, "dev")
+
+	createModuleCalling(t, "../base", devDir)
+	err := os.MkdirAll(baseDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(baseDir, "main.tf"), []byte(`variable "region" {
+}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"region\" {\n}\n",
+			"uri": "%s/base/main.tf"
+		}
+	}`, rootUri)})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/willRenameFiles",
+		ReqParams: fmt.Sprintf(`{
+			"files": [
+				{
+					"oldUri": "%s/base",
+					"newUri": "%s/modules/base"
+				}
+			]
+		}`, rootUri, rootUri)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"changes": {
+					"%s/dev/module.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 2,
+									"character": 12
+								},
+								"end": {
+									"line": 2,
+									"character": 19
+								}
+							},
+							"newText": "../modules/base"
+						}
+					]
+				}
+			}
+		}`, rootUri))
+}
+
+func TestLangServer_workspaceDidRenameFiles(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+	oldDir := filepath.Join(rootDir, "old")
+	newDir := filepath.Join(rootDir, "new")
+
+	err := os.MkdirAll(newDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(newDir, "main.tf"), []byte(`variable "region" {
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"diagnostic": {}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "variable \"region\" {\n",
+			"uri": "%s/old/main.tf"
+		}
+	}`, rootUri)})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "workspace/didRenameFiles",
+		ReqParams: fmt.Sprintf(`{
+			"files": [
+				{
+					"oldUri": %q,
+					"newUri": %q
+				}
+			]
+		}`, uri.FromPath(oldDir), uri.FromPath(newDir))})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "workspace/diagnostic",
+		ReqParams: `{
+			"previousResultIds": []
+		}`}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"items": [
+					{
+						"uri": "%s/new/main.tf",
+						"version": 0,
+						"kind": "full",
+						"resultId": %q,
+						"items": [
+							{
+								"range": {
+									"start": {
+										"line": 1,
+										"character": 0
+									},
+									"end": {
+										"line": 1,
+										"character": 0
+									}
+								},
+								"severity": 1,
+								"source": "HCL",
+								"message": "Argument or block definition required: An argument or block definition is required here."
+							}
+						]
+					}
+				]
+			}
+		}`, rootUri, unterminatedBlockResultID()))
+}
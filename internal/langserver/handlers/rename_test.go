@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/creachadair/jrpc2"
+	"github.com/creachadair/jrpc2/code"
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_prepareRename(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"textDocument": {
+	    		"rename": {
+	    			"prepareSupport": true
+	    		}
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": `+fmt.Sprintf("%q",
+			`locals {
+  name = "foo"
+}
+
+output "foo" {
+  value = local.name
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 5,
+				"character": 18
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"start": {
+					"line": 5,
+					"character": 16
+				},
+				"end": {
+					"line": 5,
+					"character": 20
+				}
+			}
+		}`)
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/prepareRename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 1,
+				"character": 10
+			}
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": null
+		}`)
+}
+
+func TestLangServer_rename_variableWithCaller(t *testing.T) {
+	rootDir := t.TempDir()
+	rootUri := uri.FromPath(rootDir)
+	baseDirUri := uri.FromPath(filepath.Join(rootDir, "base"))
+	devDir := filepath.Join(rootDir, "dev")
+
+	createModuleCalling(t, "../base", devDir)
+	err := os.WriteFile(filepath.Join(devDir, "module.tf"), []byte(`module "local" {
+  source = "../base"
+  region = "eu-west-1"
+}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				rootDir: validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, rootUri)})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": `+fmt.Sprintf("%q",
+			`variable "region" {
+}
+
+output "foo" {
+  value = var.region
+}`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, baseDirUri)})
+	ls.CallAndExpectError(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 16
+			},
+			"newName": "1nvalid"
+		}`, baseDirUri)}, code.InvalidParams.Err())
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 4,
+				"character": 16
+			},
+			"newName": "location"
+		}`, baseDirUri)}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 4,
+			"result": {
+				"changes": {
+					"%s/main.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 0,
+									"character": 10
+								},
+								"end": {
+									"line": 0,
+									"character": 16
+								}
+							},
+							"newText": "location"
+						},
+						{
+							"range": {
+								"start": {
+									"line": 4,
+									"character": 14
+								},
+								"end": {
+									"line": 4,
+									"character": 20
+								}
+							},
+							"newText": "location"
+						}
+					],
+					"%s/dev/module.tf": [
+						{
+							"range": {
+								"start": {
+									"line": 2,
+									"character": 2
+								},
+								"end": {
+									"line": 2,
+									"character": 8
+								}
+							},
+							"newText": "location"
+						}
+					]
+				}
+			}
+		}`, baseDirUri, rootUri))
+}
+
+func TestLangServer_rename_withMovedBlocks(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(nil))
+	messages := make(chan lsp.ShowMessageParams, 1)
+	ls.OnNotify(func(req *jrpc2.Request) {
+		if req.Method() != "window/showMessage" {
+			return
+		}
+		var params lsp.ShowMessageParams
+		err := req.UnmarshalParams(&params)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		messages <- params
+	})
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {
+	    	"workspace": {
+	    		"workspaceEdit": {
+	    			"documentChanges": true,
+	    			"resourceOperations": ["create"]
+	    		}
+	    	}
+	    },
+	    "initializationOptions": {
+	    	"rename": {
+	    		"movedBlocks": true
+	    	}
+	    },
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": `+fmt.Sprintf("%q",
+			`resource "random_pet" "web" {
+}
+
+output "id" {
+  value = random_pet.web.id
+}
+`)+`,
+			"uri": "%s/main.tf"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/rename",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"position": {
+				"line": 0,
+				"character": 24
+			},
+			"newName": "frontend"
+		}`, tmpDir.URI())}, fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"documentChanges": [
+					{
+						"kind": "create",
+						"uri": "%[1]s/moved.tf",
+						"options": {
+							"ignoreIfExists": true
+						}
+					},
+					{
+						"textDocument": {
+							"uri": "%[1]s/main.tf",
+							"version": null
+						},
+						"edits": [
+							{
+								"range": {
+									"start": { "line": 0, "character": 23 },
+									"end": { "line": 0, "character": 26 }
+								},
+								"newText": "frontend"
+							},
+							{
+								"range": {
+									"start": { "line": 4, "character": 21 },
+									"end": { "line": 4, "character": 24 }
+								},
+								"newText": "frontend"
+							}
+						]
+					},
+					{
+						"textDocument": {
+							"uri": "%[1]s/moved.tf",
+							"version": null
+						},
+						"edits": [
+							{
+								"range": {
+									"start": { "line": 0, "character": 0 },
+									"end": { "line": 0, "character": 0 }
+								},
+								"newText": "moved {\n  from = random_pet.web\n  to   = random_pet.frontend\n}\n"
+							}
+						]
+					}
+				]
+			}
+		}`, tmpDir.URI()))
+
+	select {
+	case msg := <-messages:
+		expectedMsg := lsp.ShowMessageParams{
+			Type: lsp.Info,
+			Message: `Renaming resource "web" also adds a moved block, so that Terraform ` +
+				`doesn't replace existing objects. This can be disabled via the rename.movedBlocks setting.`,
+		}
+		if diff := cmp.Diff(expectedMsg, msg); diff != "" {
+			t.Fatalf("unexpected message: %s", diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected user to be told about moved block")
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	ihcl "github.com/hashicorp/terraform-ls/internal/hcl"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentSelectionRange(ctx context.Context, params lsp.SelectionRangeParams) ([]lsp.SelectionRange, error) {
+	ranges := make([]lsp.SelectionRange, 0)
+
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return ranges, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return ranges, err
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return ranges, err
+	}
+
+	f, ok := parsedFileForDocument(mod, file)
+	if !ok {
+		h.logger.Printf("file not parsed yet: %s", file.FullPath())
+		return ranges, nil
+	}
+
+	for _, pos := range params.Positions {
+		fPos, err := ilsp.FilePositionFromDocumentPosition(lsp.TextDocumentPositionParams{
+			TextDocument: params.TextDocument,
+			Position:     pos,
+		}, file)
+		if err != nil {
+			return ranges, err
+		}
+
+		hclRanges := ihcl.SelectionRanges(f, fPos.Filename(), fPos.Position())
+		ranges = append(ranges, ilsp.HCLRangesToSelectionRange(hclRanges))
+	}
+
+	return ranges, nil
+}
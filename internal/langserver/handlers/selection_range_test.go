@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-ls/internal/langserver"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLangServer_selectionRange_tfvars(t *testing.T) {
+	tmpDir := TempDir(t)
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): validTfMockCalls(),
+			},
+		},
+	}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+	    "capabilities": {},
+	    "rootUri": %q,
+	    "processId": 12345
+	}`, tmpDir.URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform-vars",
+			"text": "tags = {\n  Name = \"web\"\n}\n",
+			"uri": "%s/terraform.tfvars"
+		}
+	}`, tmpDir.URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/selectionRange",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/terraform.tfvars"
+			},
+			"positions": [
+				{
+					"line": 1,
+					"character": 4
+				}
+			]
+		}`, tmpDir.URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": [
+				{
+					"range": {
+						"start": {"line": 1, "character": 2},
+						"end": {"line": 1, "character": 6}
+					},
+					"parent": {
+						"range": {
+							"start": {"line": 1, "character": 2},
+							"end": {"line": 1, "character": 14}
+						},
+						"parent": {
+							"range": {
+								"start": {"line": 0, "character": 7},
+								"end": {"line": 2, "character": 1}
+							},
+							"parent": {
+								"range": {
+									"start": {"line": 0, "character": 0},
+									"end": {"line": 2, "character": 1}
+								},
+								"parent": {
+									"range": {
+										"start": {"line": 0, "character": 0},
+										"end": {"line": 3, "character": 0}
+									}
+								}
+							}
+						}
+					}
+				}
+			]
+		}`)
+}
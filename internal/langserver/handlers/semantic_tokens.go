@@ -5,7 +5,9 @@ import (
 	"fmt"
 
 	"github.com/creachadair/jrpc2/code"
+	"github.com/hashicorp/hcl-lang/lang"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
 	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
@@ -29,49 +31,148 @@ func (lh *logHandler) TextDocumentSemanticTokensFull(ctx context.Context, params
 		return tks, code.MethodNotFound.Err()
 	}
 
-	ds, err := lsctx.DocumentStorage(ctx)
+	doc, tokens, err := lh.semanticTokensForDocument(ctx, params.TextDocument.URI)
 	if err != nil {
 		return tks, err
 	}
 
-	mf, err := lsctx.ModuleFinder(ctx)
-	if err != nil {
-		return tks, err
+	te := &ilsp.TokenEncoder{
+		Lines:      doc.Lines(),
+		Tokens:     tokens,
+		ClientCaps: cc.TextDocument.SemanticTokens,
 	}
+	tks.Data = te.Encode()
 
-	fh := ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI)
-	doc, err := ds.GetDocument(fh)
+	if caps.FullDeltaRequest() {
+		cache, err := lsctx.SemanticTokensCache(ctx)
+		if err != nil {
+			return tks, err
+		}
+		tks.ResultID = cache.Store(params.TextDocument.URI, doc.Version(), tks.Data)
+	}
+
+	return tks, nil
+}
+
+func (lh *logHandler) TextDocumentSemanticTokensFullDelta(ctx context.Context, params lsp.SemanticTokensDeltaParams) (interface{}, error) {
+	cc, err := lsctx.ClientCapabilities(ctx)
 	if err != nil {
-		return tks, err
+		return nil, err
 	}
 
-	mod, err := mf.ModuleByPath(doc.Dir())
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.FullDeltaRequest() {
+		lh.logger.Printf("semantic tokens full/delta request support not announced by client")
+		return nil, code.MethodNotFound.Err()
+	}
+
+	cache, err := lsctx.SemanticTokensCache(ctx)
 	if err != nil {
-		return tks, fmt.Errorf("finding compatible decoder failed: %w", err)
+		return nil, err
 	}
 
-	schema, err := schemaForDocument(mf, doc)
+	doc, tokens, err := lh.semanticTokensForDocument(ctx, params.TextDocument.URI)
 	if err != nil {
-		return tks, err
+		return nil, err
 	}
 
-	d, err := decoderForDocument(ctx, mod, doc.LanguageID())
+	te := &ilsp.TokenEncoder{
+		Lines:      doc.Lines(),
+		Tokens:     tokens,
+		ClientCaps: cc.TextDocument.SemanticTokens,
+	}
+	data := te.Encode()
+
+	previous, ok := cache.Get(params.TextDocument.URI, params.PreviousResultID)
+	resultID := cache.Store(params.TextDocument.URI, doc.Version(), data)
+	if !ok {
+		// previous result is no longer known,
+		// so the client has to receive all tokens
+		lh.logger.Printf("semantic tokens result %q not found, sending full tokens",
+			params.PreviousResultID)
+		return lsp.SemanticTokens{
+			ResultID: resultID,
+			Data:     data,
+		}, nil
+	}
+
+	return lsp.SemanticTokensDelta{
+		ResultID: resultID,
+		Edits:    ilsp.SemanticTokensEdits(previous, data),
+	}, nil
+}
+
+func (lh *logHandler) TextDocumentSemanticTokensRange(ctx context.Context, params lsp.SemanticTokensRangeParams) (lsp.SemanticTokens, error) {
+	tks := lsp.SemanticTokens{}
+
+	cc, err := lsctx.ClientCapabilities(ctx)
 	if err != nil {
 		return tks, err
 	}
-	d.SetSchema(schema)
 
-	tokens, err := d.SemanticTokensInFile(doc.Filename())
+	caps := ilsp.SemanticTokensClientCapabilities{
+		SemanticTokensClientCapabilities: cc.TextDocument.SemanticTokens,
+	}
+	if !caps.RangeRequest() {
+		lh.logger.Printf("semantic tokens range request support not announced by client")
+		return tks, code.MethodNotFound.Err()
+	}
+
+	doc, tokens, err := lh.semanticTokensForDocument(ctx, params.TextDocument.URI)
 	if err != nil {
 		return tks, err
 	}
 
 	te := &ilsp.TokenEncoder{
-		Lines:      doc.Lines(),
-		Tokens:     tokens,
+		Lines: doc.Lines(),
+		Tokens: ilsp.SemanticTokensInLines(tokens,
+			int(params.Range.Start.Line), int(params.Range.End.Line)),
 		ClientCaps: cc.TextDocument.SemanticTokens,
 	}
 	tks.Data = te.Encode()
 
 	return tks, nil
 }
+
+func (lh *logHandler) semanticTokensForDocument(ctx context.Context, uri lsp.DocumentURI) (filesystem.Document, []lang.SemanticToken, error) {
+	ds, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fh := ilsp.FileHandlerFromDocumentURI(uri)
+	doc, err := ds.GetDocument(fh)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mod, err := mf.ModuleByPath(doc.Dir())
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding compatible decoder failed: %w", err)
+	}
+
+	schema, err := schemaForDocument(mf, doc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d, err := decoderForDocument(ctx, mod, doc.LanguageID())
+	if err != nil {
+		return nil, nil, err
+	}
+	d.SetSchema(schema)
+
+	tokens, err := d.SemanticTokensInFile(doc.Filename())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, tokens, nil
+}
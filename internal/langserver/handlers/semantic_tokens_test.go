@@ -220,6 +220,7 @@ func TestSemanticTokensFull_clientSupportsDelta(t *testing.T) {
 			"jsonrpc": "2.0",
 			"id": 3,
 			"result": {
+				"resultId": "0.1",
 				"data": [
 					0,0,8,0,0,
 					0,9,6,1,2
@@ -228,6 +229,292 @@ func TestSemanticTokensFull_clientSupportsDelta(t *testing.T) {
 		}`)
 }
 
+func TestSemanticTokensFullDelta(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Dir())
+
+	var testSchema tfjson.ProviderSchemas
+	err := json.Unmarshal([]byte(testModuleSchemaOutput), &testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+					{
+						Method:        "ProviderSchemas",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							&testSchema,
+							nil,
+						},
+					},
+				},
+			},
+		}}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"semanticTokens": {
+					"tokenTypes": [
+						"type",
+						"property",
+						"string"
+					],
+					"tokenModifiers": [
+						"deprecated",
+						"modification"
+					],
+					"requests": {
+						"full": {
+							"delta": true
+						}
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, TempDir(t).URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "provider \"test\" {\n\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, TempDir(t).URI())})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			}
+		}`, TempDir(t).URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"resultId": "0.1",
+				"data": [
+					0,0,8,0,0,
+					0,9,6,1,2
+				]
+			}
+		}`)
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didChange",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 1,
+			"uri": "%s/main.tf"
+		},
+		"contentChanges": [
+			{
+				"text": "provider \"test\" {\n\n}\n\nprovider \"test\" {\n\n}\n"
+			}
+		]
+	}`, TempDir(t).URI())})
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full/delta",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "0.1"
+		}`, TempDir(t).URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 5,
+			"result": {
+				"resultId": "1.2",
+				"edits": [
+					{
+						"start": 10,
+						"deleteCount": 0,
+						"data": [
+							4,0,8,0,0,
+							0,9,6,1,2
+						]
+					}
+				]
+			}
+		}`)
+
+	// unknown previous result is answered with all tokens
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/full/delta",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"previousResultId": "0.1"
+		}`, TempDir(t).URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 6,
+			"result": {
+				"resultId": "1.2",
+				"data": [
+					0,0,8,0,0,
+					0,9,6,1,2,
+					4,0,8,0,0,
+					0,9,6,1,2
+				]
+			}
+		}`)
+}
+
+func TestSemanticTokensRange(t *testing.T) {
+	tmpDir := TempDir(t)
+	InitPluginCache(t, tmpDir.Dir())
+
+	var testSchema tfjson.ProviderSchemas
+	err := json.Unmarshal([]byte(testModuleSchemaOutput), &testSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ls := langserver.NewLangServerMock(t, NewMockSession(&MockSessionInput{
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{
+				tmpDir.Dir(): {
+					{
+						Method:        "Version",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							version.Must(version.NewVersion("0.12.0")),
+							nil,
+							nil,
+						},
+					},
+					{
+						Method:        "GetExecPath",
+						Repeatability: 1,
+						ReturnArguments: []interface{}{
+							"",
+						},
+					},
+					{
+						Method:        "ProviderSchemas",
+						Repeatability: 1,
+						Arguments: []interface{}{
+							mock.AnythingOfType(""),
+						},
+						ReturnArguments: []interface{}{
+							&testSchema,
+							nil,
+						},
+					},
+				},
+			},
+		}}))
+	stop := ls.Start(t)
+	defer stop()
+
+	ls.Call(t, &langserver.CallRequest{
+		Method: "initialize",
+		ReqParams: fmt.Sprintf(`{
+		"capabilities": {
+			"textDocument": {
+				"semanticTokens": {
+					"tokenTypes": [
+						"type",
+						"property",
+						"string"
+					],
+					"tokenModifiers": [
+						"deprecated",
+						"modification"
+					],
+					"requests": {
+						"range": true
+					}
+				}
+			}
+		},
+		"rootUri": %q,
+		"processId": 12345
+	}`, TempDir(t).URI())})
+	ls.Notify(t, &langserver.CallRequest{
+		Method:    "initialized",
+		ReqParams: "{}",
+	})
+	ls.Call(t, &langserver.CallRequest{
+		Method: "textDocument/didOpen",
+		ReqParams: fmt.Sprintf(`{
+		"textDocument": {
+			"version": 0,
+			"languageId": "terraform",
+			"text": "provider \"test\" {\n\n}\n\nprovider \"test\" {\n\n}\n",
+			"uri": "%s/main.tf"
+		}
+	}`, TempDir(t).URI())})
+
+	ls.CallAndExpectResponse(t, &langserver.CallRequest{
+		Method: "textDocument/semanticTokens/range",
+		ReqParams: fmt.Sprintf(`{
+			"textDocument": {
+				"uri": "%s/main.tf"
+			},
+			"range": {
+				"start": {
+					"line": 4,
+					"character": 0
+				},
+				"end": {
+					"line": 6,
+					"character": 1
+				}
+			}
+		}`, TempDir(t).URI())}, `{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"result": {
+				"data": [
+					4,0,8,0,0,
+					0,9,6,1,2
+				]
+			}
+		}`)
+}
+
 func TestVarsSemanticTokensFull(t *testing.T) {
 	tmpDir := TempDir(t)
 	InitPluginCache(t, tmpDir.Dir())
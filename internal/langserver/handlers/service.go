@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/creachadair/jrpc2"
@@ -13,6 +14,7 @@ import (
 	rpch "github.com/creachadair/jrpc2/handler"
 	"github.com/hashicorp/hcl-lang/decoder"
 	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
 	lsctx "github.com/hashicorp/terraform-ls/internal/context"
 	idecoder "github.com/hashicorp/terraform-ls/internal/decoder"
 	"github.com/hashicorp/terraform-ls/internal/filesystem"
@@ -23,6 +25,7 @@ import (
 	"github.com/hashicorp/terraform-ls/internal/schemas"
 	"github.com/hashicorp/terraform-ls/internal/settings"
 	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
 	"github.com/hashicorp/terraform-ls/internal/terraform/discovery"
 	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
 	"github.com/hashicorp/terraform-ls/internal/terraform/module"
@@ -44,10 +47,13 @@ type service struct {
 	modMgr           module.ModuleManager
 	newModuleManager module.ModuleManagerFactory
 	newWatcher       module.WatcherFactory
+	newClientWatcher module.WatcherFactory
 	newWalker        module.WalkerFactory
 	tfDiscoFunc      discovery.DiscoveryFunc
 	tfExecFactory    exec.ExecutorFactory
-	tfExecOpts       *exec.ExecutorOpts
+	tfExecOpts       *exec.SharedExecutorOpts
+	options          *settings.Options
+	optionsMu        *sync.Mutex
 
 	additionalHandlers map[string]rpch.Func
 }
@@ -67,9 +73,11 @@ func NewSession(srvCtx context.Context) session.Session {
 		stopSession:      stopSession,
 		newModuleManager: module.NewModuleManager,
 		newWatcher:       module.NewWatcher,
+		newClientWatcher: module.NewClientWatcher,
 		newWalker:        module.NewWalker,
 		tfDiscoFunc:      d.LookPath,
 		tfExecFactory:    exec.NewExecutor,
+		optionsMu:        &sync.Mutex{},
 	}
 }
 
@@ -95,11 +103,16 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 	cc := &lsp.ClientCapabilities{}
 
 	notifier := diagnostics.NewNotifier(svc.sessCtx, svc.logger)
+	semTokensCache := ilsp.NewSemanticTokensCache()
+	completionCache := ilsp.NewCompletionCache()
 
 	rootDir := ""
 	commandPrefix := ""
 	clientName := ""
+	formatter := ""
 	var expFeatures settings.ExperimentalFeatures
+	var renameOpts settings.RenameOptions
+	var organizeOpts settings.OrganizeAttributesOptions
 
 	m := map[string]rpch.Func{
 		"initialize": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
@@ -113,6 +126,10 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithCommandPrefix(ctx, &commandPrefix)
 			ctx = lsctx.WithClientName(ctx, &clientName)
 			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+			ctx = lsctx.WithFormatter(ctx, &formatter)
+			ctx = lsctx.WithRenameOptions(ctx, &renameOpts)
+			ctx = lsctx.WithOrganizeAttributesOptions(ctx, &organizeOpts)
+			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
 
 			version, ok := lsctx.LanguageServerVersion(svc.srvCtx)
 			if ok {
@@ -127,7 +144,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 				return nil, err
 			}
 
-			return handle(ctx, req, Initialized)
+			return handle(ctx, req, svc.Initialized)
 		},
 		"textDocument/didChange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
@@ -136,6 +153,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
 			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
 			return handle(ctx, req, TextDocumentDidChange)
 		},
@@ -147,6 +165,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
 			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
 			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 			ctx = lsctx.WithWatcher(ctx, svc.watcher)
 			return handle(ctx, req, lh.TextDocumentDidOpen)
 		},
@@ -157,6 +176,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
 			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithSemanticTokensCache(ctx, semTokensCache)
 			return handle(ctx, req, TextDocumentDidClose)
 		},
 		"textDocument/documentSymbol": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
@@ -218,9 +238,24 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithClientCapabilities(ctx, cc)
 			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
 			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+			ctx = lsctx.WithCompletionCache(ctx, completionCache)
 
 			return handle(ctx, req, lh.TextDocumentComplete)
 		},
+		"completionItem/resolve": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+			ctx = lsctx.WithCompletionCache(ctx, completionCache)
+
+			return handle(ctx, req, lh.CompletionItemResolve)
+		},
 		"textDocument/hover": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -242,7 +277,11 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			ctx = lsctx.WithClientCapabilities(ctx, cc)
 			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
-			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+			ctx = lsctx.WithFormatter(ctx, &formatter)
+			ctx = lsctx.WithRenameOptions(ctx, &renameOpts)
+			ctx = lsctx.WithOrganizeAttributesOptions(ctx, &organizeOpts)
+			ctx = exec.WithSharedExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
 
 			return handle(ctx, req, lh.TextDocumentCodeAction)
@@ -266,11 +305,37 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 
 			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
-			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = lsctx.WithFormatter(ctx, &formatter)
+			ctx = lsctx.WithOrganizeAttributesOptions(ctx, &organizeOpts)
+			ctx = exec.WithSharedExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
 
 			return handle(ctx, req, lh.TextDocumentFormatting)
 		},
+		"textDocument/rangeFormatting": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithFormatter(ctx, &formatter)
+			ctx = lsctx.WithOrganizeAttributesOptions(ctx, &organizeOpts)
+			ctx = exec.WithSharedExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
+
+			return handle(ctx, req, lh.TextDocumentRangeFormatting)
+		},
+		"textDocument/onTypeFormatting": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+
+			return handle(ctx, req, lh.TextDocumentOnTypeFormatting)
+		},
 		"textDocument/semanticTokens/full": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -281,8 +346,35 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithClientCapabilities(ctx, cc)
 			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
 
+			ctx = lsctx.WithSemanticTokensCache(ctx, semTokensCache)
+
 			return handle(ctx, req, lh.TextDocumentSemanticTokensFull)
 		},
+		"textDocument/semanticTokens/full/delta": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+			ctx = lsctx.WithSemanticTokensCache(ctx, semTokensCache)
+
+			return handle(ctx, req, lh.TextDocumentSemanticTokensFullDelta)
+		},
+		"textDocument/semanticTokens/range": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentSemanticTokensRange)
+		},
 		"textDocument/didSave": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -292,7 +384,7 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
 			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
 			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
-			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = exec.WithSharedExecutorOpts(ctx, svc.tfExecOpts)
 
 			return handle(ctx, req, lh.TextDocumentDidSave)
 		},
@@ -318,6 +410,185 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 
 			return handle(ctx, req, lh.References)
 		},
+		"textDocument/signatureHelp": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentSignatureHelp)
+		},
+		"textDocument/foldingRange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentFoldingRange)
+		},
+		"textDocument/selectionRange": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentSelectionRange)
+		},
+		"textDocument/inlayHint": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentInlayHint)
+		},
+		"textDocument/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentDiagnostic)
+		},
+		"workspace/diagnostic": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.WorkspaceDiagnostic)
+		},
+		"workspace/didChangeConfiguration": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithRootDirectory(ctx, &rootDir)
+			ctx = lsctx.WithExperimentalFeatures(ctx, &expFeatures)
+			ctx = lsctx.WithFormatter(ctx, &formatter)
+			ctx = lsctx.WithRenameOptions(ctx, &renameOpts)
+			ctx = lsctx.WithOrganizeAttributesOptions(ctx, &organizeOpts)
+
+			return handle(ctx, req, svc.DidChangeConfiguration)
+		},
+		"workspace/willRenameFiles": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.WorkspaceWillRenameFiles)
+		},
+		"workspace/didRenameFiles": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+			ctx = lsctx.WithWatcher(ctx, svc.watcher)
+
+			return handle(ctx, req, lh.WorkspaceDidRenameFiles)
+		},
+		"workspace/didChangeWatchedFiles": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithWatcher(ctx, svc.watcher)
+
+			return handle(ctx, req, lh.DidChangeWatchedFiles)
+		},
+		"textDocument/documentHighlight": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentHighlight)
+		},
+		"textDocument/prepareRename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentPrepareRename)
+		},
+		"textDocument/rename": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+			ctx = lsctx.WithRenameOptions(ctx, &renameOpts)
+
+			return handle(ctx, req, lh.TextDocumentRename)
+		},
+		"textDocument/prepareCallHierarchy": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.TextDocumentPrepareCallHierarchy)
+		},
+		"callHierarchy/incomingCalls": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.CallHierarchyIncomingCalls)
+		},
+		"callHierarchy/outgoingCalls": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
+			err := session.CheckInitializationIsConfirmed()
+			if err != nil {
+				return nil, err
+			}
+
+			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
+
+			return handle(ctx, req, lh.CallHierarchyOutgoingCalls)
+		},
 		"workspace/executeCommand": func(ctx context.Context, req *jrpc2.Request) (interface{}, error) {
 			err := session.CheckInitializationIsConfirmed()
 			if err != nil {
@@ -325,13 +596,15 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 			}
 
 			ctx = lsctx.WithCommandPrefix(ctx, &commandPrefix)
+			ctx = lsctx.WithDocumentStorage(ctx, svc.fs)
+			ctx = lsctx.WithClientCapabilities(ctx, cc)
 			ctx = lsctx.WithModuleManager(ctx, svc.modMgr)
 			ctx = lsctx.WithModuleFinder(ctx, svc.modMgr)
 			ctx = lsctx.WithModuleWalker(ctx, svc.walker)
 			ctx = lsctx.WithWatcher(ctx, svc.watcher)
 			ctx = lsctx.WithRootDirectory(ctx, &rootDir)
 			ctx = lsctx.WithDiagnosticsNotifier(ctx, notifier)
-			ctx = exec.WithExecutorOpts(ctx, svc.tfExecOpts)
+			ctx = exec.WithSharedExecutorOpts(ctx, svc.tfExecOpts)
 			ctx = exec.WithExecutorFactory(ctx, svc.tfExecFactory)
 
 			return handle(ctx, req, lh.WorkspaceExecuteCommand)
@@ -387,13 +660,63 @@ func (svc *service) Assigner() (jrpc2.Assigner, error) {
 	return convertMap(m), nil
 }
 
-func (svc *service) configureSessionDependencies(cfgOpts *settings.Options) error {
+func (svc *service) configureSessionDependencies(cfgOpts *settings.Options, clientCaps lsp.ClientCapabilities) error {
+	execOpts, err := svc.terraformExecOptions(cfgOpts)
+	if err != nil {
+		return err
+	}
+
+	svc.tfExecOpts = exec.NewSharedExecutorOpts(execOpts)
+
+	svc.sessCtx = exec.WithSharedExecutorOpts(svc.sessCtx, svc.tfExecOpts)
+	svc.sessCtx = exec.WithExecutorFactory(svc.sessCtx, svc.tfExecFactory)
+
+	store, err := state.NewStateStore()
+	if err != nil {
+		return err
+	}
+	store.SetLogger(svc.logger)
+
+	err = schemas.PreloadSchemasToStore(store.ProviderSchemas)
+	if err != nil {
+		return err
+	}
+
+	svc.modMgr = svc.newModuleManager(svc.sessCtx, svc.fs, store.Modules, store.ProviderSchemas)
+	svc.modMgr.SetLogger(svc.logger)
+
+	svc.walker = svc.newWalker(svc.fs, svc.modMgr)
+	svc.walker.SetLogger(svc.logger)
+
+	newWatcher := svc.newWatcher
+	if clientCaps.Workspace.DidChangeWatchedFiles.DynamicRegistration {
+		// Watching by the client is preferred as it scales
+		// better than fsnotify, especially in large trees
+		newWatcher = svc.newClientWatcher
+	}
+	ww, err := newWatcher(svc.fs, svc.modMgr)
+	if err != nil {
+		return err
+	}
+	svc.watcher = ww
+	svc.watcher.SetLogger(svc.logger)
+	err = svc.watcher.Start()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// terraformExecOptions builds options for Terraform executions
+// from CLI flags (set in the server context) and LSP options
+func (svc *service) terraformExecOptions(cfgOpts *settings.Options) (*exec.ExecutorOpts, error) {
 	// The following is set via CLI flags, hence available in the server context
 	execOpts := &exec.ExecutorOpts{}
 	cliExecPath, ok := lsctx.TerraformExecPath(svc.srvCtx)
 	if ok {
 		if len(cfgOpts.TerraformExecPath) > 0 {
-			return fmt.Errorf("Terraform exec path can either be set via (-tf-exec) CLI flag " +
+			return nil, fmt.Errorf("Terraform exec path can either be set via (-tf-exec) CLI flag " +
 				"or (terraformExecPath) LSP config option, not both")
 		}
 		execOpts.ExecPath = cliExecPath
@@ -405,12 +728,11 @@ func (svc *service) configureSessionDependencies(cfgOpts *settings.Options) erro
 			execOpts.ExecPath = path
 		}
 	}
-	svc.srvCtx = lsctx.WithTerraformExecPath(svc.srvCtx, execOpts.ExecPath)
 
 	path, ok := lsctx.TerraformExecLogPath(svc.srvCtx)
 	if ok {
 		if len(cfgOpts.TerraformLogFilePath) > 0 {
-			return fmt.Errorf("Terraform log file path can either be set via (-tf-log-file) CLI flag " +
+			return nil, fmt.Errorf("Terraform log file path can either be set via (-tf-log-file) CLI flag " +
 				"or (terraformLogFilePath) LSP config option, not both")
 		}
 		execOpts.ExecLogPath = path
@@ -421,52 +743,19 @@ func (svc *service) configureSessionDependencies(cfgOpts *settings.Options) erro
 	timeout, ok := lsctx.TerraformExecTimeout(svc.srvCtx)
 	if ok {
 		if len(cfgOpts.TerraformExecTimeout) > 0 {
-			return fmt.Errorf("Terraform exec timeout can either be set via (-tf-exec-timeout) CLI flag " +
+			return nil, fmt.Errorf("Terraform exec timeout can either be set via (-tf-exec-timeout) CLI flag " +
 				"or (terraformExecTimeout) LSP config option, not both")
 		}
 		execOpts.Timeout = timeout
 	} else if len(cfgOpts.TerraformExecTimeout) > 0 {
 		d, err := time.ParseDuration(cfgOpts.TerraformExecTimeout)
 		if err != nil {
-			return fmt.Errorf("Failed to parse terraformExecTimeout LSP config option: %s", err)
+			return nil, fmt.Errorf("Failed to parse terraformExecTimeout LSP config option: %s", err)
 		}
 		execOpts.Timeout = d
 	}
 
-	svc.tfExecOpts = execOpts
-
-	svc.sessCtx = exec.WithExecutorOpts(svc.sessCtx, execOpts)
-	svc.sessCtx = exec.WithExecutorFactory(svc.sessCtx, svc.tfExecFactory)
-
-	store, err := state.NewStateStore()
-	if err != nil {
-		return err
-	}
-	store.SetLogger(svc.logger)
-
-	err = schemas.PreloadSchemasToStore(store.ProviderSchemas)
-	if err != nil {
-		return err
-	}
-
-	svc.modMgr = svc.newModuleManager(svc.sessCtx, svc.fs, store.Modules, store.ProviderSchemas)
-	svc.modMgr.SetLogger(svc.logger)
-
-	svc.walker = svc.newWalker(svc.fs, svc.modMgr)
-	svc.walker.SetLogger(svc.logger)
-
-	ww, err := svc.newWatcher(svc.fs, svc.modMgr)
-	if err != nil {
-		return err
-	}
-	svc.watcher = ww
-	svc.watcher.SetLogger(svc.logger)
-	err = svc.watcher.Start()
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return execOpts, nil
 }
 
 func (svc *service) Finish(_ jrpc2.Assigner, status jrpc2.ServerStatus) {
@@ -539,3 +828,13 @@ func decoderForDocument(ctx context.Context, mod module.Module, languageID strin
 	}
 	return idecoder.DecoderForModule(ctx, mod)
 }
+
+func parsedFileForDocument(mod module.Module, doc filesystem.Document) (*hcl.File, bool) {
+	var f *hcl.File
+	if doc.LanguageID() == ilsp.Tfvars.String() {
+		f = mod.ParsedVarsFiles[ast.VarsFilename(doc.Filename())]
+	} else {
+		f = mod.ParsedModuleFiles[ast.ModFilename(doc.Filename())]
+	}
+	return f, f != nil
+}
@@ -68,9 +68,11 @@ func (ms *mockSession) new(srvCtx context.Context) session.Session {
 		fs:                 fs,
 		newModuleManager:   module.NewModuleManagerMock(input),
 		newWatcher:         module.MockWatcher(),
+		newClientWatcher:   module.NewClientWatcher,
 		newWalker:          module.SyncWalker,
 		tfDiscoFunc:        d.LookPath,
 		tfExecFactory:      exec.NewMockExecutor(tfCalls),
+		optionsMu:          &sync.Mutex{},
 		additionalHandlers: handlers,
 	}
 
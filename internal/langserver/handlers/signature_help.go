@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+
+	lsctx "github.com/hashicorp/terraform-ls/internal/context"
+	"github.com/hashicorp/terraform-ls/internal/functions"
+	ilsp "github.com/hashicorp/terraform-ls/internal/lsp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func (h *logHandler) TextDocumentSignatureHelp(ctx context.Context, params lsp.SignatureHelpParams) (*lsp.SignatureHelp, error) {
+	fs, err := lsctx.DocumentStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := lsctx.ModuleFinder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fs.GetDocument(ilsp.FileHandlerFromDocumentURI(params.TextDocument.URI))
+	if err != nil {
+		return nil, err
+	}
+
+	if file.LanguageID() != ilsp.Terraform.String() {
+		// functions cannot be called in variable files
+		return nil, nil
+	}
+
+	mod, err := mf.ModuleByPath(file.Dir())
+	if err != nil {
+		return nil, err
+	}
+
+	fPos, err := ilsp.FilePositionFromDocumentPosition(params.TextDocumentPositionParams, file)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := file.Text()
+	if err != nil {
+		return nil, err
+	}
+
+	call, ok := functions.CallAtPos(text, fPos.Filename(), fPos.Position())
+	if !ok {
+		return nil, nil
+	}
+
+	f, ok := functions.FunctionsForVersion(mod.TerraformVersion)[call.Name]
+	if !ok {
+		h.logger.Printf("unknown function %q for Terraform %s", call.Name, mod.TerraformVersion)
+		return nil, nil
+	}
+
+	return ilsp.SignatureHelp(f, call.ArgIndex), nil
+}
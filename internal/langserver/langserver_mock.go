@@ -34,6 +34,8 @@ type langServerMock struct {
 	client       *jrpc2.Client
 	clientStdin  io.Reader
 	clientStdout io.WriteCloser
+	onCallback   func(context.Context, *jrpc2.Request) (interface{}, error)
+	onNotify     func(*jrpc2.Request)
 }
 
 func NewLangServerMock(t *testing.T, sf session.SessionFactory) *langServerMock {
@@ -65,6 +67,20 @@ func NewLangServerMock(t *testing.T, sf session.SessionFactory) *langServerMock
 	return lsm
 }
 
+// OnCallback sets a function to handle requests sent
+// from the server to the client, such as workspace/configuration.
+// It has to be called before Start.
+func (lsm *langServerMock) OnCallback(fn func(context.Context, *jrpc2.Request) (interface{}, error)) {
+	lsm.onCallback = fn
+}
+
+// OnNotify sets a function to handle notifications sent
+// from the server to the client, such as window/showMessage.
+// It has to be called before Start.
+func (lsm *langServerMock) OnNotify(fn func(*jrpc2.Request)) {
+	lsm.onNotify = fn
+}
+
 func (lsm *langServerMock) Stop() {
 	lsm.logger.Println("Stopping mock server ...")
 	lsm.rpcSrv.Stop()
@@ -94,7 +110,10 @@ func (lsm *langServerMock) Start(t *testing.T) context.CancelFunc {
 	}()
 
 	clientCh := channel.LSP(lsm.clientStdin, lsm.clientStdout)
-	opts := &jrpc2.ClientOptions{}
+	opts := &jrpc2.ClientOptions{
+		OnCallback: lsm.onCallback,
+		OnNotify:   lsm.onNotify,
+	}
 	if testing.Verbose() {
 		opts.Logger = testLogger(os.Stdout, "[CLIENT] ")
 	}
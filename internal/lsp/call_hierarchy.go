@@ -0,0 +1,31 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// CallHierarchyItemData identifies the module block
+// represented by a call hierarchy item
+type CallHierarchyItemData struct {
+	ModuleURI string `json:"moduleUri"`
+	Name      string `json:"name"`
+}
+
+func DecodeCallHierarchyItemData(data interface{}) (CallHierarchyItemData, error) {
+	var itemData CallHierarchyItemData
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return itemData, err
+	}
+	err = json.Unmarshal(b, &itemData)
+	if err != nil {
+		return itemData, err
+	}
+	if itemData.ModuleURI == "" || itemData.Name == "" {
+		return itemData, fmt.Errorf("missing module URI or name in call hierarchy item data")
+	}
+
+	return itemData, nil
+}
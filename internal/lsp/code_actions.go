@@ -9,6 +9,12 @@ import (
 const (
 	SourceFormatAll            = "source.formatAll"
 	SourceFormatAllTerraformLs = "source.formatAll.terraform-ls"
+	SourceFixAllUpgrade        = "source.fixAll.terraform-ls.upgrade"
+	SourceGenerateVariables    = "source.generateVariables"
+	SourceGenerateTfvars       = "source.generateTfvars"
+	SourceGenerateOutputs      = "source.generateOutputs"
+	SourceOrganizeAttributes   = "source.organizeAttributes"
+	RefactorExtractModule      = "refactor.extract.module"
 )
 
 type CodeActions map[lsp.CodeActionKind]bool
@@ -19,6 +25,15 @@ var (
 		lsp.SourceFixAll:           true,
 		SourceFormatAll:            true,
 		SourceFormatAllTerraformLs: true,
+		SourceFixAllUpgrade:        true,
+		lsp.QuickFix:               true,
+		lsp.RefactorExtract:        true,
+		RefactorExtractModule:      true,
+		lsp.RefactorRewrite:        true,
+		SourceGenerateVariables:    true,
+		SourceGenerateTfvars:       true,
+		SourceGenerateOutputs:      true,
+		SourceOrganizeAttributes:   true,
 	}
 )
 
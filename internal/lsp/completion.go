@@ -1,6 +1,10 @@
 package lsp
 
 import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
 	"github.com/hashicorp/hcl-lang/lang"
 	"github.com/hashicorp/terraform-ls/internal/mdplain"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
@@ -94,3 +98,121 @@ func tagSliceContains(supported []lsp.CompletionItemTag, tag lsp.CompletionItemT
 	}
 	return false
 }
+
+// CompletionItemData represents data attached to completion items
+// which is needed to resolve them via completionItem/resolve
+type CompletionItemData struct {
+	URI      lsp.DocumentURI `json:"uri"`
+	Position lsp.Position    `json:"position"`
+	Version  int             `json:"version"`
+	// Index is the index of the item in the completion list
+	Index int `json:"index"`
+}
+
+func DecodeCompletionItemData(data interface{}) (CompletionItemData, error) {
+	var itemData CompletionItemData
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return itemData, err
+	}
+	err = json.Unmarshal(b, &itemData)
+	if err != nil {
+		return itemData, err
+	}
+	if itemData.URI == "" {
+		return itemData, fmt.Errorf("missing URI in completion item data")
+	}
+
+	return itemData, nil
+}
+
+// lazyProperties are completion item properties
+// which the server is able to resolve lazily
+var lazyProperties = []string{
+	"detail",
+	"documentation",
+	"additionalTextEdits",
+}
+
+// LazyCompletionList strips all properties of completion items
+// which the client is able to resolve lazily and attaches data
+// needed to resolve them later
+func LazyCompletionList(list lsp.CompletionList, data CompletionItemData, caps lsp.CompletionClientCapabilities) lsp.CompletionList {
+	lazyProps := make(map[string]bool)
+	for _, prop := range caps.CompletionItem.ResolveSupport.Properties {
+		for _, lazyProp := range lazyProperties {
+			if prop == lazyProp {
+				lazyProps[prop] = true
+			}
+		}
+	}
+	if len(lazyProps) == 0 {
+		return list
+	}
+
+	for i, item := range list.Items {
+		if lazyProps["detail"] {
+			item.Detail = ""
+		}
+		if lazyProps["documentation"] {
+			item.Documentation = ""
+		}
+		if lazyProps["additionalTextEdits"] {
+			item.AdditionalTextEdits = nil
+		}
+		itemData := data
+		itemData.Index = i
+		item.Data = itemData
+		list.Items[i] = item
+	}
+
+	return list
+}
+
+// CompletionCache keeps the last fully resolved completion list,
+// so that its items can be resolved without completing again
+type CompletionCache struct {
+	mu    sync.Mutex
+	key   CompletionItemData
+	items []lsp.CompletionItem
+}
+
+func NewCompletionCache() *CompletionCache {
+	return &CompletionCache{}
+}
+
+// Store caches items of the list completed in the given document
+// version and position, replacing any previously cached list
+func (c *CompletionCache) Store(data CompletionItemData, list lsp.CompletionList) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data.Index = 0
+	c.key = data
+	c.items = make([]lsp.CompletionItem, len(list.Items))
+	copy(c.items, list.Items)
+}
+
+// Item returns the fully resolved item the data refers to,
+// if the list it comes from is still cached
+func (c *CompletionCache) Item(data CompletionItemData) (lsp.CompletionItem, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index := data.Index
+	data.Index = 0
+	if c.key != data || index < 0 || index >= len(c.items) {
+		return lsp.CompletionItem{}, false
+	}
+	return c.items[index], true
+}
+
+// ResolveCompletionItem fills in properties of the item
+// from the fully resolved item
+func ResolveCompletionItem(item, resolved lsp.CompletionItem) lsp.CompletionItem {
+	item.Detail = resolved.Detail
+	item.Documentation = resolved.Documentation
+	item.AdditionalTextEdits = resolved.AdditionalTextEdits
+	return item
+}
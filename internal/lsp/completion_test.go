@@ -0,0 +1,60 @@
+package lsp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func TestCompletionCache(t *testing.T) {
+	cache := NewCompletionCache()
+	data := CompletionItemData{
+		URI:      lsp.DocumentURI("file:///test/main.tf"),
+		Position: lsp.Position{Line: 1, Character: 12},
+		Version:  1,
+	}
+	list := lsp.CompletionList{
+		Items: []lsp.CompletionItem{
+			{Label: "upper", Detail: "upper(str string) string"},
+			{Label: "uuid", Detail: "uuid() string"},
+		},
+	}
+	cache.Store(data, list)
+
+	// stripping lazy properties must not affect cached items
+	var caps lsp.CompletionClientCapabilities
+	err := json.Unmarshal([]byte(`{
+		"completionItem": {
+			"resolveSupport": {"properties": ["detail"]}
+		}
+	}`), &caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazyList := LazyCompletionList(list, data, caps)
+
+	itemData, err := DecodeCompletionItemData(lazyList.Items[1].Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, ok := cache.Item(itemData)
+	if !ok {
+		t.Fatalf("expected item %#v to be cached", itemData)
+	}
+	expectedItem := lsp.CompletionItem{Label: "uuid", Detail: "uuid() string"}
+	if diff := cmp.Diff(expectedItem, item); diff != "" {
+		t.Fatalf("unexpected item: %s", diff)
+	}
+
+	itemData.Version = 2
+	if _, ok := cache.Item(itemData); ok {
+		t.Fatalf("expected item of outdated version not to be found")
+	}
+	itemData.Version = 1
+	itemData.Index = 2
+	if _, ok := cache.Item(itemData); ok {
+		t.Fatalf("expected item out of range not to be found")
+	}
+}
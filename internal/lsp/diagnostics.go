@@ -1,6 +1,10 @@
 package lsp
 
 import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
 	"github.com/hashicorp/hcl/v2"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
@@ -40,3 +44,12 @@ func HCLDiagsToLSP(hclDiags hcl.Diagnostics, source string) []lsp.Diagnostic {
 	}
 	return diags
 }
+
+// DiagnosticsResultID returns an identifier of the given diagnostics
+// which only changes when the diagnostics change, such that
+// the client can be sent an unchanged report in pull mode
+func DiagnosticsResultID(diags []lsp.Diagnostic) string {
+	h := fnv.New64a()
+	json.NewEncoder(h).Encode(diags)
+	return fmt.Sprintf("%x", h.Sum64())
+}
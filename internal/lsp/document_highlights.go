@@ -0,0 +1,37 @@
+package lsp
+
+import (
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func RefTargetToWriteHighlight(target lang.ReferenceTarget) (lsp.DocumentHighlight, bool) {
+	var rng *hcl.Range
+	if target.DefRangePtr != nil {
+		rng = target.DefRangePtr
+	} else if target.RangePtr != nil {
+		rng = target.RangePtr
+	}
+	if rng == nil {
+		return lsp.DocumentHighlight{}, false
+	}
+
+	return lsp.DocumentHighlight{
+		Range: HCLRangeToLSP(*rng),
+		Kind:  lsp.Write,
+	}, true
+}
+
+func RefOriginsToReadHighlights(origins lang.ReferenceOrigins) []lsp.DocumentHighlight {
+	highlights := make([]lsp.DocumentHighlight, len(origins))
+
+	for i, origin := range origins {
+		highlights[i] = lsp.DocumentHighlight{
+			Range: HCLRangeToLSP(origin.Range),
+			Kind:  lsp.Read,
+		}
+	}
+
+	return highlights
+}
@@ -0,0 +1,19 @@
+package lsp
+
+import (
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+// FolderRenameOptions represents file operations filter matching
+// renames of folders, which may affect sources of local modules
+var FolderRenameOptions = lsp.FileOperationRegistrationOptions{
+	Filters: []lsp.FileOperationFilter{
+		{
+			Scheme: "file",
+			Pattern: lsp.FileOperationPattern{
+				Glob:    "**",
+				Matches: lsp.FolderOp,
+			},
+		},
+	},
+}
@@ -0,0 +1,51 @@
+package lsp
+
+import (
+	ihcl "github.com/hashicorp/terraform-ls/internal/hcl"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func FoldingRanges(ranges []ihcl.FoldingRange, lineFoldingOnly bool) []lsp.FoldingRange {
+	frs := make([]lsp.FoldingRange, 0)
+
+	for _, rng := range ranges {
+		var kind string
+		if rng.Kind == ihcl.FoldingRangeKindComment {
+			kind = "comment"
+		}
+
+		if !lineFoldingOnly {
+			lspRng := HCLRangeToLSP(rng.Range)
+			frs = append(frs, lsp.FoldingRange{
+				StartLine:      lspRng.Start.Line,
+				StartCharacter: lspRng.Start.Character,
+				EndLine:        lspRng.End.Line,
+				EndCharacter:   lspRng.End.Character,
+				Kind:           kind,
+			})
+			continue
+		}
+
+		startLine := rng.Range.Start.Line - 1
+		endLine := rng.Range.End.Line - 1
+		if rng.Kind == ihcl.FoldingRangeKindRegion {
+			// keep the line with closing token visible
+			endLine--
+		}
+		if endLine <= startLine {
+			continue
+		}
+		// client can only fold one range per line
+		if len(frs) > 0 && frs[len(frs)-1].StartLine == uint32(startLine) {
+			continue
+		}
+
+		frs = append(frs, lsp.FoldingRange{
+			StartLine: uint32(startLine),
+			EndLine:   uint32(endLine),
+			Kind:      kind,
+		})
+	}
+
+	return frs
+}
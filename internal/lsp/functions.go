@@ -0,0 +1,86 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/functions"
+	"github.com/hashicorp/terraform-ls/internal/mdplain"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func SignatureHelp(f functions.Function, argIdx int) *lsp.SignatureHelp {
+	labels := f.ParamLabels()
+	params := make([]lsp.ParameterInformation, len(labels))
+	for i, label := range labels {
+		params[i] = lsp.ParameterInformation{
+			Label: label,
+		}
+	}
+	for i, p := range f.Params {
+		params[i].Documentation = mdplain.Clean(p.Description)
+	}
+	if f.VariadicParam != nil {
+		params[len(params)-1].Documentation = mdplain.Clean(f.VariadicParam.Description)
+	}
+
+	sig := lsp.SignatureInformation{
+		Label:         f.Signature(),
+		Documentation: mdplain.Clean(f.Description),
+		Parameters:    params,
+	}
+
+	activeParam, ok := f.ParamIndex(argIdx)
+	if !ok {
+		// point outside of the list of parameters
+		// for calls with too many arguments
+		activeParam = len(params)
+	}
+	sig.ActiveParameter = uint32(activeParam)
+
+	return &lsp.SignatureHelp{
+		Signatures:      []lsp.SignatureInformation{sig},
+		ActiveSignature: 0,
+		ActiveParameter: uint32(activeParam),
+	}
+}
+
+func FunctionCompletionItems(funcs functions.Functions, prefix string, rng hcl.Range,
+	caps lsp.TextDocumentClientCapabilities) []lsp.CompletionItem {
+	snippetSupport := caps.Completion.CompletionItem.SnippetSupport
+
+	items := make([]lsp.CompletionItem, 0)
+	for _, name := range funcs.Names() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		f := funcs[name]
+
+		te := lang.TextEdit{
+			Range:   rng,
+			NewText: name,
+			Snippet: fmt.Sprintf("%s(${0})", name),
+		}
+
+		items = append(items, lsp.CompletionItem{
+			Label:            name,
+			Kind:             lsp.FunctionCompletion,
+			InsertTextFormat: insertTextFormat(snippetSupport),
+			Detail:           f.Signature(),
+			Documentation:    mdplain.Clean(f.Description),
+			TextEdit:         textEdit(te, snippetSupport),
+		})
+	}
+
+	return items
+}
+
+func FunctionHover(f functions.Function, rng hcl.Range, cc lsp.TextDocumentClientCapabilities) *lsp.Hover {
+	content := fmt.Sprintf("```\n%s\n```\n\n%s", f.Signature(), f.Description)
+	return HoverData(&lang.HoverData{
+		Content: lang.Markdown(content),
+		Range:   rng,
+	}, cc)
+}
@@ -53,3 +53,56 @@ func HCLPosToLSP(pos hcl.Pos) lsp.Position {
 		Character: uint32(pos.Column - 1),
 	}
 }
+
+// HCLRangesToSelectionRange converts ranges ordered
+// from the innermost to the outermost one
+func HCLRangesToSelectionRange(ranges []hcl.Range) lsp.SelectionRange {
+	var parent *lsp.SelectionRange
+	for i := len(ranges) - 1; i > 0; i-- {
+		parent = &lsp.SelectionRange{
+			Range:  HCLRangeToLSP(ranges[i]),
+			Parent: parent,
+		}
+	}
+
+	if len(ranges) == 0 {
+		return lsp.SelectionRange{}
+	}
+
+	return lsp.SelectionRange{
+		Range:  HCLRangeToLSP(ranges[0]),
+		Parent: parent,
+	}
+}
+
+// HCLRangeFromLSP converts the range within the given file,
+// including byte offsets of both positions
+func HCLRangeFromLSP(rng lsp.Range, f File) (hcl.Range, error) {
+	start, err := hclPosFromLSP(rng.Start, f)
+	if err != nil {
+		return hcl.Range{}, err
+	}
+	end, err := hclPosFromLSP(rng.End, f)
+	if err != nil {
+		return hcl.Range{}, err
+	}
+
+	return hcl.Range{
+		Filename: f.Filename(),
+		Start:    start,
+		End:      end,
+	}, nil
+}
+
+func hclPosFromLSP(pos lsp.Position, f File) (hcl.Pos, error) {
+	byteOffset, err := filesystem.ByteOffsetForPos(f.Lines(), lspPosToFsPos(pos))
+	if err != nil {
+		return hcl.Pos{}, err
+	}
+
+	return hcl.Pos{
+		Line:   int(pos.Line) + 1,
+		Column: int(pos.Character) + 1,
+		Byte:   byteOffset,
+	}, nil
+}
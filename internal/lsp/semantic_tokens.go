@@ -1,6 +1,10 @@
 package lsp
 
 import (
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/hcl-lang/lang"
 	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
 )
 
@@ -17,3 +21,141 @@ func (c SemanticTokensClientCapabilities) FullRequest() bool {
 	}
 	return false
 }
+
+func (c SemanticTokensClientCapabilities) FullDeltaRequest() bool {
+	full, ok := c.Requests.Full.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	delta, ok := full["delta"].(bool)
+	return ok && delta
+}
+
+func (c SemanticTokensClientCapabilities) RangeRequest() bool {
+	return c.Requests.Range
+}
+
+// SemanticTokensFullOptions represents the server capability
+// for full semantic token requests which supports deltas
+type SemanticTokensFullOptions struct {
+	Delta bool `json:"delta"`
+}
+
+// SemanticTokensInLines returns tokens which overlap
+// with the given (0-based, inclusive) range of lines
+func SemanticTokensInLines(tokens []lang.SemanticToken, startLine, endLine int) []lang.SemanticToken {
+	filtered := make([]lang.SemanticToken, 0)
+	for _, token := range tokens {
+		if token.Range.End.Line-1 < startLine || token.Range.Start.Line-1 > endLine {
+			continue
+		}
+		filtered = append(filtered, token)
+	}
+	return filtered
+}
+
+// SemanticTokensEdits returns edits which turn previously
+// encoded tokens into the current ones.
+//
+// Highlighting typically changes around a single place being edited,
+// so we only replace the span between common prefix and suffix.
+func SemanticTokensEdits(previous, current []uint32) []lsp.SemanticTokensEdit {
+	prefix := 0
+	for prefix < len(previous) && prefix < len(current) &&
+		previous[prefix] == current[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(previous)-prefix && suffix < len(current)-prefix &&
+		previous[len(previous)-1-suffix] == current[len(current)-1-suffix] {
+		suffix++
+	}
+
+	if prefix == len(previous) && prefix == len(current) {
+		return []lsp.SemanticTokensEdit{}
+	}
+
+	return []lsp.SemanticTokensEdit{
+		{
+			Start:       uint32(prefix),
+			DeleteCount: uint32(len(previous) - prefix - suffix),
+			Data:        current[prefix : len(current)-suffix],
+		},
+	}
+}
+
+// SemanticTokensCache keeps the last encoded tokens of each document,
+// so that subsequent requests can be answered with a delta
+type SemanticTokensCache struct {
+	mu      sync.Mutex
+	lastID  uint64
+	entries map[lsp.DocumentURI]semanticTokensEntry
+}
+
+type semanticTokensEntry struct {
+	resultID string
+	version  int
+	data     []uint32
+}
+
+func NewSemanticTokensCache() *SemanticTokensCache {
+	return &SemanticTokensCache{
+		entries: make(map[lsp.DocumentURI]semanticTokensEntry),
+	}
+}
+
+// Store caches tokens of the given document version and returns
+// result ID to refer to them. The ID is only reused if neither
+// the version nor the tokens have changed since last time.
+func (c *SemanticTokensCache) Store(uri lsp.DocumentURI, version int, data []uint32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[uri]
+	if ok && entry.version == version && equalTokens(entry.data, data) {
+		return entry.resultID
+	}
+
+	c.lastID++
+	resultID := fmt.Sprintf("%d.%d", version, c.lastID)
+	c.entries[uri] = semanticTokensEntry{
+		resultID: resultID,
+		version:  version,
+		data:     data,
+	}
+
+	return resultID
+}
+
+// Get returns tokens of the given result ID if these are still cached
+func (c *SemanticTokensCache) Get(uri lsp.DocumentURI, resultID string) ([]uint32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[uri]
+	if !ok || entry.resultID != resultID {
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// Forget removes any tokens cached for the given document
+func (c *SemanticTokensCache) Forget(uri lsp.DocumentURI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, uri)
+}
+
+func equalTokens(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
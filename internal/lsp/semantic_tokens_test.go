@@ -0,0 +1,130 @@
+package lsp
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+)
+
+func TestSemanticTokensEdits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		previous      []uint32
+		current       []uint32
+		expectedEdits []lsp.SemanticTokensEdit
+	}{
+		{
+			"no change",
+			[]uint32{0, 0, 8, 0, 0},
+			[]uint32{0, 0, 8, 0, 0},
+			[]lsp.SemanticTokensEdit{},
+		},
+		{
+			"token appended",
+			[]uint32{0, 0, 8, 0, 0},
+			[]uint32{0, 0, 8, 0, 0, 1, 2, 4, 1, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 0, Data: []uint32{1, 2, 4, 1, 0}},
+			},
+		},
+		{
+			"token removed",
+			[]uint32{0, 0, 8, 0, 0, 1, 2, 4, 1, 0},
+			[]uint32{0, 0, 8, 0, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+		{
+			"token changed in the middle",
+			[]uint32{0, 0, 8, 0, 0, 1, 2, 4, 1, 0, 1, 2, 3, 1, 0},
+			[]uint32{0, 0, 8, 0, 0, 2, 2, 4, 1, 0, 1, 2, 3, 1, 0},
+			[]lsp.SemanticTokensEdit{
+				{Start: 5, DeleteCount: 1, Data: []uint32{2}},
+			},
+		},
+		{
+			"all tokens removed",
+			[]uint32{0, 0, 8, 0, 0},
+			[]uint32{},
+			[]lsp.SemanticTokensEdit{
+				{Start: 0, DeleteCount: 5, Data: []uint32{}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits := SemanticTokensEdits(tc.previous, tc.current)
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
+
+func TestSemanticTokensInLines(t *testing.T) {
+	tokens := []lang.SemanticToken{
+		{
+			Type: lang.TokenBlockType,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 1, Column: 1, Byte: 0},
+				End:   hcl.Pos{Line: 1, Column: 8, Byte: 7},
+			},
+		},
+		{
+			Type: lang.TokenString,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 2, Column: 10, Byte: 28},
+				End:   hcl.Pos{Line: 4, Column: 4, Byte: 52},
+			},
+		},
+		{
+			Type: lang.TokenAttrName,
+			Range: hcl.Range{
+				Start: hcl.Pos{Line: 5, Column: 3, Byte: 60},
+				End:   hcl.Pos{Line: 5, Column: 11, Byte: 68},
+			},
+		},
+	}
+
+	filtered := SemanticTokensInLines(tokens, 2, 3)
+	expectedTokens := tokens[1:2]
+	if diff := cmp.Diff(expectedTokens, filtered); diff != "" {
+		t.Fatalf("unexpected tokens: %s", diff)
+	}
+}
+
+func TestSemanticTokensCache(t *testing.T) {
+	cache := NewSemanticTokensCache()
+	uri := lsp.DocumentURI("file:///test/main.tf")
+
+	firstID := cache.Store(uri, 1, []uint32{0, 0, 8, 0, 0})
+	if id := cache.Store(uri, 1, []uint32{0, 0, 8, 0, 0}); id != firstID {
+		t.Fatalf("expected result ID to be reused for unchanged tokens, given %q", id)
+	}
+
+	secondID := cache.Store(uri, 2, []uint32{0, 0, 8, 0, 0})
+	if secondID == firstID {
+		t.Fatalf("expected new result ID for new version")
+	}
+
+	if _, ok := cache.Get(uri, firstID); ok {
+		t.Fatalf("expected outdated result %q to be forgotten", firstID)
+	}
+	data, ok := cache.Get(uri, secondID)
+	if !ok {
+		t.Fatalf("expected result %q to be cached", secondID)
+	}
+	if diff := cmp.Diff([]uint32{0, 0, 8, 0, 0}, data); diff != "" {
+		t.Fatalf("unexpected data: %s", diff)
+	}
+
+	cache.Forget(uri)
+	if _, ok := cache.Get(uri, secondID); ok {
+		t.Fatalf("expected result %q to be forgotten", secondID)
+	}
+}
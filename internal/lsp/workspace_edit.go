@@ -0,0 +1,89 @@
+package lsp
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	lsp "github.com/hashicorp/terraform-ls/internal/protocol"
+	"github.com/hashicorp/terraform-ls/internal/uri"
+)
+
+// WorkspaceEdit converts text edits indexed by absolute file path
+func WorkspaceEdit(edits map[string][]lang.TextEdit) lsp.WorkspaceEdit {
+	changes := make(map[string][]lsp.TextEdit, len(edits))
+	for path, fileEdits := range edits {
+		changes[uri.FromPath(path)] = textEdits(fileEdits, false)
+	}
+
+	return lsp.WorkspaceEdit{
+		Changes: changes,
+	}
+}
+
+// ExtendedWorkspaceEdit converts text edits indexed by absolute file path,
+// which may require new files at given paths to be created first.
+//
+// Files can only be created by clients which support it,
+// see SupportsFileCreation.
+func ExtendedWorkspaceEdit(edits map[string][]lang.TextEdit, newFiles []string) *lsp.ExtendedWorkspaceEdit {
+	if len(newFiles) == 0 {
+		return &lsp.ExtendedWorkspaceEdit{
+			Changes: WorkspaceEdit(edits).Changes,
+		}
+	}
+
+	changes := make([]interface{}, 0)
+	for _, path := range newFiles {
+		changes = append(changes, lsp.CreateFile{
+			Kind: "create",
+			URI:  lsp.DocumentURI(uri.FromPath(path)),
+			Options: lsp.CreateFileOptions{
+				IgnoreIfExists: true,
+			},
+		})
+	}
+
+	paths := make([]string, 0, len(edits))
+	for path := range edits {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		changes = append(changes, lsp.UnversionedTextDocumentEdit{
+			TextDocument: lsp.UnversionedTextDocumentIdentifier{
+				URI: lsp.DocumentURI(uri.FromPath(path)),
+			},
+			Edits: textEdits(edits[path], false),
+		})
+	}
+
+	return &lsp.ExtendedWorkspaceEdit{
+		DocumentChanges: changes,
+	}
+}
+
+// SupportsFileCreation reports whether the client can apply
+// workspace edits which create files
+func SupportsFileCreation(cc lsp.ClientCapabilities) bool {
+	return supportsResourceOperation(cc, lsp.Create)
+}
+
+// SupportsFileDeletion reports whether the client can apply
+// workspace edits which delete files
+func SupportsFileDeletion(cc lsp.ClientCapabilities) bool {
+	return supportsResourceOperation(cc, lsp.Delete)
+}
+
+func supportsResourceOperation(cc lsp.ClientCapabilities, kind lsp.ResourceOperationKind) bool {
+	wec := cc.Workspace.WorkspaceEdit
+	if wec == nil || !wec.DocumentChanges {
+		return false
+	}
+	for _, op := range wec.ResourceOperations {
+		if op == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package protocol
+
+import "encoding/json"
+
+// Types below represent parts of LSP 3.17
+// which are not covered by the generated protocol.go yet
+
+// ExtendedServerCapabilities represents server capabilities
+// including those introduced in LSP 3.17
+type ExtendedServerCapabilities struct {
+	ServerCapabilities
+
+	// Workspace shadows ServerCapabilities.Workspace
+	Workspace ExtendedWorkspace `json:"workspace,omitempty"`
+
+	InlayHintProvider  interface{}/* bool | InlayHintOptions */ `json:"inlayHintProvider,omitempty"`
+	DiagnosticProvider interface{}/* DiagnosticOptions */ `json:"diagnosticProvider,omitempty"`
+}
+
+// ExtendedWorkspace represents workspace server capabilities.
+// Unlike the generated FileOperationOptions it allows the server
+// to declare interest in only some of the file operations.
+type ExtendedWorkspace struct {
+	FileOperations   *ExtendedFileOperationOptions `json:"fileOperations,omitempty"`
+	WorkspaceFolders WorkspaceFolders4Gn           `json:"workspaceFolders,omitempty"`
+}
+
+type ExtendedFileOperationOptions struct {
+	DidRename  *FileOperationRegistrationOptions `json:"didRename,omitempty"`
+	WillRename *FileOperationRegistrationOptions `json:"willRename,omitempty"`
+}
+
+// ExtendedInitializeResult represents InitializeResult
+// with capabilities introduced in LSP 3.17
+type ExtendedInitializeResult struct {
+	Capabilities ExtendedServerCapabilities `json:"capabilities"`
+	ServerInfo   struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	} `json:"serverInfo,omitempty"`
+}
+
+// ExtendedClientCapabilities represents client capabilities
+// introduced in LSP 3.17
+type ExtendedClientCapabilities struct {
+	TextDocument struct {
+		Diagnostic *DiagnosticClientCapabilities `json:"diagnostic,omitempty"`
+	} `json:"textDocument"`
+}
+
+// ExtendedInitializeParams represents InitializeParams
+// with client capabilities introduced in LSP 3.17
+type ExtendedInitializeParams struct {
+	InitializeParams
+	ExtendedCapabilities ExtendedClientCapabilities `json:"-"`
+}
+
+func (p *ExtendedInitializeParams) UnmarshalJSON(b []byte) error {
+	err := json.Unmarshal(b, &p.InitializeParams)
+	if err != nil {
+		return err
+	}
+
+	var params struct {
+		Capabilities ExtendedClientCapabilities `json:"capabilities"`
+	}
+	err = json.Unmarshal(b, &params)
+	if err != nil {
+		return err
+	}
+	p.ExtendedCapabilities = params.Capabilities
+
+	return nil
+}
+
+type InlayHintOptions struct {
+	ResolveProvider bool `json:"resolveProvider,omitempty"`
+	WorkDoneProgressOptions
+}
+
+type InlayHintParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	WorkDoneProgressParams
+}
+
+type InlayHintKind float64
+
+const (
+	TypeInlayHint      InlayHintKind = 1
+	ParameterInlayHint InlayHintKind = 2
+)
+
+type InlayHint struct {
+	Position     Position      `json:"position"`
+	Label        string        `json:"label"`
+	Kind         InlayHintKind `json:"kind,omitempty"`
+	Tooltip      string        `json:"tooltip,omitempty"`
+	PaddingLeft  bool          `json:"paddingLeft,omitempty"`
+	PaddingRight bool          `json:"paddingRight,omitempty"`
+}
+
+type DiagnosticClientCapabilities struct {
+	DynamicRegistration    bool `json:"dynamicRegistration,omitempty"`
+	RelatedDocumentSupport bool `json:"relatedDocumentSupport,omitempty"`
+}
+
+type DiagnosticOptions struct {
+	Identifier            string `json:"identifier,omitempty"`
+	InterFileDependencies bool   `json:"interFileDependencies"`
+	WorkspaceDiagnostics  bool   `json:"workspaceDiagnostics"`
+	WorkDoneProgressOptions
+}
+
+const (
+	DiagnosticFull      = "full"
+	DiagnosticUnchanged = "unchanged"
+)
+
+// ExtendedCodeAction represents CodeAction whose edit
+// may contain resource operations
+type ExtendedCodeAction struct {
+	CodeAction
+
+	// Edit shadows CodeAction.Edit
+	Edit *ExtendedWorkspaceEdit `json:"edit,omitempty"`
+}
+
+// ExtendedWorkspaceEdit represents WorkspaceEdit whose document changes
+// may contain resource operations, unlike the generated WorkspaceEdit
+type ExtendedWorkspaceEdit struct {
+	Changes         map[string][]TextEdit `json:"changes,omitempty"`
+	DocumentChanges []interface{}/* UnversionedTextDocumentEdit | CreateFile | DeleteFile */ `json:"documentChanges,omitempty"`
+}
+
+// ExtendedApplyWorkspaceEditParams represents ApplyWorkspaceEditParams
+// whose edit may contain resource operations
+type ExtendedApplyWorkspaceEditParams struct {
+	Label string                 `json:"label,omitempty"`
+	Edit  *ExtendedWorkspaceEdit `json:"edit"`
+}
+
+// UnversionedTextDocumentEdit represents TextDocumentEdit of a document
+// whose version is unknown, such as one created as part of the same edit
+type UnversionedTextDocumentEdit struct {
+	TextDocument UnversionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                        `json:"edits"`
+}
+
+type UnversionedTextDocumentIdentifier struct {
+	URI DocumentURI `json:"uri"`
+	// Version is always null
+	Version *int32 `json:"version"`
+}
@@ -0,0 +1,160 @@
+package refactor
+
+import (
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ModuleCall represents a module block
+// as an item of the module call hierarchy
+type ModuleCall struct {
+	// CallerPath is path to the module which contains the module block
+	CallerPath string
+	Name       string
+	SourceAddr string
+
+	// Path is path to the called module
+	// (empty if the module is not installed or not local)
+	Path string
+
+	// Range and NameRange are ranges of the block and its label
+	// with filenames relative to the caller module
+	Range     hcl.Range
+	NameRange hcl.Range
+}
+
+// ModuleCallAtPos returns module call declared by the module block
+// at the given position, if any
+func ModuleCallAtPos(mf module.ModuleFinder, modPath, filename string, pos hcl.Pos) (*ModuleCall, bool, error) {
+	return findModuleCall(mf, modPath, func(call *ModuleCall) bool {
+		return call.Range.Filename == filename && call.Range.ContainsPos(pos)
+	})
+}
+
+// ModuleCallByName returns module call declared
+// by the module block of the given name, if any
+func ModuleCallByName(mf module.ModuleFinder, modPath, name string) (*ModuleCall, bool, error) {
+	return findModuleCall(mf, modPath, func(call *ModuleCall) bool {
+		return call.Name == name
+	})
+}
+
+func findModuleCall(mf module.ModuleFinder, modPath string, match func(*ModuleCall) bool) (*ModuleCall, bool, error) {
+	mod, err := mf.ModuleByPath(modPath)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, sf := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+		for _, call := range moduleBlockCalls(mod.Path, sf) {
+			if !match(call) {
+				continue
+			}
+			err = resolveCallPath(mf, call)
+			if err != nil {
+				return nil, false, err
+			}
+			return call, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// IncomingModuleCalls returns all module blocks
+// calling the module which contains the given call
+func IncomingModuleCalls(mf module.ModuleFinder, call *ModuleCall) ([]*ModuleCall, error) {
+	calls, err := callsOfModule(mf, call.CallerPath)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming := make([]*ModuleCall, 0)
+	for _, c := range calls {
+		caller, err := mf.ModuleByPath(c.CallerPath)
+		if err != nil {
+			continue
+		}
+		for _, sf := range syntaxFiles(caller.ParsedModuleFiles.AsMap()) {
+			for _, blockCall := range moduleBlockCalls(caller.Path, sf) {
+				if blockCall.Name != c.Name {
+					continue
+				}
+				blockCall.Path = c.Path
+				incoming = append(incoming, blockCall)
+			}
+		}
+	}
+
+	return incoming, nil
+}
+
+// OutgoingModuleCalls returns all module blocks
+// within the module called by the given call
+func OutgoingModuleCalls(mf module.ModuleFinder, call *ModuleCall) ([]*ModuleCall, error) {
+	outgoing := make([]*ModuleCall, 0)
+	if call.Path == "" {
+		return outgoing, nil
+	}
+
+	mod, err := mf.ModuleByPath(call.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sf := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+		for _, blockCall := range moduleBlockCalls(mod.Path, sf) {
+			err = resolveCallPath(mf, blockCall)
+			if err != nil {
+				return nil, err
+			}
+			outgoing = append(outgoing, blockCall)
+		}
+	}
+
+	return outgoing, nil
+}
+
+// moduleBlockCalls returns calls of all module blocks in the file
+// without resolving paths of the called modules
+func moduleBlockCalls(callerPath string, sf syntaxFile) []*ModuleCall {
+	calls := make([]*ModuleCall, 0)
+	for _, block := range sf.Body.Blocks {
+		if block.Type != "module" || len(block.Labels) != 1 {
+			continue
+		}
+		calls = append(calls, &ModuleCall{
+			CallerPath: callerPath,
+			Name:       block.Labels[0],
+			SourceAddr: sourceAddr(block.Body),
+			Range:      block.Range(),
+			NameRange:  labelNameRange(sf.Bytes, block.LabelRanges[0]),
+		})
+	}
+	return calls
+}
+
+func sourceAddr(body *hclsyntax.Body) string {
+	attr, ok := body.Attributes["source"]
+	if !ok {
+		return ""
+	}
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+		return ""
+	}
+	return val.AsString()
+}
+
+func resolveCallPath(mf module.ModuleFinder, call *ModuleCall) error {
+	path, ok, err := calledModulePath(mf, call.CallerPath, call.Name)
+	if err != nil {
+		return err
+	}
+	if ok {
+		call.Path = path
+	}
+	return nil
+}
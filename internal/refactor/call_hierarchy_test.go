@@ -0,0 +1,206 @@
+package refactor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+func TestModuleCallHierarchy(t *testing.T) {
+	rootDir, mm := loadNestedTestModules(t)
+	appDir := filepath.Join(rootDir, "app")
+	dbDir := filepath.Join(rootDir, "db")
+
+	appCall := &ModuleCall{
+		CallerPath: rootDir,
+		Name:       "app",
+		SourceAddr: "./app",
+		Path:       appDir,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 3, Column: 2, Byte: 35},
+		},
+		NameRange: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+			End:      hcl.Pos{Line: 1, Column: 12, Byte: 11},
+		},
+	}
+	dbCall := &ModuleCall{
+		CallerPath: appDir,
+		Name:       "db",
+		SourceAddr: "../db",
+		Path:       dbDir,
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 1, Byte: 0},
+			End:      hcl.Pos{Line: 3, Column: 2, Byte: 34},
+		},
+		NameRange: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 1, Column: 9, Byte: 8},
+			End:      hcl.Pos{Line: 1, Column: 11, Byte: 10},
+		},
+	}
+	vpcCall := &ModuleCall{
+		CallerPath: rootDir,
+		Name:       "vpc",
+		SourceAddr: "terraform-aws-modules/vpc/aws",
+		Range: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 5, Column: 1, Byte: 37},
+			End:      hcl.Pos{Line: 7, Column: 2, Byte: 96},
+		},
+		NameRange: hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 5, Column: 9, Byte: 45},
+			End:      hcl.Pos{Line: 5, Column: 12, Byte: 48},
+		},
+	}
+
+	call, ok, err := ModuleCallAtPos(mm, rootDir, "main.tf", hcl.Pos{Line: 2, Column: 5, Byte: 18})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected module call to be found")
+	}
+	if diff := cmp.Diff(appCall, call); diff != "" {
+		t.Fatalf("unexpected call: %s", diff)
+	}
+
+	outgoing, err := OutgoingModuleCalls(mm, appCall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*ModuleCall{dbCall}, outgoing); diff != "" {
+		t.Fatalf("unexpected outgoing calls of app: %s", diff)
+	}
+
+	outgoing, err = OutgoingModuleCalls(mm, dbCall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*ModuleCall{}, outgoing); diff != "" {
+		t.Fatalf("unexpected outgoing calls of db: %s", diff)
+	}
+
+	outgoing, err = OutgoingModuleCalls(mm, vpcCall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*ModuleCall{}, outgoing); diff != "" {
+		t.Fatalf("unexpected outgoing calls of vpc: %s", diff)
+	}
+
+	incoming, err := IncomingModuleCalls(mm, dbCall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*ModuleCall{appCall}, incoming); diff != "" {
+		t.Fatalf("unexpected incoming calls of db: %s", diff)
+	}
+
+	incoming, err = IncomingModuleCalls(mm, appCall)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*ModuleCall{}, incoming); diff != "" {
+		t.Fatalf("unexpected incoming calls of app: %s", diff)
+	}
+}
+
+const testNestedManifest = `{
+  "Modules": [
+    {
+      "Key": "",
+      "Source": "",
+      "Dir": "."
+    },
+    {
+      "Key": "app",
+      "Source": "./app",
+      "Dir": "app"
+    },
+    {
+      "Key": "app.db",
+      "Source": "../db",
+      "Dir": "db"
+    },
+    {
+      "Key": "vpc",
+      "Source": "terraform-aws-modules/vpc/aws",
+      "Version": "3.0.0",
+      "Dir": ".terraform/modules/vpc"
+    }
+  ]
+}`
+
+func loadNestedTestModules(t *testing.T) (string, module.ModuleManager) {
+	rootDir := t.TempDir()
+	appDir := filepath.Join(rootDir, "app")
+	dbDir := filepath.Join(rootDir, "db")
+
+	files := map[string]string{
+		filepath.Join(rootDir, "main.tf"): `module "app" {
+  source = "./app"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`,
+		filepath.Join(appDir, "main.tf"): `module "db" {
+  source = "../db"
+}
+`,
+		filepath.Join(dbDir, "main.tf"): `resource "random_pet" "db" {
+}
+`,
+		filepath.Join(rootDir, ".terraform", "modules", "modules.json"): testNestedManifest,
+	}
+	for path, content := range files {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm := module.NewSyncModuleManager(context.Background(), filesystem.NewFilesystem(), ss.Modules, ss.ProviderSchemas)
+	for _, modPath := range []string{rootDir, appDir, dbDir} {
+		_, err := mm.AddModule(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if modPath == rootDir {
+			err = mm.EnqueueModuleOpWait(modPath, op.OpTypeParseModuleManifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		err = mm.EnqueueModuleOpWait(modPath, op.OpTypeParseModuleConfiguration)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return rootDir, mm
+}
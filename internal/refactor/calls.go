@@ -0,0 +1,118 @@
+package refactor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-ls/internal/pathcmp"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// moduleCall represents a call of a local module
+// as recorded in the module manifest of a root module
+type moduleCall struct {
+	// CallerPath is path to the module which contains the module block
+	CallerPath string
+	// Name is the label of the module block
+	Name string
+	// Path is path to the called module
+	Path string
+}
+
+// localModuleCalls returns all calls of local modules
+// known from manifests of installed root modules
+func localModuleCalls(mf module.ModuleFinder) ([]moduleCall, error) {
+	mods, err := mf.ListModules()
+	if err != nil {
+		return nil, err
+	}
+	return manifestCalls(mods), nil
+}
+
+func manifestCalls(mods []module.Module) []moduleCall {
+	calls := make([]moduleCall, 0)
+	for _, mod := range mods {
+		if mod.ModManifest == nil {
+			continue
+		}
+
+		dirs := map[string]string{
+			"": mod.Path,
+		}
+		for _, record := range mod.ModManifest.Records {
+			if record.IsRoot() || record.IsExternal() {
+				continue
+			}
+			dirs[record.Key] = filepath.Join(mod.Path, record.Dir)
+		}
+
+		for _, record := range mod.ModManifest.Records {
+			if record.IsRoot() || record.IsExternal() {
+				continue
+			}
+
+			parentKey, name := "", record.Key
+			if i := strings.LastIndex(record.Key, "."); i >= 0 {
+				parentKey, name = record.Key[:i], record.Key[i+1:]
+			}
+			callerPath, ok := dirs[parentKey]
+			if !ok {
+				continue
+			}
+
+			call := moduleCall{
+				CallerPath: callerPath,
+				Name:       name,
+				Path:       dirs[record.Key],
+			}
+			if !containsCall(calls, call) {
+				calls = append(calls, call)
+			}
+		}
+	}
+
+	return calls
+}
+
+func containsCall(calls []moduleCall, call moduleCall) bool {
+	for _, c := range calls {
+		if c.Name == call.Name &&
+			pathcmp.PathEquals(c.CallerPath, call.CallerPath) &&
+			pathcmp.PathEquals(c.Path, call.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// callsOfModule returns all calls of the module at given path
+func callsOfModule(mf module.ModuleFinder, modPath string) ([]moduleCall, error) {
+	callers, err := mf.CallersOfModule(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]moduleCall, 0)
+	for _, call := range manifestCalls(callers) {
+		if pathcmp.PathEquals(call.Path, modPath) {
+			result = append(result, call)
+		}
+	}
+	return result, nil
+}
+
+// calledModulePath returns path to the module called
+// from the module at callerPath via the module block of given name
+func calledModulePath(mf module.ModuleFinder, callerPath, name string) (string, bool, error) {
+	calls, err := localModuleCalls(mf)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, call := range calls {
+		if call.Name == name && pathcmp.PathEquals(call.CallerPath, callerPath) {
+			return call.Path, true, nil
+		}
+	}
+	return "", false, nil
+}
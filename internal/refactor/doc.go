@@ -0,0 +1,12 @@
+/*
+Package refactor provides reference-aware transformations
+of Terraform configuration, such as renaming of declarations.
+
+Transformations are calculated from the already decoded module state
+(parsed files, reference targets and origins) and expressed as Edits,
+which keeps the package independent of LSP types.
+
+The package also provides navigation of the module call hierarchy,
+which is based on the same knowledge of module calls.
+*/
+package refactor
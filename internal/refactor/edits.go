@@ -0,0 +1,159 @@
+package refactor
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// Edits represents text edits across one or more files,
+// indexed by absolute path of each file
+type Edits map[string][]lang.TextEdit
+
+// Add appends an edit for the file at given path
+// unless the same range is already being edited
+func (e Edits) Add(path string, rng hcl.Range, newText string) {
+	for _, edit := range e[path] {
+		if edit.Range == rng {
+			return
+		}
+	}
+	e[path] = append(e[path], lang.TextEdit{
+		Range:   rng,
+		NewText: newText,
+	})
+}
+
+// Paths returns sorted paths of all edited files
+func (e Edits) Paths() []string {
+	paths := make([]string, 0, len(e))
+	for path := range e {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// FileChange represents content of a file before and after a change
+type FileChange struct {
+	Before []byte
+	After  []byte
+}
+
+// fileChanges applies edits of files (indexed by file name)
+// and returns changes indexed by absolute path
+func fileChanges(mod module.Module, edits map[string][]byteEdit) map[string]FileChange {
+	files := make(map[string]FileChange, len(edits))
+	for name, fileEdits := range edits {
+		before := mod.ParsedModuleFiles[ast.ModFilename(name)].Bytes
+		files[filepath.Join(mod.Path, name)] = FileChange{
+			Before: before,
+			After:  applyByteEdits(before, fileEdits),
+		}
+	}
+	return files
+}
+
+type byteEdit struct {
+	Start, End int
+	Text       string
+}
+
+func applyByteEdits(src []byte, edits []byteEdit) []byte {
+	sorted := make([]byteEdit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start > sorted[j].Start
+	})
+
+	out := string(src)
+	for _, e := range sorted {
+		out = out[:e.Start] + e.Text + out[e.End:]
+	}
+	return []byte(out)
+}
+
+// insertIntoBlock returns edit inserting the entry as the last line
+// of the block body, indented one level deeper than the block
+func insertIntoBlock(src []byte, block *hclsyntax.Block, entry string) byteEdit {
+	indent := lineIndent(src, block.TypeRange.Start.Byte)
+	closeBrace := block.CloseBraceRange.Start.Byte
+
+	braceLineStart := lineStart(src, closeBrace)
+	if strings.TrimSpace(string(src[braceLineStart:closeBrace])) == "" {
+		return byteEdit{
+			Start: braceLineStart,
+			End:   braceLineStart,
+			Text:  indent + "  " + reindent(entry, "", indent) + "\n",
+		}
+	}
+
+	return byteEdit{
+		Start: closeBrace,
+		End:   closeBrace,
+		Text:  "\n" + indent + "  " + reindent(entry, "", indent) + "\n" + indent,
+	}
+}
+
+func appendToFile(src []byte, text string) byteEdit {
+	content := string(src)
+	switch {
+	case strings.TrimSpace(content) == "":
+	case strings.HasSuffix(content, "\n"):
+		text = "\n" + text
+	default:
+		text = "\n\n" + text
+	}
+	return byteEdit{
+		Start: len(src),
+		End:   len(src),
+		Text:  text,
+	}
+}
+
+func lineStart(src []byte, offset int) int {
+	for offset > 0 && src[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+func lineIndent(src []byte, offset int) string {
+	start := lineStart(src, offset)
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+// lineEnd returns offset of the beginning of the next line
+func lineEnd(src []byte, offset int) int {
+	for offset < len(src) && src[offset] != '\n' {
+		offset++
+	}
+	if offset < len(src) {
+		offset++
+	}
+	return offset
+}
+
+// posAtByte returns position of the given byte offset within src
+func posAtByte(src []byte, offset int) hcl.Pos {
+	pos := hcl.Pos{Line: 1, Column: 1, Byte: offset}
+	for _, r := range string(src[:offset]) {
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+			continue
+		}
+		pos.Column++
+	}
+	return pos
+}
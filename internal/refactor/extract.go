@@ -0,0 +1,486 @@
+package refactor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ExtractTarget represents the kind of declaration
+// which an expression is extracted into
+type ExtractTarget int
+
+const (
+	ExtractToLocal ExtractTarget = iota
+	ExtractToVariable
+)
+
+const (
+	localsFilename    = "locals.tf"
+	variablesFilename = "variables.tf"
+)
+
+// Extraction represents changes which extract an expression
+// into a new local value or input variable declaration
+// and replace the expression with a reference to it
+type Extraction struct {
+	Target ExtractTarget
+	Name   string
+
+	// Files are changes of all affected files indexed by absolute path
+	Files map[string]FileChange
+}
+
+// Extract calculates changes extracting the expression found within rng
+// of the given file. The whole value of an attribute is extracted if rng
+// is empty. False is returned if there is no extractable expression.
+//
+// Only expressions which do not depend on the surrounding scope
+// (e.g. count or each) can be extracted and only constant expressions
+// can become a default value of a variable.
+func Extract(mod module.Module, filename string, rng hcl.Range, target ExtractTarget) (*Extraction, bool) {
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+
+	found, ok := findExtractableExpr(body, rng)
+	if !ok {
+		return nil, false
+	}
+	if !isExtractableToScope(found, target) {
+		return nil, false
+	}
+
+	var typeConstraint string
+	if target == ExtractToVariable {
+		val, diags := found.expr.Value(nil)
+		if diags.HasErrors() || !val.IsWhollyKnown() {
+			return nil, false
+		}
+		typeConstraint = typeexpr.TypeString(variableType(val))
+	}
+
+	src := f.Bytes
+	exprRng := found.expr.Range()
+	exprText := string(exprRng.SliceBytes(src))
+
+	name := uniqueName(declaredNames(mod, target), found.name)
+
+	var ref string
+	switch target {
+	case ExtractToLocal:
+		ref = "local." + name
+	case ExtractToVariable:
+		ref = "var." + name
+	}
+
+	edits := map[string][]byteEdit{
+		filename: {
+			{Start: exprRng.Start.Byte, End: exprRng.End.Byte, Text: ref},
+		},
+	}
+
+	srcIndent := lineIndent(src, exprRng.Start.Byte)
+	blockStart := lineStart(src, found.topBlock.Range().Start.Byte)
+
+	switch target {
+	case ExtractToLocal:
+		entry := fmt.Sprintf("%s = %s", name, reindent(exprText, srcIndent, "  "))
+		declFile, edit := localsEdit(mod, filename, blockStart, entry)
+		edits[declFile] = append(edits[declFile], edit)
+	case ExtractToVariable:
+		declText := fmt.Sprintf("variable %q {\n  type    = %s\n  default = %s\n}\n",
+			name, typeConstraint, reindent(exprText, srcIndent, "  "))
+		declFile, edit := variableEdit(mod, filename, blockStart, declText)
+		edits[declFile] = append(edits[declFile], edit)
+	}
+
+	return &Extraction{
+		Target: target,
+		Name:   name,
+		Files:  fileChanges(mod, edits),
+	}, true
+}
+
+// localsEdit returns edit which adds the entry into locals.tf
+// if it exists, or into the first locals block of the file,
+// or into a new block inserted at blockStart
+func localsEdit(mod module.Module, filename string, blockStart int, entry string) (string, byteEdit) {
+	if f, ok := mod.ParsedModuleFiles[localsFilename]; ok {
+		if body, ok := f.Body.(*hclsyntax.Body); ok {
+			var lastBlock *hclsyntax.Block
+			for _, block := range body.Blocks {
+				if block.Type == "locals" {
+					lastBlock = block
+				}
+			}
+			if lastBlock != nil {
+				return localsFilename, insertIntoBlock(f.Bytes, lastBlock, entry)
+			}
+			return localsFilename, appendToFile(f.Bytes, "locals {\n  "+entry+"\n}\n")
+		}
+	}
+
+	f := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	body := f.Body.(*hclsyntax.Body)
+	for _, block := range body.Blocks {
+		if block.Type == "locals" {
+			return filename, insertIntoBlock(f.Bytes, block, entry)
+		}
+	}
+
+	return filename, byteEdit{
+		Start: blockStart,
+		End:   blockStart,
+		Text:  "locals {\n  " + entry + "\n}\n\n",
+	}
+}
+
+// variableEdit returns edit which appends the declaration
+// to variables.tf if it exists, or inserts it at blockStart
+func variableEdit(mod module.Module, filename string, blockStart int, decl string) (string, byteEdit) {
+	if f, ok := mod.ParsedModuleFiles[variablesFilename]; ok {
+		return variablesFilename, appendToFile(f.Bytes, decl)
+	}
+
+	return filename, byteEdit{
+		Start: blockStart,
+		End:   blockStart,
+		Text:  decl + "\n",
+	}
+}
+
+type extractableExpr struct {
+	expr     hclsyntax.Expression
+	name     string
+	topBlock *hclsyntax.Block
+
+	// scopeNames are names of symbols declared by the surrounding
+	// for expressions or dynamic blocks
+	scopeNames map[string]bool
+}
+
+// attributes which only accept static references or literal values
+var staticAttributes = map[string]map[string]bool{
+	"module": {
+		"source":  true,
+		"version": true,
+	},
+	"resource": {
+		"depends_on": true,
+		"provider":   true,
+	},
+	"data": {
+		"depends_on": true,
+		"provider":   true,
+	},
+	"output": {
+		"depends_on": true,
+	},
+}
+
+func findExtractableExpr(body *hclsyntax.Body, rng hcl.Range) (*extractableExpr, bool) {
+	for _, block := range body.Blocks {
+		if !rangeContainsRange(block.Range(), rng) {
+			continue
+		}
+		switch block.Type {
+		case "resource", "data", "module", "output", "locals", "provider":
+		default:
+			// e.g. variable defaults or terraform settings
+			// cannot contain references
+			return nil, false
+		}
+
+		return findExtractableExprInBody(block, block.Body, rng, map[string]bool{})
+	}
+	return nil, false
+}
+
+func findExtractableExprInBody(topBlock *hclsyntax.Block, body *hclsyntax.Body, rng hcl.Range, scopeNames map[string]bool) (*extractableExpr, bool) {
+	for _, attr := range body.Attributes {
+		if !rangeContainsRange(attr.Expr.Range(), rng) {
+			continue
+		}
+		if body == topBlock.Body && staticAttributes[topBlock.Type][attr.Name] {
+			return nil, false
+		}
+
+		if rng.Empty() {
+			return &extractableExpr{
+				expr:       attr.Expr,
+				name:       attr.Name,
+				topBlock:   topBlock,
+				scopeNames: scopeNames,
+			}, true
+		}
+
+		w := &exprFinder{rng: rng, scopeNames: scopeNames, name: attr.Name}
+		hclsyntax.Walk(attr.Expr, w)
+		if w.found == nil {
+			return nil, false
+		}
+		return &extractableExpr{
+			expr:       w.found,
+			name:       w.foundName,
+			topBlock:   topBlock,
+			scopeNames: w.foundScopeNames,
+		}, true
+	}
+
+	for _, block := range body.Blocks {
+		if !rangeContainsRange(block.Body.Range(), rng) {
+			continue
+		}
+		if block.Type == "lifecycle" {
+			return nil, false
+		}
+
+		names := scopeNames
+		if block.Type == "dynamic" && len(block.Labels) == 1 {
+			names = copyNames(scopeNames)
+			names[dynamicIteratorName(block)] = true
+		}
+		return findExtractableExprInBody(topBlock, block.Body, rng, names)
+	}
+
+	return nil, false
+}
+
+// exprFinder finds the smallest replaceable expression containing the range
+type exprFinder struct {
+	rng        hcl.Range
+	scopeNames map[string]bool
+	name       string
+
+	stack []hclsyntax.Node
+
+	found           hclsyntax.Expression
+	foundName       string
+	foundScopeNames map[string]bool
+}
+
+func (w *exprFinder) Enter(node hclsyntax.Node) hcl.Diagnostics {
+	w.stack = append(w.stack, node)
+
+	expr, ok := node.(hclsyntax.Expression)
+	if !ok || !rangeContainsRange(expr.Range(), w.rng) {
+		return nil
+	}
+
+	ancestors := w.stack[:len(w.stack)-1]
+	for _, n := range ancestors {
+		// object keys are mostly static
+		if _, ok := n.(*hclsyntax.ObjectConsKeyExpr); ok {
+			return nil
+		}
+	}
+	if _, ok := expr.(*hclsyntax.ObjectConsKeyExpr); ok {
+		return nil
+	}
+
+	name := w.name
+	if len(ancestors) > 0 {
+		switch parent := ancestors[len(ancestors)-1].(type) {
+		case *hclsyntax.TemplateExpr:
+			// parts of templates cannot be replaced on their own
+			return nil
+		case *hclsyntax.ObjectConsExpr:
+			for _, item := range parent.Items {
+				if item.ValueExpr != expr {
+					continue
+				}
+				if key, ok := objectKeyName(item.KeyExpr); ok {
+					name = strings.ToLower(key)
+				}
+			}
+		}
+	}
+
+	scopeNames := copyNames(w.scopeNames)
+	for _, n := range ancestors {
+		forExpr, ok := n.(*hclsyntax.ForExpr)
+		if !ok || rangeContainsRange(forExpr.CollExpr.Range(), expr.Range()) {
+			continue
+		}
+		if forExpr.KeyVar != "" {
+			scopeNames[forExpr.KeyVar] = true
+		}
+		scopeNames[forExpr.ValVar] = true
+	}
+
+	w.found = expr
+	w.foundName = name
+	w.foundScopeNames = scopeNames
+
+	return nil
+}
+
+func (w *exprFinder) Exit(node hclsyntax.Node) hcl.Diagnostics {
+	w.stack = w.stack[:len(w.stack)-1]
+	return nil
+}
+
+func objectKeyName(expr hclsyntax.Expression) (string, bool) {
+	keyExpr, ok := expr.(*hclsyntax.ObjectConsKeyExpr)
+	if !ok {
+		return "", false
+	}
+	key := hcl.ExprAsKeyword(keyExpr.Wrapped)
+	if key == "" {
+		val, diags := keyExpr.Wrapped.Value(nil)
+		if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.String {
+			return "", false
+		}
+		key = val.AsString()
+	}
+	if !hclsyntax.ValidIdentifier(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// isExtractableToScope reports whether the expression can be evaluated
+// outside of its current scope, i.e. in a locals block or as a default
+func isExtractableToScope(found *extractableExpr, target ExtractTarget) bool {
+	if st, ok := found.expr.(*hclsyntax.ScopeTraversalExpr); ok && len(st.Traversal) == 2 {
+		switch st.Traversal.RootName() {
+		case "local", "var":
+			// already is a reference to a declaration
+			return false
+		}
+	}
+
+	traversals := found.expr.Variables()
+	if target == ExtractToVariable && len(traversals) > 0 {
+		// defaults of variables cannot contain references
+		return false
+	}
+
+	for _, traversal := range traversals {
+		root := traversal.RootName()
+		switch root {
+		case "count", "each", "self":
+			return false
+		}
+		if found.scopeNames[root] {
+			return false
+		}
+	}
+	return true
+}
+
+func dynamicIteratorName(block *hclsyntax.Block) string {
+	if attr, ok := block.Body.Attributes["iterator"]; ok {
+		if name := hcl.ExprAsKeyword(attr.Expr); name != "" {
+			return name
+		}
+	}
+	return block.Labels[0]
+}
+
+func copyNames(names map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(names))
+	for name := range names {
+		c[name] = true
+	}
+	return c
+}
+
+// declaredNames returns names of local values or variables
+// known as reference targets of the module
+func declaredNames(mod module.Module, target ExtractTarget) map[string]bool {
+	root := "local"
+	if target == ExtractToVariable {
+		root = "var"
+	}
+
+	names := make(map[string]bool, 0)
+	for _, refTarget := range mod.RefTargets {
+		if len(refTarget.Addr) < 2 || refTarget.Addr[0].String() != root {
+			continue
+		}
+		if name, ok := stepName(refTarget.Addr[1]); ok {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+func uniqueName(declared map[string]bool, name string) string {
+	if !declared[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s_%d", name, i)
+		if !declared[candidate] {
+			return candidate
+		}
+	}
+}
+
+// variableType returns type constraint for a variable with the given
+// default value, preferring collection types over structural ones
+func variableType(val cty.Value) cty.Type {
+	ty := val.Type()
+	switch {
+	case ty.IsTupleType() || ty.IsListType() || ty.IsSetType():
+		elemType, ok := uniformType(val)
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return cty.List(elemType)
+	case ty.IsObjectType() || ty.IsMapType():
+		elemType, ok := uniformType(val)
+		if !ok {
+			return cty.DynamicPseudoType
+		}
+		return cty.Map(elemType)
+	}
+	return ty
+}
+
+func uniformType(val cty.Value) (cty.Type, bool) {
+	if val.IsNull() || val.LengthInt() == 0 {
+		return cty.DynamicPseudoType, true
+	}
+
+	var elemType cty.Type
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		ty := variableType(v)
+		if elemType == cty.NilType {
+			elemType = ty
+			continue
+		}
+		if !elemType.Equals(ty) {
+			return cty.NilType, false
+		}
+	}
+	return elemType, true
+}
+
+// reindent replaces indentation of continuation lines
+// of a multi-line expression
+func reindent(text, oldIndent, newIndent string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = newIndent + strings.TrimPrefix(lines[i], oldIndent)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func rangeContainsRange(outer, inner hcl.Range) bool {
+	return outer.Start.Byte <= inner.Start.Byte && inner.End.Byte <= outer.End.Byte
+}
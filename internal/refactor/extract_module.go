@@ -0,0 +1,544 @@
+package refactor
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	tfmod "github.com/hashicorp/terraform-schema/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	modulesDirname = "modules"
+	mainFilename   = "main.tf"
+)
+
+// ModuleExtraction represents changes moving blocks
+// into a new module and replacing them with a call of it
+type ModuleExtraction struct {
+	// Name of the module call, which is also the name
+	// of the new module directory within modules
+	Name string
+
+	Edits    Edits
+	NewFiles []string
+}
+
+// ExtractModule calculates changes moving resource, data and locals blocks
+// of the given file within rng into main.tf of a new module in modules/<name>
+// and replacing them with a module call.
+//
+// References from the blocks to any other declarations become input
+// variables of the new module, as does path.module, which would otherwise
+// refer to the new module directory, and references to the moved declarations
+// from elsewhere in the module become references to its outputs.
+// moved blocks are added for moved resources, so that Terraform moves
+// existing objects into the module instead of replacing them. These are
+// appended to movedFilename if it exists, or placed after the module call.
+//
+// False is returned if there is no such block within the range, if any
+// of them configures provider (which would have to be passed explicitly),
+// or if Terraform version is older than 1.1, which does not support moved blocks.
+func ExtractModule(mf module.ModuleFinder, modPath, filename string, rng hcl.Range, movedFilename string) (*ModuleExtraction, bool, error) {
+	mod, err := mf.ModuleByPath(modPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if mod.TerraformVersion != nil && mod.TerraformVersion.LessThan(v1_1) {
+		return nil, false, nil
+	}
+
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return nil, false, nil
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false, nil
+	}
+	src := f.Bytes
+
+	blocks := extractableBlocks(body, rng)
+	if len(blocks) == 0 {
+		return nil, false, nil
+	}
+	for _, block := range blocks {
+		if _, ok := block.Body.Attributes["provider"]; ok {
+			return nil, false, nil
+		}
+	}
+
+	isWithinBlocks := func(rng hcl.Range) bool {
+		if rng.Filename != filename {
+			return false
+		}
+		for _, block := range blocks {
+			if rangeContainsRange(block.Range(), rng) {
+				return true
+			}
+		}
+		return false
+	}
+
+	moved := make([]lang.Address, 0)
+	calls := make(map[string]bool, 0)
+	for _, target := range mod.RefTargets {
+		if len(target.Addr) == 2 && target.Addr[0].String() == "module" {
+			if name, ok := stepName(target.Addr[1]); ok {
+				calls[name] = true
+			}
+		}
+		if target.RangePtr != nil && isWithinBlocks(*target.RangePtr) {
+			moved = append(moved, target.Addr)
+		}
+	}
+
+	name := extractedModuleName(blocks)
+	for {
+		name = uniqueName(calls, name)
+		_, err := mf.ModuleByPath(filepath.Join(mod.Path, modulesDirname, name))
+		if module.IsModuleNotFound(err) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		calls[name] = true
+	}
+
+	variables := make([]extractedValue, 0)
+	outputs := make([]extractedValue, 0)
+	innerEdits := make([]byteEdit, 0)
+	outerEdits := make(map[string][]byteEdit, 0)
+
+	files := mod.ParsedModuleFiles.AsMap()
+	seen := make(map[hcl.Range]bool, 0)
+	for _, origin := range mod.RefOrigins {
+		if seen[origin.Range] {
+			continue
+		}
+		seen[origin.Range] = true
+
+		of, ok := files[origin.Range.Filename]
+		if !ok {
+			continue
+		}
+		if _, ok := of.Body.(*hclsyntax.Body); !ok {
+			continue
+		}
+		ranges, ok := traversalStepRanges(of.Bytes, origin.Range)
+		if !ok || len(ranges) != len(origin.Addr) {
+			continue
+		}
+
+		decl, isMoved := movedDeclaration(moved, origin.Addr)
+
+		if isWithinBlocks(origin.Range) {
+			if isMoved {
+				continue
+			}
+			addr, ok := referencedValue(origin.Addr)
+			if !ok {
+				continue
+			}
+			v := addExtractedValue(&variables, addr, variableName(addr))
+			innerEdits = append(innerEdits, byteEdit{
+				Start: origin.Range.Start.Byte,
+				End:   ranges[len(addr)-1].End.Byte,
+				Text:  "var." + v.name,
+			})
+			continue
+		}
+
+		if !isMoved {
+			continue
+		}
+		addr := origin.Addr[:len(decl)]
+		if len(origin.Addr) > len(decl) {
+			if _, ok := stepName(origin.Addr[len(decl)]); ok {
+				addr = origin.Addr[:len(decl)+1]
+			}
+		}
+		v := addExtractedValue(&outputs, addr, variableName(addr))
+		outerEdits[origin.Range.Filename] = append(outerEdits[origin.Range.Filename], byteEdit{
+			Start: origin.Range.Start.Byte,
+			End:   ranges[len(addr)-1].End.Byte,
+			Text:  fmt.Sprintf("module.%s.%s", name, v.name),
+		})
+	}
+
+	// path.module would refer to the directory of the new module,
+	// so the directory of the original one is passed in instead
+	for _, rng := range pathModuleReferences(blocks) {
+		v := addExtractedValue(&variables, pathModuleAddr, "parent_module_path")
+		innerEdits = append(innerEdits, byteEdit{
+			Start: rng.Start.Byte,
+			End:   rng.End.Byte,
+			Text:  "var." + v.name,
+		})
+	}
+
+	modDir := filepath.Join(mod.Path, modulesDirname, name)
+	edits := make(Edits, 0)
+	newFiles := make([]string, 0)
+	addNewFile := func(filename, text string) {
+		p := filepath.Join(modDir, filename)
+		edits.Add(p, hcl.Range{
+			Filename: filename,
+			Start:    hcl.InitialPos,
+			End:      hcl.InitialPos,
+		}, text)
+		newFiles = append(newFiles, p)
+	}
+
+	// main.tf with the blocks and providers they require
+	mainBlocks := make([]string, 0, len(blocks)+1)
+	if req, ok := requiredProviders(mod, blocks); ok {
+		mainBlocks = append(mainBlocks, req)
+	}
+	for _, block := range blocks {
+		start, end := blockLines(src, block)
+		blockEdits := make([]byteEdit, 0)
+		for _, e := range innerEdits {
+			if e.Start >= start && e.End <= end {
+				blockEdits = append(blockEdits, byteEdit{e.Start - start, e.End - start, e.Text})
+			}
+		}
+		mainBlocks = append(mainBlocks, string(applyByteEdits(src[start:end], blockEdits)))
+	}
+	addNewFile(mainFilename, strings.Join(mainBlocks, "\n"))
+
+	if len(variables) > 0 {
+		decls := make([]string, 0, len(variables))
+		for _, v := range variables {
+			ty := referenceType(mod.RefTargets, v.addr)
+			if v.addr.String() == pathModuleAddr.String() {
+				ty = cty.String
+			}
+			decls = append(decls, fmt.Sprintf("variable %q {\n  type = %s\n}\n",
+				v.name, typeexpr.TypeString(ty)))
+		}
+		addNewFile(variablesFilename, strings.Join(decls, "\n"))
+	}
+	if len(outputs) > 0 {
+		decls := make([]string, 0, len(outputs))
+		for _, v := range outputs {
+			decls = append(decls, outputBlock(v.name, generatedOutput{}, v.addr.String()))
+		}
+		addNewFile(outputsFilename, strings.Join(decls, "\n"))
+	}
+
+	// module call replacing the blocks
+	var call strings.Builder
+	fmt.Fprintf(&call, "module %q {\n  source = %q\n", name, "./"+path.Join(modulesDirname, name))
+	if len(variables) > 0 {
+		call.WriteString("\n")
+		width := 0
+		for _, v := range variables {
+			if len(v.name) > width {
+				width = len(v.name)
+			}
+		}
+		for _, v := range variables {
+			fmt.Fprintf(&call, "  %-*s = %s\n", width, v.name, v.addr)
+		}
+	}
+	call.WriteString("}\n")
+
+	movedBlocks := make([]string, 0)
+	for _, block := range blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		addr := blockAddress(block)
+		movedBlocks = append(movedBlocks, fmt.Sprintf("moved {\n  from = %s\n  to   = module.%s.%s\n}\n",
+			addr, name, addr))
+	}
+
+	replacement := call.String()
+	if len(movedBlocks) > 0 {
+		if movedFile, ok := mod.ParsedModuleFiles[ast.ModFilename(movedFilename)]; ok && filename != movedFilename {
+			outerEdits[movedFilename] = append(outerEdits[movedFilename],
+				appendToFile(movedFile.Bytes, strings.Join(movedBlocks, "\n")))
+		} else {
+			replacement += "\n" + strings.Join(movedBlocks, "\n")
+		}
+	}
+
+	blockSpans := make([][2]int, 0, len(blocks))
+	for _, block := range blocks {
+		start, end := blockLines(src, block)
+		// remove empty lines which separated the block
+		for end < len(src) && strings.TrimSpace(string(src[end:lineEnd(src, end)])) == "" {
+			end = lineEnd(src, end)
+		}
+		blockSpans = append(blockSpans, [2]int{start, end})
+	}
+	for i, span := range blockSpans {
+		start, end := span[0], span[1]
+
+		text := ""
+		if i == 0 {
+			text = replacement
+			// separate the replacement from any blocks following the last one
+			if blockSpans[len(blockSpans)-1][1] < len(src) {
+				text += "\n"
+			}
+		}
+		outerEdits[filename] = append(outerEdits[filename], byteEdit{
+			Start: start,
+			End:   end,
+			Text:  text,
+		})
+	}
+
+	for name, fileEdits := range outerEdits {
+		of := mod.ParsedModuleFiles[ast.ModFilename(name)]
+		for _, e := range fileEdits {
+			addByteEdit(edits, filepath.Join(mod.Path, name), of.Bytes, e)
+		}
+	}
+
+	return &ModuleExtraction{
+		Name:     name,
+		Edits:    edits,
+		NewFiles: newFiles,
+	}, true, nil
+}
+
+// extractableBlocks returns resource, data and locals blocks
+// overlapping the range, or containing it if the range is empty
+func extractableBlocks(body *hclsyntax.Body, rng hcl.Range) []*hclsyntax.Block {
+	blocks := make([]*hclsyntax.Block, 0)
+	for _, block := range body.Blocks {
+		switch {
+		case (block.Type == "resource" || block.Type == "data") && len(block.Labels) == 2,
+			block.Type == "locals" && len(block.Labels) == 0:
+		default:
+			continue
+		}
+
+		if rng.Empty() {
+			if block.Range().ContainsOffset(rng.Start.Byte) {
+				blocks = append(blocks, block)
+			}
+			continue
+		}
+		if rangesOverlap(block.Range(), rng) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// extractedModuleName returns name of the first resource
+// or data source, or a generic name if only locals are extracted
+func extractedModuleName(blocks []*hclsyntax.Block) string {
+	for _, block := range blocks {
+		if len(block.Labels) == 2 {
+			return block.Labels[1]
+		}
+	}
+	return "extracted"
+}
+
+// blockLines returns span of whole lines of the block,
+// including comment lines directly above it
+func blockLines(src []byte, block *hclsyntax.Block) (int, int) {
+	start := lineStart(src, block.Range().Start.Byte)
+	for start > 0 {
+		prevStart := lineStart(src, start-1)
+		if !isComment(strings.TrimSpace(string(src[prevStart:start]))) {
+			break
+		}
+		start = prevStart
+	}
+	return start, lineEnd(src, block.Range().End.Byte)
+}
+
+var pathModuleAddr = lang.Address{
+	lang.RootStep{Name: "path"},
+	lang.AttrStep{Name: "module"},
+}
+
+// pathModuleReferences returns ranges of path.module within the blocks
+func pathModuleReferences(blocks []*hclsyntax.Block) []hcl.Range {
+	ranges := make([]hcl.Range, 0)
+	for _, block := range blocks {
+		hclsyntax.VisitAll(block.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok || len(expr.Traversal) < 2 || expr.Traversal.RootName() != "path" {
+				return nil
+			}
+			if attr, ok := expr.Traversal[1].(hcl.TraverseAttr); ok && attr.Name == "module" {
+				ranges = append(ranges, hcl.RangeBetween(expr.Traversal[0].SourceRange(),
+					expr.Traversal[1].SourceRange()))
+			}
+			return nil
+		})
+	}
+	return ranges
+}
+
+// movedDeclaration returns address of the moved declaration
+// which the given address refers to
+func movedDeclaration(moved []lang.Address, addr lang.Address) (lang.Address, bool) {
+	for _, decl := range moved {
+		if addressHasPrefix(addr, decl) {
+			return decl, true
+		}
+	}
+	return nil, false
+}
+
+// referencedValue returns address of the value to pass into the module
+// for the given reference, i.e. a variable or local value, or a single
+// attribute of a resource, data source or module call
+func referencedValue(addr lang.Address) (lang.Address, bool) {
+	if len(addr) < 2 {
+		return nil, false
+	}
+
+	size := 0
+	switch addr[0].String() {
+	case "count", "each", "self", "path", "terraform":
+		// values which are available within the module too
+		// or cannot be passed into it
+		return nil, false
+	case "var", "local":
+		size = 2
+	case "module":
+		size = 3
+	case "data":
+		size = 4
+	default:
+		size = 3
+	}
+
+	for i, step := range addr {
+		if _, ok := stepName(step); !ok {
+			size = i
+			break
+		}
+	}
+	if size > len(addr) {
+		size = len(addr)
+	}
+	if size < 2 {
+		return nil, false
+	}
+	return addr[:size], true
+}
+
+// variableName returns name of a variable or output for the value
+// of the given address, without the root step of the kind of it
+func variableName(addr lang.Address) string {
+	names := make([]string, 0, len(addr))
+	for _, step := range addr {
+		if name, ok := stepName(step); ok {
+			names = append(names, name)
+		}
+	}
+	switch names[0] {
+	case "data":
+		names = names[2:]
+	default:
+		names = names[1:]
+	}
+	return strings.Join(names, "_")
+}
+
+type extractedValue struct {
+	addr lang.Address
+	name string
+}
+
+// addExtractedValue returns value of the given address, adding it
+// with a unique name derived from the given one if it is not added yet
+func addExtractedValue(values *[]extractedValue, addr lang.Address, name string) extractedValue {
+	declared := make(map[string]bool, len(*values))
+	for _, v := range *values {
+		if v.addr.String() == addr.String() {
+			return v
+		}
+		declared[v.name] = true
+	}
+
+	v := extractedValue{
+		addr: addr,
+		name: uniqueName(declared, name),
+	}
+	*values = append(*values, v)
+	sort.SliceStable(*values, func(i, j int) bool {
+		return (*values)[i].name < (*values)[j].name
+	})
+	return v
+}
+
+// referenceType returns type of the reference target of the given
+// address, or cty.DynamicPseudoType if the type is not known
+func referenceType(targets lang.ReferenceTargets, addr lang.Address) cty.Type {
+	for _, target := range targets {
+		if target.Addr.String() == addr.String() &&
+			target.Type != cty.NilType && target.Type != cty.DynamicPseudoType {
+			return target.Type
+		}
+		if ty := referenceType(target.NestedTargets, addr); ty != cty.DynamicPseudoType {
+			return ty
+		}
+	}
+	return cty.DynamicPseudoType
+}
+
+// requiredProviders returns terraform block requiring providers
+// of the blocks which are not implied from the names of resource
+// types, i.e. those which do not belong to the hashicorp namespace
+func requiredProviders(mod module.Module, blocks []*hclsyntax.Block) (string, bool) {
+	names := make([]string, 0)
+	required := make(map[string]bool, 0)
+	for _, block := range blocks {
+		if len(block.Labels) != 2 {
+			continue
+		}
+		localName := strings.SplitN(block.Labels[0], "_", 2)[0]
+		if required[localName] {
+			continue
+		}
+		provider, ok := mod.Meta.ProviderReferences[tfmod.ProviderRef{LocalName: localName}]
+		if !ok || provider.IsDefault() || provider.IsLegacy() {
+			// implied by the resource type
+			continue
+		}
+		required[localName] = true
+		names = append(names, localName)
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("terraform {\n  required_providers {\n")
+	for _, localName := range names {
+		provider := mod.Meta.ProviderReferences[tfmod.ProviderRef{LocalName: localName}]
+		fmt.Fprintf(&b, "    %s = {\n", localName)
+		if constraints, ok := mod.Meta.ProviderRequirements[provider]; ok && len(constraints) > 0 {
+			fmt.Fprintf(&b, "      source  = %q\n", provider.ForDisplay())
+			fmt.Fprintf(&b, "      version = %q\n", constraints.String())
+		} else {
+			fmt.Fprintf(&b, "      source = %q\n", provider.ForDisplay())
+		}
+		b.WriteString("    }\n")
+	}
+	b.WriteString("  }\n}\n")
+	return b.String(), true
+}
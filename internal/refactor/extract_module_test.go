@@ -0,0 +1,464 @@
+package refactor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+func TestExtractModule(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		// references within main.tf which would be decoded
+		// as origins with provider schemas
+		origins          []string
+		start, end       string
+		expectedName     string
+		expectedFiles    map[string]string
+		expectedNewFiles []string
+	}{
+		{
+			"resources with dependencies",
+			map[string]string{
+				"main.tf": `variable "region" {
+  type = string
+}
+
+locals {
+  name = "web-${terraform.workspace}"
+}
+
+# web server
+resource "aws_instance" "web" {
+  ami  = data.aws_ami.app.id
+  tags = { Name = local.name, Region = var.region }
+}
+
+resource "aws_eip" "ip" {
+  instance = aws_instance.web.id
+}
+
+data "aws_ami" "app" {
+  owners = ["self"]
+}
+`,
+				"outputs.tf": `output "ip" {
+  value = aws_instance.web.public_ip
+}
+`,
+			},
+			[]string{"data.aws_ami.app.id", "local.name", "var.region", "aws_instance.web.id"},
+			`resource "aws_instance"`,
+			`instance = `,
+			"web",
+			map[string]string{
+				"main.tf": `variable "region" {
+  type = string
+}
+
+locals {
+  name = "web-${terraform.workspace}"
+}
+
+module "web" {
+  source = "./modules/web"
+
+  app_id = data.aws_ami.app.id
+  name   = local.name
+  region = var.region
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+
+moved {
+  from = aws_eip.ip
+  to   = module.web.aws_eip.ip
+}
+
+data "aws_ami" "app" {
+  owners = ["self"]
+}
+`,
+				"outputs.tf": `output "ip" {
+  value = module.web.web_public_ip
+}
+`,
+				"modules/web/main.tf": `# web server
+resource "aws_instance" "web" {
+  ami  = var.app_id
+  tags = { Name = var.name, Region = var.region }
+}
+
+resource "aws_eip" "ip" {
+  instance = aws_instance.web.id
+}
+`,
+				"modules/web/variables.tf": `variable "app_id" {
+  type = any
+}
+
+variable "name" {
+  type = any
+}
+
+variable "region" {
+  type = string
+}
+`,
+				"modules/web/outputs.tf": `output "web_public_ip" {
+  value = aws_instance.web.public_ip
+}
+`,
+			},
+			[]string{"modules/web/main.tf", "modules/web/variables.tf", "modules/web/outputs.tf"},
+		},
+		{
+			"instances and moved.tf",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  count = 2
+}
+
+output "first_id" {
+  value = aws_instance.web[0].id
+}
+`,
+				"moved.tf": `moved {
+  from = aws_instance.app
+  to   = aws_instance.web
+}
+`,
+			},
+			nil,
+			`count`,
+			``,
+			"web",
+			map[string]string{
+				"main.tf": `module "web" {
+  source = "./modules/web"
+}
+
+output "first_id" {
+  value = module.web.web[0].id
+}
+`,
+				"moved.tf": `moved {
+  from = aws_instance.app
+  to   = aws_instance.web
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+				"modules/web/main.tf": `resource "aws_instance" "web" {
+  count = 2
+}
+`,
+				"modules/web/outputs.tf": `output "web" {
+  value = aws_instance.web
+}
+`,
+			},
+			[]string{"modules/web/main.tf", "modules/web/outputs.tf"},
+		},
+		{
+			"locals only",
+			map[string]string{
+				"main.tf": `module "extracted" {
+  source = "./other"
+}
+
+locals {
+  port = 8080
+}
+
+output "port" {
+  value = local.port
+}
+`,
+			},
+			nil,
+			`port = 8080`,
+			``,
+			"extracted_2",
+			map[string]string{
+				"main.tf": `module "extracted" {
+  source = "./other"
+}
+
+module "extracted_2" {
+  source = "./modules/extracted_2"
+}
+
+output "port" {
+  value = module.extracted_2.port
+}
+`,
+				"modules/extracted_2/main.tf": `locals {
+  port = 8080
+}
+`,
+				"modules/extracted_2/outputs.tf": `output "port" {
+  value = local.port
+}
+`,
+			},
+			[]string{"modules/extracted_2/main.tf", "modules/extracted_2/outputs.tf"},
+		},
+		{
+			"third-party provider",
+			map[string]string{
+				"main.tf": `terraform {
+  required_providers {
+    mycloud = {
+      source  = "example/mycloud"
+      version = "~> 1.0"
+    }
+  }
+}
+
+resource "mycloud_server" "app" {
+  size = "small"
+}
+`,
+			},
+			nil,
+			`size`,
+			``,
+			"app",
+			map[string]string{
+				"main.tf": `terraform {
+  required_providers {
+    mycloud = {
+      source  = "example/mycloud"
+      version = "~> 1.0"
+    }
+  }
+}
+
+module "app" {
+  source = "./modules/app"
+}
+
+moved {
+  from = mycloud_server.app
+  to   = module.app.mycloud_server.app
+}
+`,
+				"modules/app/main.tf": `terraform {
+  required_providers {
+    mycloud = {
+      source  = "example/mycloud"
+      version = "~> 1.0"
+    }
+  }
+}
+
+resource "mycloud_server" "app" {
+  size = "small"
+}
+`,
+			},
+			[]string{"modules/app/main.tf"},
+		},
+		{
+			"path.module",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  user_data = file("${path.module}/init.sh")
+  tags      = { Root = path.root }
+}
+
+data "template_file" "config" {
+  template = templatefile(path.module, {})
+}
+`,
+			},
+			nil,
+			`resource`,
+			`templatefile`,
+			"web",
+			map[string]string{
+				"main.tf": `module "web" {
+  source = "./modules/web"
+
+  parent_module_path = path.module
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+				"modules/web/main.tf": `resource "aws_instance" "web" {
+  user_data = file("${var.parent_module_path}/init.sh")
+  tags      = { Root = path.root }
+}
+
+data "template_file" "config" {
+  template = templatefile(var.parent_module_path, {})
+}
+`,
+				"modules/web/variables.tf": `variable "parent_module_path" {
+  type = string
+}
+`,
+			},
+			[]string{"modules/web/main.tf", "modules/web/variables.tf"},
+		},
+		{
+			"provider configuration",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  provider = aws.west
+}
+`,
+			},
+			nil,
+			`resource`,
+			``,
+			"",
+			nil,
+			nil,
+		},
+		{
+			"no block",
+			map[string]string{
+				"main.tf": `variable "name" {
+}
+`,
+			},
+			nil,
+			`variable`,
+			``,
+			"",
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mm := loadSingleTestModuleManager(t, tc.files)
+
+			origins := make(lang.ReferenceOrigins, 0, len(tc.origins))
+			for _, ref := range tc.origins {
+				origins = append(origins, lang.ReferenceOrigin{
+					Addr:  testAddress(t, ref),
+					Range: textRange(t, tc.files["main.tf"], ref),
+				})
+			}
+			mf := &originsModuleFinder{mm, modPath, origins}
+
+			rng := textRange(t, tc.files["main.tf"], tc.start)
+			if tc.end == "" {
+				rng.End = rng.Start
+			} else {
+				rng.End = textRange(t, tc.files["main.tf"], tc.end).End
+			}
+
+			extraction, ok, err := ExtractModule(mf, modPath, "main.tf", rng, "moved.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no extraction, given: %#v", extraction)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected extraction")
+			}
+			if extraction.Name != tc.expectedName {
+				t.Fatalf("expected name %q, given %q", tc.expectedName, extraction.Name)
+			}
+
+			expectedNewFiles := make([]string, 0, len(tc.expectedNewFiles))
+			for _, name := range tc.expectedNewFiles {
+				expectedNewFiles = append(expectedNewFiles, filepath.FromSlash(name))
+			}
+			expectedFiles := make(map[string]string, len(tc.expectedFiles))
+			for name, content := range tc.expectedFiles {
+				expectedFiles[filepath.FromSlash(name)] = content
+			}
+			assertEditedFiles(t, modPath, tc.files, extraction.Edits, extraction.NewFiles,
+				expectedFiles, expectedNewFiles)
+		})
+	}
+}
+
+func TestExtractModule_movedBlocksFile(t *testing.T) {
+	files := map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+		"moved.tf": ``,
+		"refactoring.tf": `moved {
+  from = aws_instance.app
+  to   = aws_instance.web
+}
+`,
+	}
+	modPath, mm := loadSingleTestModuleManager(t, files)
+	mf := &originsModuleFinder{mm, modPath, lang.ReferenceOrigins{}}
+
+	rng := textRange(t, files["main.tf"], `ami`)
+	rng.End = rng.Start
+	extraction, ok, err := ExtractModule(mf, modPath, "main.tf", rng, "refactoring.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected extraction")
+	}
+
+	expectedFiles := map[string]string{
+		"main.tf": `module "web" {
+  source = "./modules/web"
+}
+`,
+		"refactoring.tf": `moved {
+  from = aws_instance.app
+  to   = aws_instance.web
+}
+
+moved {
+  from = aws_instance.web
+  to   = module.web.aws_instance.web
+}
+`,
+		filepath.FromSlash("modules/web/main.tf"): `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+	}
+	assertEditedFiles(t, modPath, files, extraction.Edits, extraction.NewFiles,
+		expectedFiles, []string{filepath.FromSlash("modules/web/main.tf")})
+}
+
+// originsModuleFinder adds origins to the module at the given path
+type originsModuleFinder struct {
+	module.ModuleFinder
+
+	modPath string
+	origins lang.ReferenceOrigins
+}
+
+func (mf *originsModuleFinder) ModuleByPath(path string) (module.Module, error) {
+	mod, err := mf.ModuleFinder.ModuleByPath(path)
+	if err != nil || path != mf.modPath {
+		return mod, err
+	}
+	mod.RefOrigins = append(mod.RefOrigins, mf.origins...)
+	return mod, nil
+}
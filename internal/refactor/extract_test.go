@@ -0,0 +1,390 @@
+package refactor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+func TestExtract(t *testing.T) {
+	testCases := []struct {
+		name      string
+		files     map[string]string
+		selection string
+		target    ExtractTarget
+		// cursor makes the range empty, placed
+		// at the beginning of the selection
+		cursor        bool
+		expectedName  string
+		expectedFiles map[string]string
+	}{
+		{
+			"whole attribute value into new locals block",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  instance_type = "t2.micro"
+}
+`,
+			},
+			`"t2`,
+			ExtractToLocal,
+			true,
+			"instance_type",
+			map[string]string{
+				"main.tf": `locals {
+  instance_type = "t2.micro"
+}
+
+resource "aws_instance" "app" {
+  instance_type = local.instance_type
+}
+`,
+			},
+		},
+		{
+			"object item into existing locals file",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  tags = {
+    Name = "${var.env}-app"
+  }
+}
+`,
+				"locals.tf": `locals {
+  region = "eu-west-1"
+}
+`,
+			},
+			`"${var.env}-app"`,
+			ExtractToLocal,
+			false,
+			"name",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  tags = {
+    Name = local.name
+  }
+}
+`,
+				"locals.tf": `locals {
+  region = "eu-west-1"
+  name = "${var.env}-app"
+}
+`,
+			},
+		},
+		{
+			"colliding name",
+			map[string]string{
+				"main.tf": `locals {
+  ami = "ami-1"
+}
+
+resource "aws_instance" "app" {
+  ami = "ami-2"
+}
+`,
+			},
+			`"ami-2"`,
+			ExtractToLocal,
+			false,
+			"ami_2",
+			map[string]string{
+				"main.tf": `locals {
+  ami = "ami-1"
+  ami_2 = "ami-2"
+}
+
+resource "aws_instance" "app" {
+  ami = local.ami_2
+}
+`,
+			},
+		},
+		{
+			"constant into existing variables file",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  security_groups = ["a", "b"]
+}
+`,
+				"variables.tf": `variable "env" {
+}
+`,
+			},
+			`["a", "b"]`,
+			ExtractToVariable,
+			false,
+			"security_groups",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  security_groups = var.security_groups
+}
+`,
+				"variables.tf": `variable "env" {
+}
+
+variable "security_groups" {
+  type    = list(string)
+  default = ["a", "b"]
+}
+`,
+			},
+		},
+		{
+			"constant into new variable block",
+			map[string]string{
+				"main.tf": `output "port" {
+  value = 8080
+}
+`,
+			},
+			`8080`,
+			ExtractToVariable,
+			false,
+			"value",
+			map[string]string{
+				"main.tf": `variable "value" {
+  type    = number
+  default = 8080
+}
+
+output "port" {
+  value = var.value
+}
+`,
+			},
+		},
+		{
+			"reference into variable",
+			map[string]string{
+				"main.tf": `output "env" {
+  value = upper(var.env)
+}
+`,
+			},
+			`upper(var.env)`,
+			ExtractToVariable,
+			false,
+			"",
+			nil,
+		},
+		{
+			"reference to each",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  for_each = var.instances
+  ami      = each.value
+}
+`,
+			},
+			`each.value`,
+			ExtractToLocal,
+			false,
+			"",
+			nil,
+		},
+		{
+			"reference to for expression symbol",
+			map[string]string{
+				"main.tf": `output "names" {
+  value = [for n in var.names : upper(n)]
+}
+`,
+			},
+			`upper(n)`,
+			ExtractToLocal,
+			false,
+			"",
+			nil,
+		},
+		{
+			"collection of for expression",
+			map[string]string{
+				"main.tf": `output "names" {
+  value = [for n in var.names : upper(n)]
+}
+`,
+			},
+			`var.names`,
+			ExtractToLocal,
+			false,
+			"",
+			nil,
+		},
+		{
+			"module source",
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+}
+`,
+			},
+			`"./app"`,
+			ExtractToLocal,
+			false,
+			"",
+			nil,
+		},
+		{
+			"variable default",
+			map[string]string{
+				"main.tf": `variable "env" {
+  default = "dev"
+}
+`,
+			},
+			`"dev"`,
+			ExtractToLocal,
+			false,
+			"",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mod := loadSingleTestModule(t, tc.files)
+
+			rng := textRange(t, tc.files["main.tf"], tc.selection)
+			if tc.cursor {
+				rng.End = rng.Start
+			}
+
+			extraction, ok := Extract(mod, "main.tf", rng, tc.target)
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no extraction, got %#v", extraction)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected extraction")
+			}
+
+			if extraction.Name != tc.expectedName {
+				t.Fatalf("expected name %q, given %q", tc.expectedName, extraction.Name)
+			}
+
+			files := make(map[string]string, len(extraction.Files))
+			for path, fc := range extraction.Files {
+				rel, err := filepath.Rel(modPath, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[rel] = string(fc.After)
+			}
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+		})
+	}
+}
+
+func TestVariableType(t *testing.T) {
+	testCases := []struct {
+		src          string
+		expectedType string
+	}{
+		{`"foo"`, "string"},
+		{`["a", "b"]`, "list(string)"},
+		{`["a", 1]`, "any"},
+		{`{ a = 1, b = 2 }`, "map(number)"},
+		{`{ a = ["x"], b = [] }`, "any"},
+		{`[]`, "list(any)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			expr, diags := hclsyntax.ParseExpression([]byte(tc.src), "test.tf", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			val, diags := expr.Value(nil)
+			if diags.HasErrors() {
+				t.Fatal(diags)
+			}
+			ty := typeexpr.TypeString(variableType(val))
+			if ty != tc.expectedType {
+				t.Fatalf("expected %q, given %q", tc.expectedType, ty)
+			}
+		})
+	}
+}
+
+// textRange returns range of the first occurrence of text in src
+func textRange(t *testing.T, src, text string) hcl.Range {
+	offset := strings.Index(src, text)
+	if offset < 0 {
+		t.Fatalf("%q not found", text)
+	}
+	return hcl.Range{
+		Filename: "main.tf",
+		Start:    posAtOffset(src, offset),
+		End:      posAtOffset(src, offset+len(text)),
+	}
+}
+
+func posAtOffset(src string, offset int) hcl.Pos {
+	before := src[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := offset - strings.LastIndex(before, "\n")
+	return hcl.Pos{Line: line, Column: column, Byte: offset}
+}
+
+func loadSingleTestModule(t *testing.T, files map[string]string) (string, module.Module) {
+	modPath, mm := loadSingleTestModuleManager(t, files)
+
+	mod, err := mm.ModuleByPath(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return modPath, mod
+}
+
+func loadSingleTestModuleManager(t *testing.T, files map[string]string) (string, module.ModuleManager) {
+	modPath := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(modPath, name), []byte(content), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mm := module.NewSyncModuleManager(context.Background(), filesystem.NewFilesystem(), ss.Modules, ss.ProviderSchemas)
+	_, err = mm.AddModule(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opTypes := []op.OpType{
+		op.OpTypeParseModuleConfiguration,
+		op.OpTypeParseVariables,
+		op.OpTypeLoadModuleMetadata,
+		op.OpTypeDecodeReferenceTargets,
+		op.OpTypeDecodeReferenceOrigins,
+	}
+	for _, opType := range opTypes {
+		err := mm.EnqueueModuleOpWait(modPath, opType)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return modPath, mm
+}
@@ -0,0 +1,444 @@
+package refactor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// moved blocks were introduced in Terraform 1.1
+var v1_1 = version.Must(version.NewVersion("1.1.0"))
+
+// ForEachConversion represents changes replacing count
+// of a resource or module block with for_each
+type ForEachConversion struct {
+	// Address of the converted resource or module call, e.g. aws_instance.app
+	Address string
+
+	// Files are changes of all affected files indexed by absolute path
+	Files map[string]FileChange
+}
+
+// ConvertCountToForEach calculates changes replacing count of the resource
+// or module block at pos with for_each, along with moved blocks which map
+// each instance address to its new key and updated references.
+// moved blocks are appended to movedFilename if it exists.
+//
+// False is returned if the number of instances cannot be determined
+// from defaults of variables and constant local values,
+// as the moved blocks could not be generated in such case.
+//
+// count is converted into for_each over a set if it is the length of
+// a list of unique strings only referenced via count.index and into
+// for_each over a map indexed by the former count.index otherwise.
+func ConvertCountToForEach(mod module.Module, filename string, pos hcl.Pos, movedFilename string) (*ForEachConversion, bool) {
+	if mod.TerraformVersion != nil && mod.TerraformVersion.LessThan(v1_1) {
+		return nil, false
+	}
+
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+
+	block, addr, ok := countedBlockAtPos(body, pos)
+	if !ok {
+		return nil, false
+	}
+	countAttr := block.Body.Attributes["count"]
+
+	src := f.Bytes
+	conv, ok := forEachFromCount(src, countAttr.Expr, evalContext(mod))
+	if !ok {
+		return nil, false
+	}
+	conv.collectIndexReferences(block.Body)
+
+	keys := conv.keys()
+
+	edits := map[string][]byteEdit{
+		filename: {
+			{
+				Start: countAttr.NameRange.Start.Byte,
+				End:   countAttr.Expr.Range().End.Byte,
+				Text:  "for_each = " + conv.forEachExpr(),
+			},
+		},
+	}
+	for _, rng := range conv.elemRefs {
+		edits[filename] = append(edits[filename], byteEdit{
+			Start: rng.Start.Byte,
+			End:   rng.End.Byte,
+			Text:  "each.value",
+		})
+	}
+	for _, rng := range conv.indexRefs {
+		edits[filename] = append(edits[filename], byteEdit{
+			Start: rng.Start.Byte,
+			End:   rng.End.Byte,
+			Text:  conv.indexRef(),
+		})
+	}
+
+	for name, fileEdits := range instanceReferenceEdits(mod, addr, keys, conv.instancesExpr) {
+		edits[name] = append(edits[name], fileEdits...)
+	}
+
+	var moved strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			moved.WriteString("\n")
+		}
+		fmt.Fprintf(&moved, "moved {\n  from = %s[%d]\n  to   = %s[%s]\n}\n",
+			addr, i, addr, quotedKey(key))
+	}
+	if len(keys) > 0 {
+		movedFile, movedEdit := movedBlocksEdit(mod, filename, block, moved.String(), movedFilename)
+		edits[movedFile] = append(edits[movedFile], movedEdit)
+	}
+
+	return &ForEachConversion{
+		Address: addr.String(),
+		Files:   fileChanges(mod, edits),
+	}, true
+}
+
+// countedBlockAtPos returns resource or module block at pos
+// which has count and no for_each, along with its address
+func countedBlockAtPos(body *hclsyntax.Body, pos hcl.Pos) (*hclsyntax.Block, lang.Address, bool) {
+	for _, block := range body.Blocks {
+		if !block.Range().ContainsOffset(pos.Byte) {
+			continue
+		}
+
+		var addr lang.Address
+		switch {
+		case block.Type == "resource" && len(block.Labels) == 2:
+			addr = lang.Address{
+				lang.RootStep{Name: block.Labels[0]},
+				lang.AttrStep{Name: block.Labels[1]},
+			}
+		case block.Type == "module" && len(block.Labels) == 1:
+			addr = lang.Address{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: block.Labels[0]},
+			}
+		default:
+			// data sources cannot be moved
+			return nil, nil, false
+		}
+
+		if _, ok := block.Body.Attributes["count"]; !ok {
+			return nil, nil, false
+		}
+		if _, ok := block.Body.Attributes["for_each"]; ok {
+			return nil, nil, false
+		}
+		return block, addr, true
+	}
+	return nil, nil, false
+}
+
+type countConversion struct {
+	src   []byte
+	count int
+
+	// collection is the argument of length() used as count, if any
+	collection     hclsyntax.Expression
+	collectionText string
+	collectionVal  cty.Value
+
+	countText string
+
+	// elemRefs are ranges of collection[count.index] expressions
+	elemRefs []hcl.Range
+	// indexRefs are ranges of any other count.index references
+	indexRefs []hcl.Range
+}
+
+func forEachFromCount(src []byte, expr hclsyntax.Expression, ctx *hcl.EvalContext) (*countConversion, bool) {
+	conv := &countConversion{
+		src:       src,
+		countText: string(expr.Range().SliceBytes(src)),
+	}
+
+	if call, ok := expr.(*hclsyntax.FunctionCallExpr); ok && call.Name == "length" && len(call.Args) == 1 {
+		val, diags := call.Args[0].Value(ctx)
+		if diags.HasErrors() || !val.IsWhollyKnown() || val.IsNull() || !val.CanIterateElements() {
+			return nil, false
+		}
+		conv.collection = call.Args[0]
+		conv.collectionText = string(call.Args[0].Range().SliceBytes(src))
+		conv.collectionVal = val
+		conv.count = val.LengthInt()
+		return conv, true
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() || !val.IsKnown() || val.IsNull() || val.Type() != cty.Number {
+		return nil, false
+	}
+	count, accuracy := val.AsBigFloat().Int64()
+	if accuracy != 0 || count < 0 {
+		return nil, false
+	}
+	conv.count = int(count)
+	return conv, true
+}
+
+// collectIndexReferences finds all references to count.index within the body
+func (c *countConversion) collectIndexReferences(body *hclsyntax.Body) {
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch expr := node.(type) {
+		case *hclsyntax.IndexExpr:
+			if c.collection == nil || !isCountIndex(expr.Key) {
+				return nil
+			}
+			text := string(expr.Collection.Range().SliceBytes(c.src))
+			if text == c.collectionText {
+				c.elemRefs = append(c.elemRefs, expr.Range())
+			}
+		case *hclsyntax.ScopeTraversalExpr:
+			if !isCountIndex(expr) {
+				return nil
+			}
+			for _, rng := range c.elemRefs {
+				if rangeContainsRange(rng, expr.Range()) {
+					return nil
+				}
+			}
+			c.indexRefs = append(c.indexRefs, expr.Range())
+		}
+		return nil
+	})
+}
+
+func isCountIndex(expr hclsyntax.Expression) bool {
+	st, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(st.Traversal) != 2 || st.Traversal.RootName() != "count" {
+		return false
+	}
+	attr, ok := st.Traversal[1].(hcl.TraverseAttr)
+	return ok && attr.Name == "index"
+}
+
+// overSet reports whether for_each can iterate over a set
+// of the collection elements, which is only possible if
+// these are unique strings and the index is not referenced
+func (c *countConversion) overSet() bool {
+	if c.collection == nil || len(c.indexRefs) > 0 {
+		return false
+	}
+	ty := c.collectionVal.Type()
+	if !ty.IsListType() && !ty.IsTupleType() {
+		return false
+	}
+
+	seen := make(map[string]bool, 0)
+	for it := c.collectionVal.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || v.Type() != cty.String || seen[v.AsString()] {
+			return false
+		}
+		seen[v.AsString()] = true
+	}
+	return true
+}
+
+// keys returns instance keys in the order of former indexes
+func (c *countConversion) keys() []string {
+	keys := make([]string, 0, c.count)
+	if c.overSet() {
+		for it := c.collectionVal.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			keys = append(keys, v.AsString())
+		}
+		return keys
+	}
+
+	for i := 0; i < c.count; i++ {
+		keys = append(keys, fmt.Sprintf("%d", i))
+	}
+	return keys
+}
+
+func (c *countConversion) forEachExpr() string {
+	switch {
+	case c.overSet():
+		return fmt.Sprintf("toset(%s)", c.collectionText)
+	case c.collection != nil:
+		return fmt.Sprintf("{ for i, v in %s : i => v }", c.collectionText)
+	}
+	return fmt.Sprintf("{ for i in range(%s) : i => i }", c.countText)
+}
+
+// instancesExpr returns list of all instances of the given address
+// in the order of former indexes, which is not the lexical order
+// of keys, as values() would return
+func (c *countConversion) instancesExpr(addr string) string {
+	if c.overSet() {
+		return fmt.Sprintf("[for k in %s : %s[k]]", c.collectionText, addr)
+	}
+	return fmt.Sprintf("[for i in range(length(%s)) : %s[i]]", addr, addr)
+}
+
+// indexRef returns reference replacing count.index
+func (c *countConversion) indexRef() string {
+	if c.collection != nil {
+		return "each.key"
+	}
+	return "each.value"
+}
+
+// evalContext returns context for evaluation of expressions
+// which only depend on defaults of variables and constant local values
+func evalContext(mod module.Module) *hcl.EvalContext {
+	vars := make(map[string]cty.Value, 0)
+	for name, v := range mod.Meta.Variables {
+		if v.DefaultValue == cty.NilVal {
+			ty := v.Type
+			if ty == cty.NilType {
+				ty = cty.DynamicPseudoType
+			}
+			vars[name] = cty.UnknownVal(ty)
+			continue
+		}
+		vars[name] = v.DefaultValue
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"var":   cty.ObjectVal(vars),
+			"local": cty.EmptyObjectVal,
+		},
+	}
+
+	attrs := make(map[string]*hclsyntax.Attribute, 0)
+	for _, f := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+		for _, block := range f.Body.Blocks {
+			if block.Type != "locals" {
+				continue
+			}
+			for name, attr := range block.Body.Attributes {
+				attrs[name] = attr
+			}
+		}
+	}
+
+	// local values may reference each other, so these are
+	// evaluated repeatedly until there are no more known values
+	locals := make(map[string]cty.Value, 0)
+	for len(attrs) > 0 {
+		evaluated := 0
+		for name, attr := range attrs {
+			val, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() {
+				continue
+			}
+			locals[name] = val
+			delete(attrs, name)
+			evaluated++
+		}
+		if evaluated == 0 {
+			break
+		}
+		ctx.Variables["local"] = cty.ObjectVal(locals)
+	}
+
+	return ctx
+}
+
+// instanceReferenceEdits returns edits of references to instances
+// of the resource or module call (indexed by file name),
+// replacing indexes with keys and references to all instances
+// with the list returned by instancesExpr
+func instanceReferenceEdits(mod module.Module, addr lang.Address, keys []string,
+	instancesExpr func(addr string) string) map[string][]byteEdit {
+	edits := make(map[string][]byteEdit, 0)
+	files := mod.ParsedModuleFiles.AsMap()
+	seen := make(map[hcl.Range]bool, 0)
+
+	for _, origin := range mod.RefOrigins {
+		if !addressHasPrefix(origin.Addr, addr) || seen[origin.Range] {
+			continue
+		}
+		seen[origin.Range] = true
+
+		f, ok := files[origin.Range.Filename]
+		if !ok {
+			continue
+		}
+		if _, ok := f.Body.(*hclsyntax.Body); !ok {
+			continue
+		}
+
+		traversal, diags := hclsyntax.ParseTraversalAbs(origin.Range.SliceBytes(f.Bytes),
+			origin.Range.Filename, origin.Range.Start)
+		if diags.HasErrors() || len(traversal) < len(addr) {
+			continue
+		}
+
+		name := origin.Range.Filename
+		if len(traversal) == len(addr) {
+			edits[name] = append(edits[name], byteEdit{
+				Start: origin.Range.Start.Byte,
+				End:   origin.Range.End.Byte,
+				Text:  instancesExpr(string(origin.Range.SliceBytes(f.Bytes))),
+			})
+			continue
+		}
+
+		index, ok := traversal[len(addr)].(hcl.TraverseIndex)
+		if !ok || index.Key.Type() != cty.Number {
+			continue
+		}
+		i, accuracy := index.Key.AsBigFloat().Int64()
+		if accuracy != 0 || i < 0 || int(i) >= len(keys) {
+			continue
+		}
+		edits[name] = append(edits[name], byteEdit{
+			Start: index.SrcRange.Start.Byte,
+			End:   index.SrcRange.End.Byte,
+			Text:  fmt.Sprintf("[%s]", quotedKey(keys[i])),
+		})
+	}
+
+	return edits
+}
+
+// movedBlocksEdit returns edit which appends the moved blocks
+// to movedFilename if it exists, or inserts them after the block
+func movedBlocksEdit(mod module.Module, filename string, block *hclsyntax.Block, moved, movedFilename string) (string, byteEdit) {
+	if f, ok := mod.ParsedModuleFiles[ast.ModFilename(movedFilename)]; ok {
+		return movedFilename, appendToFile(f.Bytes, moved)
+	}
+
+	src := mod.ParsedModuleFiles[ast.ModFilename(filename)].Bytes
+	offset := lineEnd(src, block.CloseBraceRange.End.Byte)
+	text := "\n" + moved
+	if offset == len(src) && !strings.HasSuffix(string(src), "\n") {
+		text = "\n" + text
+	}
+
+	return filename, byteEdit{
+		Start: offset,
+		End:   offset,
+		Text:  text,
+	}
+}
+
+func quotedKey(key string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(key)).Bytes())
+}
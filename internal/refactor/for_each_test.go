@@ -0,0 +1,334 @@
+package refactor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConvertCountToForEach(t *testing.T) {
+	testCases := []struct {
+		name            string
+		files           map[string]string
+		cursor          string
+		expectedAddress string
+		expectedFiles   map[string]string
+	}{
+		{
+			"list of unique strings",
+			map[string]string{
+				"main.tf": `variable "names" {
+  default = ["blue", "green"]
+}
+
+resource "aws_instance" "app" {
+  count = length(var.names)
+  tags = {
+    Name = "app-${var.names[count.index]}"
+  }
+}
+
+output "first_id" {
+  value = aws_instance.app[0].id
+}
+
+output "ids" {
+  value = aws_instance.app[*].id
+}
+`,
+			},
+			`resource "aws_instance"`,
+			"aws_instance.app",
+			map[string]string{
+				"main.tf": `variable "names" {
+  default = ["blue", "green"]
+}
+
+resource "aws_instance" "app" {
+  for_each = toset(var.names)
+  tags = {
+    Name = "app-${each.value}"
+  }
+}
+
+moved {
+  from = aws_instance.app[0]
+  to   = aws_instance.app["blue"]
+}
+
+moved {
+  from = aws_instance.app[1]
+  to   = aws_instance.app["green"]
+}
+
+output "first_id" {
+  value = aws_instance.app["blue"].id
+}
+
+output "ids" {
+  value = [for k in var.names : aws_instance.app[k]][*].id
+}
+`,
+			},
+		},
+		{
+			"referenced index",
+			map[string]string{
+				"main.tf": `locals {
+  names = ["blue", "green"]
+}
+
+resource "aws_instance" "app" {
+  count = length(local.names)
+  tags = {
+    Name  = local.names[count.index]
+    Index = count.index
+  }
+}
+`,
+			},
+			`count = length`,
+			"aws_instance.app",
+			map[string]string{
+				"main.tf": `locals {
+  names = ["blue", "green"]
+}
+
+resource "aws_instance" "app" {
+  for_each = { for i, v in local.names : i => v }
+  tags = {
+    Name  = each.value
+    Index = each.key
+  }
+}
+
+moved {
+  from = aws_instance.app[0]
+  to   = aws_instance.app["0"]
+}
+
+moved {
+  from = aws_instance.app[1]
+  to   = aws_instance.app["1"]
+}
+`,
+			},
+		},
+		{
+			"number of module instances into moved.tf",
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+  count  = 2
+  name   = "app-${count.index}"
+}`,
+				"moved.tf": `moved {
+  from = module.web
+  to   = module.app
+}
+`,
+			},
+			`module "app"`,
+			"module.app",
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+  for_each = { for i in range(2) : i => i }
+  name   = "app-${each.value}"
+}`,
+				"moved.tf": `moved {
+  from = module.web
+  to   = module.app
+}
+
+moved {
+  from = module.app[0]
+  to   = module.app["0"]
+}
+
+moved {
+  from = module.app[1]
+  to   = module.app["1"]
+}
+`,
+			},
+		},
+		{
+			"unknown count",
+			map[string]string{
+				"main.tf": `variable "names" {
+  type = list(string)
+}
+
+resource "aws_instance" "app" {
+  count = length(var.names)
+}
+`,
+			},
+			`count = length`,
+			"",
+			nil,
+		},
+		{
+			"data source",
+			map[string]string{
+				"main.tf": `data "aws_ami" "app" {
+  count = 2
+}
+`,
+			},
+			`count = 2`,
+			"",
+			nil,
+		},
+		{
+			"block without count",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  ami = "ami-1"
+}
+`,
+			},
+			`ami = `,
+			"",
+			nil,
+		},
+		{
+			"more than 10 instances",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  count = 11
+}
+
+output "tenth_id" {
+  value = element(aws_instance.web[*].id, 9)
+}
+
+output "last_id" {
+  value = aws_instance.web[10].id
+}
+`,
+			},
+			`count`,
+			"aws_instance.web",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  for_each = { for i in range(11) : i => i }
+}
+` + testMovedBlocks("aws_instance.web", 11) + `
+output "tenth_id" {
+  value = element([for i in range(length(aws_instance.web)) : aws_instance.web[i]][*].id, 9)
+}
+
+output "last_id" {
+  value = aws_instance.web["10"].id
+}
+`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mod := loadSingleTestModule(t, tc.files)
+
+			offset := strings.Index(tc.files["main.tf"], tc.cursor)
+			if offset < 0 {
+				t.Fatalf("%q not found", tc.cursor)
+			}
+			pos := posAtOffset(tc.files["main.tf"], offset)
+
+			conv, ok := ConvertCountToForEach(mod, "main.tf", pos, "moved.tf")
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no conversion, got %#v", conv)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected conversion")
+			}
+
+			if conv.Address != tc.expectedAddress {
+				t.Fatalf("expected address %q, given %q", tc.expectedAddress, conv.Address)
+			}
+
+			files := make(map[string]string, len(conv.Files))
+			for path, fc := range conv.Files {
+				rel, err := filepath.Rel(modPath, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[rel] = string(fc.After)
+			}
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertCountToForEach_movedBlocksFile(t *testing.T) {
+	files := map[string]string{
+		"main.tf": `module "app" {
+  source = "./app"
+  count  = 1
+}
+`,
+		"moved.tf": ``,
+		"refactoring.tf": `moved {
+  from = module.web
+  to   = module.app
+}
+`,
+	}
+	modPath, mod := loadSingleTestModule(t, files)
+
+	pos := posAtOffset(files["main.tf"], strings.Index(files["main.tf"], `module "app"`))
+	conv, ok := ConvertCountToForEach(mod, "main.tf", pos, "refactoring.tf")
+	if !ok {
+		t.Fatal("expected conversion")
+	}
+
+	changedFiles := make(map[string]string, len(conv.Files))
+	for path, fc := range conv.Files {
+		rel, err := filepath.Rel(modPath, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		changedFiles[rel] = string(fc.After)
+	}
+	expectedFiles := map[string]string{
+		"main.tf": `module "app" {
+  source = "./app"
+  for_each = { for i in range(1) : i => i }
+}
+`,
+		"refactoring.tf": `moved {
+  from = module.web
+  to   = module.app
+}
+
+moved {
+  from = module.app[0]
+  to   = module.app["0"]
+}
+`,
+	}
+	if diff := cmp.Diff(expectedFiles, changedFiles); diff != "" {
+		t.Fatalf("unexpected files: %s", diff)
+	}
+}
+
+// testMovedBlocks returns moved blocks from indexes
+// of count to the same keys of for_each
+func testMovedBlocks(addr string, count int) string {
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&sb, "\nmoved {\n  from = %s[%d]\n  to   = %s[\"%d\"]\n}\n", addr, i, addr, i)
+	}
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+package refactor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// PathRename represents a rename (move) of a file or directory
+type PathRename struct {
+	OldPath string
+	NewPath string
+}
+
+// Apply returns the path after the rename
+// if the path is the renamed one or is nested under it
+func (r PathRename) Apply(p string) (string, bool) {
+	rel, err := filepath.Rel(r.OldPath, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p, false
+	}
+	if rel == "." {
+		return r.NewPath, true
+	}
+	return filepath.Join(r.NewPath, rel), true
+}
+
+func applyRenames(renames []PathRename, p string) string {
+	for _, r := range renames {
+		if newPath, ok := r.Apply(p); ok {
+			return newPath
+		}
+	}
+	return p
+}
+
+// UpdateModuleSources returns edits of source attributes of all
+// local module calls which would be broken by the given renames,
+// i.e. where either the called or the calling module is moved.
+//
+// Edits target files at their paths before the renames.
+func UpdateModuleSources(mf module.ModuleFinder, renames []PathRename) (Edits, error) {
+	edits := make(Edits, 0)
+
+	mods, err := mf.ListModules()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mod := range mods {
+		newCallerPath := applyRenames(renames, mod.Path)
+
+		for _, sf := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+			for _, block := range sf.Body.Blocks {
+				if block.Type != "module" || len(block.Labels) != 1 {
+					continue
+				}
+
+				attr, ok := block.Body.Attributes["source"]
+				if !ok {
+					continue
+				}
+				source := sourceAddr(block.Body)
+				if !isLocalSourceAddr(source) {
+					continue
+				}
+				tpl, ok := attr.Expr.(*hclsyntax.TemplateExpr)
+				if !ok || !tpl.IsStringLiteral() {
+					continue
+				}
+
+				calledPath := filepath.Join(mod.Path, filepath.FromSlash(source))
+				newCalledPath := applyRenames(renames, calledPath)
+				if newCallerPath == mod.Path && newCalledPath == calledPath {
+					continue
+				}
+
+				newSource, ok := localSourceAddr(newCallerPath, newCalledPath)
+				if !ok || newSource == source {
+					continue
+				}
+
+				edits.Add(filepath.Join(mod.Path, sf.Name),
+					labelNameRange(sf.Bytes, tpl.SrcRange), newSource)
+			}
+		}
+	}
+
+	return edits, nil
+}
+
+// isLocalSourceAddr reports whether the module source address
+// refers to a local directory, using the same rule as Terraform
+func isLocalSourceAddr(addr string) bool {
+	return strings.HasPrefix(addr, "./") || strings.HasPrefix(addr, "../")
+}
+
+// localSourceAddr returns local source address of the called module
+// relative to the caller, always using forward slashes
+func localSourceAddr(callerPath, calledPath string) (string, bool) {
+	rel, err := filepath.Rel(callerPath, calledPath)
+	if err != nil || rel == "." {
+		return "", false
+	}
+	addr := filepath.ToSlash(rel)
+	if addr == ".." || strings.HasPrefix(addr, "../") {
+		return addr, true
+	}
+	return "./" + addr, true
+}
@@ -0,0 +1,110 @@
+package refactor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestUpdateModuleSources(t *testing.T) {
+	rootDir, mm := loadNestedTestModules(t)
+	appDir := filepath.Join(rootDir, "app")
+	dbDir := filepath.Join(rootDir, "db")
+
+	appSourceRange := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 13, Byte: 27},
+		End:      hcl.Pos{Line: 2, Column: 18, Byte: 32},
+	}
+	dbSourceRange := hcl.Range{
+		Filename: "main.tf",
+		Start:    hcl.Pos{Line: 2, Column: 13, Byte: 26},
+		End:      hcl.Pos{Line: 2, Column: 18, Byte: 31},
+	}
+
+	testCases := []struct {
+		name          string
+		renames       []PathRename
+		expectedEdits Edits
+	}{
+		{
+			"called module renamed",
+			[]PathRename{
+				{OldPath: dbDir, NewPath: filepath.Join(rootDir, "database")},
+			},
+			Edits{
+				filepath.Join(appDir, "main.tf"): {
+					{Range: dbSourceRange, NewText: "../database"},
+				},
+			},
+		},
+		{
+			"calling module moved",
+			[]PathRename{
+				{OldPath: appDir, NewPath: filepath.Join(rootDir, "modules", "app")},
+			},
+			Edits{
+				filepath.Join(rootDir, "main.tf"): {
+					{Range: appSourceRange, NewText: "./modules/app"},
+				},
+				filepath.Join(appDir, "main.tf"): {
+					{Range: dbSourceRange, NewText: "../../db"},
+				},
+			},
+		},
+		{
+			"whole tree moved",
+			[]PathRename{
+				{OldPath: rootDir, NewPath: rootDir + "-moved"},
+			},
+			Edits{},
+		},
+		{
+			"unrelated file renamed",
+			[]PathRename{
+				{OldPath: filepath.Join(dbDir, "main.tf"), NewPath: filepath.Join(dbDir, "db.tf")},
+			},
+			Edits{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits, err := UpdateModuleSources(mm, tc.renames)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
+
+func TestPathRename_Apply(t *testing.T) {
+	r := PathRename{
+		OldPath: filepath.Join("root", "modules", "network"),
+		NewPath: filepath.Join("root", "modules", "net"),
+	}
+
+	testCases := []struct {
+		path         string
+		expectedPath string
+		expectedOk   bool
+	}{
+		{filepath.Join("root", "modules", "network"), filepath.Join("root", "modules", "net"), true},
+		{filepath.Join("root", "modules", "network", "sub"), filepath.Join("root", "modules", "net", "sub"), true},
+		{filepath.Join("root", "modules", "network2"), filepath.Join("root", "modules", "network2"), false},
+		{filepath.Join("root", "modules"), filepath.Join("root", "modules"), false},
+	}
+
+	for _, tc := range testCases {
+		path, ok := r.Apply(tc.path)
+		if ok != tc.expectedOk || path != tc.expectedPath {
+			t.Errorf("%q: expected (%q, %t), given (%q, %t)",
+				tc.path, tc.expectedPath, tc.expectedOk, path, ok)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package refactor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// MovedBlocks calculates edits recording the rename of a resource or
+// module call in a moved block, so that Terraform moves any existing
+// objects to the new address instead of replacing them.
+//
+// The block is appended to the file of the given name within the module.
+// If such file does not exist, its path is returned as a file to be created
+// first, or the block is appended to the file declaring the symbol
+// if files cannot be created.
+//
+// Existing moved blocks which lead to the old address are updated
+// to lead to the new one, keeping any chains of moves valid.
+//
+// No edits are returned for Terraform versions older than 1.1,
+// which do not support moved blocks.
+func MovedBlocks(mf module.ModuleFinder, sym *Symbol, newName, filename string, canCreateFile bool) (Edits, []string, error) {
+	edits := make(Edits, 0)
+	newFiles := make([]string, 0)
+
+	if sym.Kind != ResourceSymbol && sym.Kind != ModuleCallSymbol {
+		return edits, newFiles, nil
+	}
+	if newName == sym.Name {
+		return edits, newFiles, nil
+	}
+
+	mod, err := mf.ModuleByPath(sym.ModulePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mod.TerraformVersion != nil && mod.TerraformVersion.LessThan(v1_1) {
+		return edits, newFiles, nil
+	}
+
+	oldAddr := sym.Address()
+	newSym := *sym
+	newSym.Name = newName
+	newAddr := newSym.Address()
+
+	for _, f := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+		for _, block := range f.Body.Blocks {
+			if block.Type != "moved" {
+				continue
+			}
+			attr, ok := block.Body.Attributes["to"]
+			if !ok || !traversalHasPrefix(attr.Expr, oldAddr) {
+				continue
+			}
+			ranges, ok := traversalStepRanges(f.Bytes, attr.Expr.Range())
+			if !ok || len(ranges) < len(oldAddr) {
+				continue
+			}
+			edits.Add(filepath.Join(mod.Path, f.Name), ranges[len(oldAddr)-1], newName)
+		}
+	}
+
+	moved := fmt.Sprintf("moved {\n  from = %s\n  to   = %s\n}\n", oldAddr, newAddr)
+
+	if _, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]; ok || canCreateFile {
+		movedEdits, newFiles := appendToModuleFile(mod, filename, moved)
+		for path, fileEdits := range movedEdits {
+			for _, e := range fileEdits {
+				edits.Add(path, e.Range, e.NewText)
+			}
+		}
+		return edits, newFiles, nil
+	}
+
+	decls, err := declarationRanges(mf, sym)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(decls) == 0 {
+		return edits, newFiles, nil
+	}
+	declFile := decls[0].Filename
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(declFile)]
+	if !ok {
+		return edits, newFiles, nil
+	}
+	addByteEdit(edits, filepath.Join(mod.Path, declFile), f.Bytes, appendToFile(f.Bytes, moved))
+
+	return edits, newFiles, nil
+}
+
+// traversalHasPrefix reports whether the expression is a traversal
+// starting with names of the given address, ignoring any indexes
+func traversalHasPrefix(expr hcl.Expression, prefix lang.Address) bool {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() {
+		return false
+	}
+
+	names := make([]string, 0, len(traversal))
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, s.Name)
+		case hcl.TraverseAttr:
+			names = append(names, s.Name)
+		}
+	}
+
+	if len(names) < len(prefix) {
+		return false
+	}
+	for i, step := range prefix {
+		name, ok := stepName(step)
+		if !ok || names[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+func addByteEdit(edits Edits, path string, src []byte, e byteEdit) {
+	edits.Add(path, hcl.Range{
+		Filename: filepath.Base(path),
+		Start:    posAtByte(src, e.Start),
+		End:      posAtByte(src, e.End),
+	}, e.Text)
+}
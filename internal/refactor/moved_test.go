@@ -0,0 +1,158 @@
+package refactor
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+)
+
+func TestMovedBlocks(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		sym              Symbol
+		newName          string
+		canCreateFile    bool
+		expectedFiles    map[string]string
+		expectedNewFiles []string
+	}{
+		{
+			"resource with existing moves",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+}
+`,
+				"moved.tf": `moved {
+  from = aws_instance.old
+  to   = aws_instance.web
+}
+`,
+			},
+			Symbol{Kind: ResourceSymbol, Type: "aws_instance", Name: "web"},
+			"frontend",
+			true,
+			map[string]string{
+				"moved.tf": `moved {
+  from = aws_instance.old
+  to   = aws_instance.frontend
+}
+
+moved {
+  from = aws_instance.web
+  to   = aws_instance.frontend
+}
+`,
+			},
+			[]string{},
+		},
+		{
+			"module call into new file",
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+}
+`,
+			},
+			Symbol{Kind: ModuleCallSymbol, Name: "app"},
+			"web",
+			true,
+			map[string]string{
+				"moved.tf": `moved {
+  from = module.app
+  to   = module.web
+}
+`,
+			},
+			[]string{"moved.tf"},
+		},
+		{
+			"module call without file creation",
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+}`,
+			},
+			Symbol{Kind: ModuleCallSymbol, Name: "app"},
+			"web",
+			false,
+			map[string]string{
+				"main.tf": `module "app" {
+  source = "./app"
+}
+
+moved {
+  from = module.app
+  to   = module.web
+}
+`,
+			},
+			[]string{},
+		},
+		{
+			"variable",
+			map[string]string{
+				"main.tf": `variable "app" {
+}
+`,
+			},
+			Symbol{Kind: VariableSymbol, Name: "app"},
+			"web",
+			true,
+			map[string]string{},
+			[]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mm := loadSingleTestModuleManager(t, tc.files)
+			sym := tc.sym
+			sym.ModulePath = modPath
+
+			edits, newFiles, err := MovedBlocks(mm, &sym, tc.newName, "moved.tf", tc.canCreateFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			files := make(map[string]string, len(edits))
+			for path, fileEdits := range edits {
+				rel, err := filepath.Rel(modPath, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				files[rel] = applyTextEdits(tc.files[rel], fileEdits)
+			}
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+
+			relNewFiles := make([]string, 0, len(newFiles))
+			for _, path := range newFiles {
+				rel, err := filepath.Rel(modPath, path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				relNewFiles = append(relNewFiles, rel)
+			}
+			if diff := cmp.Diff(tc.expectedNewFiles, relNewFiles); diff != "" {
+				t.Fatalf("unexpected new files: %s", diff)
+			}
+		})
+	}
+}
+
+func applyTextEdits(src string, edits []lang.TextEdit) string {
+	sorted := make([]lang.TextEdit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Range.Start.Byte > sorted[j].Range.Start.Byte
+	})
+
+	for _, e := range sorted {
+		src = src[:e.Range.Start.Byte] + e.NewText + src[e.Range.End.Byte:]
+	}
+	return src
+}
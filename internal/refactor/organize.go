@@ -0,0 +1,148 @@
+package refactor
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/settings"
+)
+
+// organizedBlockTypes are types of blocks whose contents are organized,
+// i.e. those which accept meta-arguments
+var organizedBlockTypes = map[string]bool{
+	"resource": true,
+	"data":     true,
+	"module":   true,
+}
+
+// OrganizeAttributes reorders attributes and nested blocks of resource,
+// data and module blocks in the given order of names, where any names
+// not listed are placed in the position of settings.OtherAttributes
+// or settings.OtherBlocks, keeping their original relative order.
+//
+// Each item moves with comments on the same line and comment lines
+// directly above it, while empty lines between items stay in place.
+// Blocks which have more items on a single line are left unchanged.
+//
+// False is returned if the source cannot be parsed
+// or if all blocks are already organized.
+func OrganizeAttributes(src []byte, filename string, order []string) ([]byte, bool) {
+	f, diags := hclsyntax.ParseConfig(src, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false
+	}
+
+	ranks := make(map[string]int, len(order))
+	for i, name := range order {
+		ranks[name] = i
+	}
+
+	edits := make([]byteEdit, 0)
+	for _, block := range body.Blocks {
+		if !organizedBlockTypes[block.Type] {
+			continue
+		}
+		edits = append(edits, organizeBlock(src, block, ranks)...)
+	}
+	if len(edits) == 0 {
+		return nil, false
+	}
+
+	return applyByteEdits(src, edits), true
+}
+
+// organizedItem is an attribute or a nested block
+// with the span of its lines, including attached comments
+type organizedItem struct {
+	rank       int
+	start, end int
+}
+
+func organizeBlock(src []byte, block *hclsyntax.Block, ranks map[string]int) []byteEdit {
+	if block.OpenBraceRange.Start.Line == block.CloseBraceRange.Start.Line {
+		return nil
+	}
+
+	type node struct {
+		name    string
+		isBlock bool
+		rng     hcl.Range
+	}
+	nodes := make([]node, 0, len(block.Body.Attributes)+len(block.Body.Blocks))
+	for _, attr := range block.Body.Attributes {
+		nodes = append(nodes, node{attr.Name, false, attr.SrcRange})
+	}
+	for _, b := range block.Body.Blocks {
+		nodes = append(nodes, node{b.Type, true, b.Range()})
+	}
+	if len(nodes) < 2 {
+		return nil
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].rng.Start.Byte < nodes[j].rng.Start.Byte
+	})
+
+	items := make([]organizedItem, 0, len(nodes))
+	prevEnd := lineEnd(src, block.OpenBraceRange.End.Byte)
+	for _, n := range nodes {
+		start := lineStart(src, n.rng.Start.Byte)
+		if start < prevEnd || strings.TrimSpace(string(src[start:n.rng.Start.Byte])) != "" {
+			return nil
+		}
+		end := lineEnd(src, n.rng.End.Byte)
+		if rest := strings.TrimSpace(string(src[n.rng.End.Byte:end])); rest != "" && !isComment(rest) {
+			return nil
+		}
+
+		// comment lines directly above belong to the item
+		for start > prevEnd {
+			prevStart := lineStart(src, start-1)
+			if !isComment(strings.TrimSpace(string(src[prevStart:start]))) {
+				break
+			}
+			start = prevStart
+		}
+
+		rank, ok := ranks[n.name]
+		if !ok {
+			rank = ranks[settings.OtherAttributes]
+			if n.isBlock {
+				rank = ranks[settings.OtherBlocks]
+			}
+		}
+
+		items = append(items, organizedItem{rank: rank, start: start, end: end})
+		prevEnd = end
+	}
+
+	sorted := make([]organizedItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].rank < sorted[j].rank
+	})
+
+	edits := make([]byteEdit, 0)
+	for i, slot := range items {
+		item := sorted[i]
+		if item.start == slot.start {
+			continue
+		}
+		edits = append(edits, byteEdit{
+			Start: slot.start,
+			End:   slot.end,
+			Text:  string(src[item.start:item.end]),
+		})
+	}
+	return edits
+}
+
+func isComment(line string) bool {
+	return strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") ||
+		(strings.HasPrefix(line, "/*") && strings.HasSuffix(line, "*/"))
+}
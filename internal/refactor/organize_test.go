@@ -0,0 +1,152 @@
+package refactor
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-ls/internal/settings"
+)
+
+func TestOrganizeAttributes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		src      string
+		order    []string
+		expected string
+	}{
+		{
+			"default order",
+			`resource "aws_instance" "web" {
+  depends_on = [aws_eip.ip]
+  ami        = "ami-123" # pinned
+
+  lifecycle {
+    create_before_destroy = true
+  }
+  ebs_block_device {
+    device_name = "sda"
+  }
+
+  # one per zone
+  count    = 2
+  provider = aws.west
+}
+
+variable "name" {
+  validation {
+    condition = true
+  }
+  type = string
+}
+`,
+			settings.DefaultAttributesOrder,
+			`resource "aws_instance" "web" {
+  # one per zone
+  count    = 2
+  provider = aws.west
+
+  ami        = "ami-123" # pinned
+  ebs_block_device {
+    device_name = "sda"
+  }
+
+  lifecycle {
+    create_before_destroy = true
+  }
+  depends_on = [aws_eip.ip]
+}
+
+variable "name" {
+  validation {
+    condition = true
+  }
+  type = string
+}
+`,
+		},
+		{
+			"custom order",
+			`module "app" {
+  name     = "app"
+  source   = "./app"
+  for_each = toset(["a", "b"])
+}
+`,
+			[]string{"source", "for_each", settings.OtherAttributes, settings.OtherBlocks},
+			`module "app" {
+  source   = "./app"
+  for_each = toset(["a", "b"])
+  name     = "app"
+}
+`,
+		},
+		{
+			"comments above items",
+			`data "aws_ami" "app" {
+  // newest only
+  most_recent = true
+  // one per region
+  /* see docs */
+  for_each = var.regions
+}
+`,
+			settings.DefaultAttributesOrder,
+			`data "aws_ami" "app" {
+  // one per region
+  /* see docs */
+  for_each = var.regions
+  // newest only
+  most_recent = true
+}
+`,
+		},
+		{
+			"already organized",
+			`resource "aws_instance" "web" {
+  count = 2
+  ami   = "ami-123"
+}
+`,
+			settings.DefaultAttributesOrder,
+			"",
+		},
+		{
+			"items sharing lines",
+			`resource "aws_instance" "web" {
+  ami = "ami-123"
+  lifecycle { create_before_destroy = true }
+  depends_on = [] }
+`,
+			settings.DefaultAttributesOrder,
+			"",
+		},
+		{
+			"invalid syntax",
+			`resource "aws_instance" "web" {
+  ami =
+  count = 2
+}
+`,
+			settings.DefaultAttributesOrder,
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			organized, ok := OrganizeAttributes([]byte(tc.src), "main.tf", tc.order)
+			if tc.expected == "" {
+				if ok {
+					t.Fatalf("expected no change, given: %s", organized)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected change")
+			}
+			if diff := cmp.Diff(tc.expected, string(organized)); diff != "" {
+				t.Fatalf("unexpected source: %s", diff)
+			}
+		})
+	}
+}
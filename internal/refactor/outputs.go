@@ -0,0 +1,274 @@
+package refactor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+const outputsFilename = "outputs.tf"
+
+// OutputsGeneration represents output blocks generated
+// for a resource, data source or module call
+type OutputsGeneration struct {
+	// Address is address of the block whose attributes are exposed
+	Address string
+	// Names are names of the generated outputs
+	Names []string
+
+	Edits    Edits
+	NewFiles []string
+}
+
+type generatedOutput struct {
+	attrName    string
+	description string
+	isSensitive bool
+}
+
+// GenerateOutputs calculates edits appending output blocks
+// to outputs.tf for the resource, data or module block within
+// the given range of the file.
+//
+// Attributes of resources and data sources come from the provider
+// schema, where attributes within the range are exposed, or all
+// computed attributes if there are none. Module calls expose all
+// outputs of the called module. Descriptions and sensitivity
+// are copied to the generated outputs.
+//
+// Path of outputs.tf is returned as a file to be created first
+// if it does not exist yet. False is returned if there is no such
+// block in the range or its attributes are not known.
+func GenerateOutputs(mf module.ModuleFinder, bodySchema *schema.BodySchema, modPath, filename string, rng hcl.Range) (*OutputsGeneration, bool, error) {
+	mod, err := mf.ModuleByPath(modPath)
+	if err != nil {
+		return nil, false, err
+	}
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return nil, false, nil
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, false, nil
+	}
+
+	block, ok := outputtableBlockAtPos(body, rng.Start)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var outputs []generatedOutput
+	switch block.Type {
+	case "resource", "data":
+		outputs, ok = attributeOutputs(block, bodySchema, rng)
+	case "module":
+		outputs, ok, err = moduleCallOutputs(mf, mod.Path, block.Labels[0])
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if !ok || len(outputs) == 0 {
+		return nil, false, nil
+	}
+
+	addr := blockAddress(block)
+	prefix := block.Labels[len(block.Labels)-1]
+
+	declared := make(map[string]bool, len(mod.Meta.Outputs))
+	for name := range mod.Meta.Outputs {
+		declared[name] = true
+	}
+
+	names := make([]string, 0, len(outputs))
+	blocks := make([]string, 0, len(outputs))
+	for _, out := range outputs {
+		name := uniqueName(declared, prefix+"_"+out.attrName)
+		declared[name] = true
+		names = append(names, name)
+
+		blocks = append(blocks, outputBlock(name, out, instanceValue(block, addr, out.attrName)))
+	}
+
+	edits, newFiles := appendToModuleFile(mod, outputsFilename, strings.Join(blocks, "\n"))
+
+	return &OutputsGeneration{
+		Address:  addr,
+		Names:    names,
+		Edits:    edits,
+		NewFiles: newFiles,
+	}, true, nil
+}
+
+func outputtableBlockAtPos(body *hclsyntax.Body, pos hcl.Pos) (*hclsyntax.Block, bool) {
+	for _, block := range body.Blocks {
+		if !block.Range().ContainsOffset(pos.Byte) {
+			continue
+		}
+		switch {
+		case (block.Type == "resource" || block.Type == "data") && len(block.Labels) == 2,
+			block.Type == "module" && len(block.Labels) == 1:
+			return block, true
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+func blockAddress(block *hclsyntax.Block) string {
+	switch block.Type {
+	case "data":
+		return fmt.Sprintf("data.%s.%s", block.Labels[0], block.Labels[1])
+	case "module":
+		return fmt.Sprintf("module.%s", block.Labels[0])
+	}
+	return fmt.Sprintf("%s.%s", block.Labels[0], block.Labels[1])
+}
+
+// attributeOutputs returns outputs of attributes of the resource
+// or data block which are within the range, or all computed ones
+func attributeOutputs(block *hclsyntax.Block, bodySchema *schema.BodySchema, rng hcl.Range) ([]generatedOutput, bool) {
+	if bodySchema == nil {
+		return nil, false
+	}
+	bSchema, ok := bodySchema.Blocks[block.Type]
+	if !ok {
+		return nil, false
+	}
+	depSchema, _, ok := decoder.NewBlockSchema(bSchema).DependentBodySchema(block.AsHCLBlock())
+	if !ok {
+		return nil, false
+	}
+
+	outputs := make([]generatedOutput, 0)
+	if rng.Start.Byte != rng.End.Byte {
+		attrs := make([]*hclsyntax.Attribute, 0)
+		for name, attr := range block.Body.Attributes {
+			if _, ok := depSchema.Attributes[name]; ok && rangesOverlap(attr.SrcRange, rng) {
+				attrs = append(attrs, attr)
+			}
+		}
+		sort.Slice(attrs, func(i, j int) bool {
+			return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+		})
+		for _, attr := range attrs {
+			outputs = append(outputs, attributeOutput(attr.Name, depSchema.Attributes[attr.Name]))
+		}
+		if len(outputs) > 0 {
+			return outputs, true
+		}
+	}
+
+	names := make([]string, 0, len(depSchema.Attributes))
+	for name, attrSchema := range depSchema.Attributes {
+		if attrSchema.IsComputed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		outputs = append(outputs, attributeOutput(name, depSchema.Attributes[name]))
+	}
+
+	return outputs, true
+}
+
+func attributeOutput(name string, attrSchema *schema.AttributeSchema) generatedOutput {
+	return generatedOutput{
+		attrName:    name,
+		description: attrSchema.Description.Value,
+		isSensitive: attrSchema.IsSensitive,
+	}
+}
+
+// moduleCallOutputs returns all outputs of the called module,
+// as long as it is installed and its metadata are loaded
+func moduleCallOutputs(mf module.ModuleFinder, modPath, name string) ([]generatedOutput, bool, error) {
+	calls, err := mf.ModuleCalls(modPath)
+	if err != nil {
+		return nil, false, err
+	}
+	var calledMod module.Module
+	for _, call := range calls {
+		if call.LocalName != name {
+			continue
+		}
+		calledMod, err = mf.ModuleByPath(call.Path)
+		if err != nil {
+			return nil, false, nil
+		}
+		break
+	}
+	if calledMod == nil {
+		return nil, false, nil
+	}
+
+	names := make([]string, 0, len(calledMod.Meta.Outputs))
+	for name := range calledMod.Meta.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	outputs := make([]generatedOutput, 0, len(names))
+	for _, name := range names {
+		out := calledMod.Meta.Outputs[name]
+		outputs = append(outputs, generatedOutput{
+			attrName:    name,
+			description: out.Description,
+			isSensitive: out.IsSensitive,
+		})
+	}
+	return outputs, true, nil
+}
+
+// instanceValue returns expression referencing the attribute
+// of all instances of the block
+func instanceValue(block *hclsyntax.Block, addr, attrName string) string {
+	if _, ok := block.Body.Attributes["count"]; ok {
+		return fmt.Sprintf("%s[*].%s", addr, attrName)
+	}
+	if _, ok := block.Body.Attributes["for_each"]; ok {
+		return fmt.Sprintf("{ for k, v in %s : k => v.%s }", addr, attrName)
+	}
+	return fmt.Sprintf("%s.%s", addr, attrName)
+}
+
+func outputBlock(name string, out generatedOutput, value string) string {
+	type attr struct {
+		name, value string
+	}
+	attrs := make([]attr, 0, 3)
+	if out.description != "" {
+		attrs = append(attrs, attr{"description", quotedKey(out.description)})
+	}
+	attrs = append(attrs, attr{"value", value})
+	if out.isSensitive {
+		attrs = append(attrs, attr{"sensitive", "true"})
+	}
+
+	width := 0
+	for _, a := range attrs {
+		if len(a.name) > width {
+			width = len(a.name)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "output %q {\n", name)
+	for _, a := range attrs {
+		fmt.Fprintf(&b, "  %-*s = %s\n", width, a.name, a.value)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func rangesOverlap(a, b hcl.Range) bool {
+	return a.Start.Byte < b.End.Byte && b.Start.Byte < a.End.Byte
+}
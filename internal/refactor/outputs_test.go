@@ -0,0 +1,324 @@
+package refactor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestGenerateOutputs(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		text             string
+		selected         bool
+		expectedAddress  string
+		expectedFiles    map[string]string
+		expectedNewFiles []string
+	}{
+		{
+			"computed attributes",
+			map[string]string{
+				"main.tf": `resource "aws_db_instance" "db" {
+  name = "app"
+}
+`,
+			},
+			`resource`,
+			false,
+			"aws_db_instance.db",
+			map[string]string{
+				"outputs.tf": `output "db_id" {
+  description = "The ID of the instance"
+  value       = aws_db_instance.db.id
+}
+
+output "db_password" {
+  value     = aws_db_instance.db.password
+  sensitive = true
+}
+`,
+			},
+			[]string{"outputs.tf"},
+		},
+		{
+			"selected attributes",
+			map[string]string{
+				"main.tf": `resource "aws_db_instance" "db" {
+  count = 2
+  name  = "app"
+}
+`,
+				"outputs.tf": `output "db_name" {
+  value = "app"
+}
+`,
+			},
+			`name  = "app"`,
+			true,
+			"aws_db_instance.db",
+			map[string]string{
+				"outputs.tf": `output "db_name" {
+  value = "app"
+}
+
+output "db_name_2" {
+  value = aws_db_instance.db[*].name
+}
+`,
+			},
+			[]string{},
+		},
+		{
+			"data source with for_each",
+			map[string]string{
+				"main.tf": `data "aws_db_instance" "db" {
+  for_each = toset(["a", "b"])
+}
+`,
+			},
+			`data`,
+			false,
+			"data.aws_db_instance.db",
+			map[string]string{
+				"outputs.tf": `output "db_address" {
+  value = { for k, v in data.aws_db_instance.db : k => v.address }
+}
+`,
+			},
+			[]string{"outputs.tf"},
+		},
+		{
+			"unknown resource type",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+}
+`,
+			},
+			`resource`,
+			false,
+			"",
+			nil,
+			nil,
+		},
+		{
+			"outside of resources",
+			map[string]string{
+				"main.tf": `locals {
+  name = "app"
+}
+`,
+			},
+			`name`,
+			false,
+			"",
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mm := loadSingleTestModuleManager(t, tc.files)
+
+			rng := textRange(t, tc.files["main.tf"], tc.text)
+			if !tc.selected {
+				rng.End = rng.Start
+			}
+			gen, ok, err := GenerateOutputs(mm, testOutputsSchema, modPath, "main.tf", rng)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no outputs, got %#v", gen)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected outputs")
+			}
+			if gen.Address != tc.expectedAddress {
+				t.Fatalf("expected address %q, given %q", tc.expectedAddress, gen.Address)
+			}
+
+			assertEditedFiles(t, modPath, tc.files, gen.Edits, gen.NewFiles, tc.expectedFiles, tc.expectedNewFiles)
+		})
+	}
+}
+
+func TestGenerateOutputs_moduleCall(t *testing.T) {
+	rootDir := t.TempDir()
+	appDir := filepath.Join(rootDir, "app")
+
+	files := map[string]string{
+		filepath.Join(rootDir, "main.tf"): `module "app" {
+  source = "./app"
+}
+`,
+		filepath.Join(rootDir, "outputs.tf"): `output "app_url" {
+  value = module.app.url
+}
+`,
+		filepath.Join(appDir, "outputs.tf"): `output "url" {
+  description = "URL of the app"
+  value       = "https://example.com"
+}
+
+output "token" {
+  value     = "secret"
+  sensitive = true
+}
+`,
+		filepath.Join(rootDir, ".terraform", "modules", "modules.json"): `{
+  "Modules": [
+    {
+      "Key": "",
+      "Source": "",
+      "Dir": "."
+    },
+    {
+      "Key": "app",
+      "Source": "./app",
+      "Dir": "app"
+    }
+  ]
+}`,
+	}
+	for path, content := range files {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mm := module.NewSyncModuleManager(context.Background(), filesystem.NewFilesystem(), ss.Modules, ss.ProviderSchemas)
+	for _, modPath := range []string{rootDir, appDir} {
+		_, err := mm.AddModule(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opTypes := []op.OpType{
+			op.OpTypeParseModuleConfiguration,
+			op.OpTypeLoadModuleMetadata,
+		}
+		if modPath == rootDir {
+			opTypes = append(opTypes, op.OpTypeParseModuleManifest)
+		}
+		for _, opType := range opTypes {
+			err := mm.EnqueueModuleOpWait(modPath, opType)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	pos := hcl.Pos{Line: 2, Column: 3, Byte: 17}
+	gen, ok, err := GenerateOutputs(mm, nil, rootDir, "main.tf", hcl.Range{Start: pos, End: pos})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected outputs")
+	}
+	if gen.Address != "module.app" {
+		t.Fatalf("unexpected address: %q", gen.Address)
+	}
+
+	expectedNames := []string{"app_token", "app_url_2"}
+	if diff := cmp.Diff(expectedNames, gen.Names); diff != "" {
+		t.Fatalf("unexpected names: %s", diff)
+	}
+
+	assertEditedFiles(t, rootDir, map[string]string{
+		"outputs.tf": files[filepath.Join(rootDir, "outputs.tf")],
+	}, gen.Edits, gen.NewFiles, map[string]string{
+		"outputs.tf": `output "app_url" {
+  value = module.app.url
+}
+
+output "app_token" {
+  value     = module.app.token
+  sensitive = true
+}
+
+output "app_url_2" {
+  description = "URL of the app"
+  value       = module.app.url
+}
+`,
+	}, []string{})
+}
+
+var testOutputsSchema = func() *schema.BodySchema {
+	dbSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"id": {
+				Description: lang.PlainText("The ID of the instance"),
+				Expr:        schema.LiteralTypeOnly(cty.String),
+				IsOptional:  true,
+				IsComputed:  true,
+			},
+			"name": {
+				Expr:       schema.LiteralTypeOnly(cty.String),
+				IsRequired: true,
+			},
+			"password": {
+				Expr:        schema.LiteralTypeOnly(cty.String),
+				IsComputed:  true,
+				IsSensitive: true,
+			},
+		},
+	}
+	dataSchema := &schema.BodySchema{
+		Attributes: map[string]*schema.AttributeSchema{
+			"address": {
+				Expr:       schema.LiteralTypeOnly(cty.String),
+				IsComputed: true,
+			},
+		},
+	}
+	labels := []*schema.LabelSchema{
+		{Name: "type", IsDepKey: true},
+		{Name: "name"},
+	}
+	dbKey := schema.NewSchemaKey(schema.DependencyKeys{
+		Labels: []schema.LabelDependent{
+			{Index: 0, Value: "aws_db_instance"},
+		},
+	})
+
+	return &schema.BodySchema{
+		Blocks: map[string]*schema.BlockSchema{
+			"resource": {
+				Labels:        labels,
+				Body:          &schema.BodySchema{},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{dbKey: dbSchema},
+			},
+			"data": {
+				Labels:        labels,
+				Body:          &schema.BodySchema{},
+				DependentBody: map[schema.SchemaKey]*schema.BodySchema{dbKey: dataSchema},
+			},
+		},
+	}
+}()
@@ -0,0 +1,153 @@
+package refactor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+type InvalidNameError struct {
+	Name string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("%q is not a valid identifier", e.Name)
+}
+
+type NameConflictError struct {
+	Kind SymbolKind
+	Name string
+}
+
+func (e *NameConflictError) Error() string {
+	return fmt.Sprintf("%s %q is already declared", e.Kind, e.Name)
+}
+
+// Rename calculates edits renaming the symbol to newName,
+// including any references to it within the declaring module.
+//
+// Variables are also renamed in arguments of module blocks
+// and outputs in references of all known callers.
+func Rename(mf module.ModuleFinder, sym *Symbol, newName string) (Edits, error) {
+	if !hclsyntax.ValidIdentifier(newName) {
+		return nil, &InvalidNameError{Name: newName}
+	}
+
+	edits := make(Edits, 0)
+	if newName == sym.Name {
+		return edits, nil
+	}
+
+	newSym := *sym
+	newSym.Name = newName
+	conflicts, err := declarationRanges(mf, &newSym)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, &NameConflictError{Kind: sym.Kind, Name: newName}
+	}
+
+	decls, err := declarationRanges(mf, sym)
+	if err != nil {
+		return nil, err
+	}
+	for _, rng := range decls {
+		edits.Add(filepath.Join(sym.ModulePath, rng.Filename), rng, newName)
+	}
+
+	mod, err := mf.ModuleByPath(sym.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if sym.Kind != OutputSymbol {
+		renameOrigins(edits, mod, sym.Address(), newName)
+	}
+
+	if sym.Kind == VariableSymbol {
+		renameAttributes(edits, mod.Path, mod.ParsedVarsFiles.AsMap(), sym.Name, newName)
+	}
+
+	if sym.Kind != VariableSymbol && sym.Kind != OutputSymbol {
+		return edits, nil
+	}
+
+	calls, err := callsOfModule(mf, sym.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, call := range calls {
+		caller, err := mf.ModuleByPath(call.CallerPath)
+		if err != nil {
+			continue
+		}
+
+		switch sym.Kind {
+		case VariableSymbol:
+			renameModuleArguments(edits, caller, call.Name, sym.Name, newName)
+		case OutputSymbol:
+			renameOrigins(edits, caller, lang.Address{
+				lang.RootStep{Name: "module"},
+				lang.AttrStep{Name: call.Name},
+				lang.AttrStep{Name: sym.Name},
+			}, newName)
+		}
+	}
+
+	return edits, nil
+}
+
+// renameOrigins renames the last step of the given address
+// in all references of the module starting with that address
+func renameOrigins(edits Edits, mod module.Module, addr lang.Address, newName string) {
+	files := mod.ParsedModuleFiles.AsMap()
+
+	for _, origin := range mod.RefOrigins {
+		if !addressHasPrefix(origin.Addr, addr) {
+			continue
+		}
+		f, ok := files[origin.Range.Filename]
+		if !ok {
+			continue
+		}
+		if _, ok := f.Body.(*hclsyntax.Body); !ok {
+			continue
+		}
+
+		ranges, ok := traversalStepRanges(f.Bytes, origin.Range)
+		if !ok || len(ranges) < len(addr) {
+			continue
+		}
+
+		edits.Add(filepath.Join(mod.Path, origin.Range.Filename),
+			ranges[len(addr)-1], newName)
+	}
+}
+
+// renameModuleArguments renames arguments of module blocks of given name
+func renameModuleArguments(edits Edits, caller module.Module, callName, oldName, newName string) {
+	for _, f := range syntaxFiles(caller.ParsedModuleFiles.AsMap()) {
+		for _, block := range f.Body.Blocks {
+			if block.Type != "module" || len(block.Labels) != 1 || block.Labels[0] != callName {
+				continue
+			}
+			if attr, ok := block.Body.Attributes[oldName]; ok {
+				edits.Add(filepath.Join(caller.Path, f.Name), attr.NameRange, newName)
+			}
+		}
+	}
+}
+
+// renameAttributes renames top-level attributes, such as those in tfvars files
+func renameAttributes(edits Edits, modPath string, files map[string]*hcl.File, oldName, newName string) {
+	for _, f := range syntaxFiles(files) {
+		if attr, ok := f.Body.Attributes[oldName]; ok {
+			edits.Add(filepath.Join(modPath, f.Name), attr.NameRange, newName)
+		}
+	}
+}
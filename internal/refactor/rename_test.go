@@ -0,0 +1,377 @@
+package refactor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+func TestSymbolAtPos(t *testing.T) {
+	rootDir, mm := loadTestModules(t)
+	childDir := filepath.Join(rootDir, "child")
+
+	testCases := []struct {
+		name        string
+		modPath     string
+		filename    string
+		pos         hcl.Pos
+		expectedSym *Symbol
+	}{
+		{
+			"resource declaration",
+			rootDir,
+			"main.tf",
+			hcl.Pos{Line: 1, Column: 25, Byte: 24},
+			&Symbol{
+				Kind:       ResourceSymbol,
+				ModulePath: rootDir,
+				Type:       "random_pet",
+				Name:       "app",
+				NameRange: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 1, Column: 24, Byte: 23},
+					End:      hcl.Pos{Line: 1, Column: 27, Byte: 26},
+				},
+			},
+		},
+		{
+			"resource type is not renameable",
+			rootDir,
+			"main.tf",
+			hcl.Pos{Line: 1, Column: 12, Byte: 11},
+			nil,
+		},
+		{
+			"local value reference",
+			rootDir,
+			"main.tf",
+			hcl.Pos{Line: 24, Column: 19, Byte: 298},
+			&Symbol{
+				Kind:       LocalSymbol,
+				ModulePath: rootDir,
+				Name:       "prefix",
+				NameRange: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 24, Column: 17, Byte: 296},
+					End:      hcl.Pos{Line: 24, Column: 23, Byte: 302},
+				},
+			},
+		},
+		{
+			"child module output reference",
+			rootDir,
+			"main.tf",
+			hcl.Pos{Line: 16, Column: 25, Byte: 208},
+			&Symbol{
+				Kind:       OutputSymbol,
+				ModulePath: childDir,
+				Name:       "id",
+				NameRange: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 16, Column: 24, Byte: 207},
+					End:      hcl.Pos{Line: 16, Column: 26, Byte: 209},
+				},
+			},
+		},
+		{
+			"variable declaration",
+			childDir,
+			"main.tf",
+			hcl.Pos{Line: 1, Column: 12, Byte: 11},
+			&Symbol{
+				Kind:       VariableSymbol,
+				ModulePath: childDir,
+				Name:       "name",
+				NameRange: hcl.Range{
+					Filename: "main.tf",
+					Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+					End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sym, err := SymbolAtPos(mm, tc.modPath, tc.filename, tc.pos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedSym, sym); diff != "" {
+				t.Fatalf("unexpected symbol: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	rootDir, mm := loadTestModules(t)
+	childDir := filepath.Join(rootDir, "child")
+
+	testCases := []struct {
+		name          string
+		sym           *Symbol
+		newName       string
+		expectedEdits Edits
+	}{
+		{
+			"resource",
+			&Symbol{
+				Kind:       ResourceSymbol,
+				ModulePath: rootDir,
+				Type:       "random_pet",
+				Name:       "app",
+			},
+			"web",
+			Edits{
+				filepath.Join(rootDir, "main.tf"): {
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 1, Column: 24, Byte: 23},
+							End:      hcl.Pos{Line: 1, Column: 27, Byte: 26},
+						},
+						NewText: "web",
+					},
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 20, Column: 22, Byte: 252},
+							End:      hcl.Pos{Line: 20, Column: 25, Byte: 255},
+						},
+						NewText: "web",
+					},
+				},
+			},
+		},
+		{
+			"variable with caller",
+			&Symbol{
+				Kind:       VariableSymbol,
+				ModulePath: childDir,
+				Name:       "name",
+			},
+			"pet_name",
+			Edits{
+				filepath.Join(childDir, "main.tf"): {
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 1, Column: 11, Byte: 10},
+							End:      hcl.Pos{Line: 1, Column: 15, Byte: 14},
+						},
+						NewText: "pet_name",
+					},
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 5, Column: 15, Byte: 49},
+							End:      hcl.Pos{Line: 5, Column: 19, Byte: 53},
+						},
+						NewText: "pet_name",
+					},
+				},
+				filepath.Join(rootDir, "main.tf"): {
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 11, Column: 3, Byte: 122},
+							End:      hcl.Pos{Line: 11, Column: 7, Byte: 126},
+						},
+						NewText: "pet_name",
+					},
+				},
+			},
+		},
+		{
+			"output with caller",
+			&Symbol{
+				Kind:       OutputSymbol,
+				ModulePath: childDir,
+				Name:       "id",
+			},
+			"pet_id",
+			Edits{
+				filepath.Join(childDir, "main.tf"): {
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 4, Column: 9, Byte: 29},
+							End:      hcl.Pos{Line: 4, Column: 11, Byte: 31},
+						},
+						NewText: "pet_id",
+					},
+				},
+				filepath.Join(rootDir, "main.tf"): {
+					{
+						Range: hcl.Range{
+							Filename: "main.tf",
+							Start:    hcl.Pos{Line: 16, Column: 24, Byte: 207},
+							End:      hcl.Pos{Line: 16, Column: 26, Byte: 209},
+						},
+						NewText: "pet_id",
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			edits, err := Rename(mm, tc.sym, tc.newName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expectedEdits, edits); diff != "" {
+				t.Fatalf("unexpected edits: %s", diff)
+			}
+		})
+	}
+}
+
+func TestRename_conflict(t *testing.T) {
+	rootDir, mm := loadTestModules(t)
+
+	_, err := Rename(mm, &Symbol{
+		Kind:       LocalSymbol,
+		ModulePath: rootDir,
+		Name:       "prefix",
+	}, "suffix")
+
+	var conflictErr *NameConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("expected conflict error, given: %#v", err)
+	}
+}
+
+func TestAddressHasPrefix(t *testing.T) {
+	addr := lang.Address{
+		lang.RootStep{Name: "module"},
+		lang.AttrStep{Name: "child"},
+		lang.AttrStep{Name: "id"},
+	}
+	if !addressHasPrefix(addr, addr[:2]) {
+		t.Fatal("expected address to have prefix")
+	}
+	if addressHasPrefix(addr[:2], addr) {
+		t.Fatal("expected shorter address not to have prefix")
+	}
+}
+
+const testRootModuleCfg = `resource "random_pet" "app" {
+  prefix = local.prefix
+}
+
+locals {
+  prefix = "app"
+  suffix = "pet"
+}
+
+module "child" {
+  name = local.prefix
+  source = "./child"
+}
+
+output "pet_id" {
+  value = module.child.id
+}
+
+output "app_id" {
+  value = random_pet.app.id
+}
+
+output "prefix" {
+  value = local.prefix
+}
+`
+
+const testChildModuleCfg = `variable "name" {
+}
+
+output "id" {
+  value = var.name
+}
+`
+
+const testManifest = `{
+  "Modules": [
+    {
+      "Key": "",
+      "Source": "",
+      "Dir": "."
+    },
+    {
+      "Key": "child",
+      "Source": "./child",
+      "Dir": "child"
+    }
+  ]
+}`
+
+func loadTestModules(t *testing.T) (string, module.ModuleManager) {
+	rootDir := t.TempDir()
+	childDir := filepath.Join(rootDir, "child")
+	manifestDir := filepath.Join(rootDir, ".terraform", "modules")
+
+	files := map[string]string{
+		filepath.Join(rootDir, "main.tf"):          testRootModuleCfg,
+		filepath.Join(childDir, "main.tf"):         testChildModuleCfg,
+		filepath.Join(manifestDir, "modules.json"): testManifest,
+	}
+	for path, content := range files {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	mm := module.NewSyncModuleManager(ctx, filesystem.NewFilesystem(), ss.Modules, ss.ProviderSchemas)
+
+	opTypes := []op.OpType{
+		op.OpTypeParseModuleConfiguration,
+		op.OpTypeParseVariables,
+		op.OpTypeLoadModuleMetadata,
+		op.OpTypeDecodeReferenceTargets,
+		op.OpTypeDecodeReferenceOrigins,
+	}
+	for _, modPath := range []string{rootDir, childDir} {
+		_, err := mm.AddModule(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if modPath == rootDir {
+			err = mm.EnqueueModuleOpWait(modPath, op.OpTypeParseModuleManifest)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, opType := range opTypes {
+			err := mm.EnqueueModuleOpWait(modPath, opType)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return rootDir, mm
+}
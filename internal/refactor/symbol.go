@@ -0,0 +1,289 @@
+package refactor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+type SymbolKind uint
+
+const (
+	NilSymbolKind SymbolKind = iota
+	VariableSymbol
+	LocalSymbol
+	OutputSymbol
+	ResourceSymbol
+	DataSymbol
+	ModuleCallSymbol
+)
+
+func (k SymbolKind) String() string {
+	switch k {
+	case VariableSymbol:
+		return "variable"
+	case LocalSymbol:
+		return "local value"
+	case OutputSymbol:
+		return "output"
+	case ResourceSymbol:
+		return "resource"
+	case DataSymbol:
+		return "data source"
+	case ModuleCallSymbol:
+		return "module"
+	}
+	return "unknown"
+}
+
+// Symbol represents a named declaration within a module
+// which can be referenced from elsewhere
+type Symbol struct {
+	Kind SymbolKind
+
+	// ModulePath is path to the module where the symbol is declared
+	ModulePath string
+
+	// Type is the resource or data source type (if applicable)
+	Type string
+	Name string
+
+	// NameRange is range of the name at the position
+	// which the symbol was looked up by
+	NameRange hcl.Range
+}
+
+// Address returns the address by which the symbol is referenced
+// within the module where it is declared. Outputs are only
+// referenced from calling modules and have no such address.
+func (s *Symbol) Address() lang.Address {
+	switch s.Kind {
+	case VariableSymbol:
+		return lang.Address{lang.RootStep{Name: "var"}, lang.AttrStep{Name: s.Name}}
+	case LocalSymbol:
+		return lang.Address{lang.RootStep{Name: "local"}, lang.AttrStep{Name: s.Name}}
+	case ResourceSymbol:
+		return lang.Address{lang.RootStep{Name: s.Type}, lang.AttrStep{Name: s.Name}}
+	case DataSymbol:
+		return lang.Address{lang.RootStep{Name: "data"}, lang.AttrStep{Name: s.Type}, lang.AttrStep{Name: s.Name}}
+	case ModuleCallSymbol:
+		return lang.Address{lang.RootStep{Name: "module"}, lang.AttrStep{Name: s.Name}}
+	}
+	return lang.Address{}
+}
+
+// SymbolAtPos returns symbol declared or referenced at given position
+// of a file within the module, or nil if there is no such symbol
+func SymbolAtPos(mf module.ModuleFinder, modPath, filename string, pos hcl.Pos) (*Symbol, error) {
+	mod, err := mf.ModuleByPath(modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f, ok := mod.ParsedModuleFiles.AsMap()[filename]
+	if !ok {
+		return nil, fmt.Errorf("%s: file not parsed", filename)
+	}
+	body, ok := f.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, nil
+	}
+
+	if sym, ok := declaredSymbolAtPos(body, f.Bytes, pos); ok {
+		sym.ModulePath = mod.Path
+		return sym, nil
+	}
+
+	for _, origin := range mod.RefOrigins {
+		if origin.Range.Filename != filename || !origin.Range.ContainsPos(pos) {
+			continue
+		}
+
+		sym, ok, err := referencedSymbolAtPos(mf, mod, f.Bytes, origin, pos)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		decls, err := declarationRanges(mf, sym)
+		if err != nil {
+			return nil, err
+		}
+		if len(decls) == 0 {
+			return nil, nil
+		}
+
+		return sym, nil
+	}
+
+	return nil, nil
+}
+
+func declaredSymbolAtPos(body *hclsyntax.Body, src []byte, pos hcl.Pos) (*Symbol, bool) {
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "locals":
+			for _, attr := range block.Body.Attributes {
+				if attr.NameRange.ContainsPos(pos) {
+					return &Symbol{
+						Kind:      LocalSymbol,
+						Name:      attr.Name,
+						NameRange: attr.NameRange,
+					}, true
+				}
+			}
+		case "variable", "output", "module":
+			if len(block.Labels) != 1 {
+				continue
+			}
+			rng := labelNameRange(src, block.LabelRanges[0])
+			if rng.ContainsPos(pos) {
+				return &Symbol{
+					Kind:      declarationKinds[block.Type],
+					Name:      block.Labels[0],
+					NameRange: rng,
+				}, true
+			}
+		case "resource", "data":
+			if len(block.Labels) != 2 {
+				continue
+			}
+			rng := labelNameRange(src, block.LabelRanges[1])
+			if rng.ContainsPos(pos) {
+				return &Symbol{
+					Kind:      declarationKinds[block.Type],
+					Type:      block.Labels[0],
+					Name:      block.Labels[1],
+					NameRange: rng,
+				}, true
+			}
+		}
+	}
+	return nil, false
+}
+
+var declarationKinds = map[string]SymbolKind{
+	"variable": VariableSymbol,
+	"output":   OutputSymbol,
+	"module":   ModuleCallSymbol,
+	"resource": ResourceSymbol,
+	"data":     DataSymbol,
+}
+
+// ignoredRootNames represents root names of references
+// which do not point to any renameable declaration
+var ignoredRootNames = map[string]bool{
+	"count":     true,
+	"each":      true,
+	"path":      true,
+	"self":      true,
+	"terraform": true,
+}
+
+func referencedSymbolAtPos(mf module.ModuleFinder, mod module.Module, src []byte,
+	origin lang.ReferenceOrigin, pos hcl.Pos) (*Symbol, bool, error) {
+	ranges, ok := traversalStepRanges(src, origin.Range)
+	if !ok || len(ranges) != len(origin.Addr) {
+		return nil, false, nil
+	}
+
+	names := make([]string, 0)
+	for _, step := range origin.Addr {
+		name, ok := stepName(step)
+		if !ok {
+			break
+		}
+		names = append(names, name)
+	}
+	if len(names) < 2 {
+		return nil, false, nil
+	}
+
+	sym := &Symbol{
+		ModulePath: mod.Path,
+	}
+	nameIdx := 1
+
+	switch root := names[0]; {
+	case ignoredRootNames[root]:
+		return nil, false, nil
+	case root == "var":
+		sym.Kind = VariableSymbol
+	case root == "local":
+		sym.Kind = LocalSymbol
+	case root == "data":
+		if len(names) < 3 {
+			return nil, false, nil
+		}
+		sym.Kind = DataSymbol
+		sym.Type = names[1]
+		nameIdx = 2
+	case root == "module":
+		if len(names) > 2 && ranges[2].ContainsPos(pos) {
+			childPath, ok, err := calledModulePath(mf, mod.Path, names[1])
+			if err != nil || !ok {
+				return nil, false, err
+			}
+			sym.Kind = OutputSymbol
+			sym.ModulePath = childPath
+			nameIdx = 2
+			break
+		}
+		sym.Kind = ModuleCallSymbol
+	default:
+		sym.Kind = ResourceSymbol
+		sym.Type = root
+	}
+
+	if !ranges[nameIdx].ContainsPos(pos) {
+		return nil, false, nil
+	}
+
+	sym.Name = names[nameIdx]
+	sym.NameRange = ranges[nameIdx]
+
+	return sym, true, nil
+}
+
+// declarationRanges returns name ranges of all declarations of the symbol
+func declarationRanges(mf module.ModuleFinder, sym *Symbol) ([]hcl.Range, error) {
+	mod, err := mf.ModuleByPath(sym.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]hcl.Range, 0)
+	for _, f := range syntaxFiles(mod.ParsedModuleFiles.AsMap()) {
+		for _, block := range f.Body.Blocks {
+			if block.Type == "locals" && sym.Kind == LocalSymbol {
+				if attr, ok := block.Body.Attributes[sym.Name]; ok {
+					ranges = append(ranges, attr.NameRange)
+				}
+				continue
+			}
+
+			kind, ok := declarationKinds[block.Type]
+			if !ok || kind != sym.Kind {
+				continue
+			}
+
+			switch kind {
+			case VariableSymbol, OutputSymbol, ModuleCallSymbol:
+				if len(block.Labels) == 1 && block.Labels[0] == sym.Name {
+					ranges = append(ranges, labelNameRange(f.Bytes, block.LabelRanges[0]))
+				}
+			case ResourceSymbol, DataSymbol:
+				if len(block.Labels) == 2 && block.Labels[0] == sym.Type && block.Labels[1] == sym.Name {
+					ranges = append(ranges, labelNameRange(f.Bytes, block.LabelRanges[1]))
+				}
+			}
+		}
+	}
+
+	return ranges, nil
+}
@@ -0,0 +1,113 @@
+package refactor
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+type syntaxFile struct {
+	Name  string
+	Bytes []byte
+	Body  *hclsyntax.Body
+}
+
+// syntaxFiles returns native syntax files sorted by name.
+// JSON files are skipped as their structure cannot be reliably edited.
+func syntaxFiles(files map[string]*hcl.File) []syntaxFile {
+	sf := make([]syntaxFile, 0)
+	for name, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		sf = append(sf, syntaxFile{
+			Name:  name,
+			Bytes: f.Bytes,
+			Body:  body,
+		})
+	}
+	sort.SliceStable(sf, func(i, j int) bool {
+		return sf[i].Name < sf[j].Name
+	})
+	return sf
+}
+
+// labelNameRange returns range of the label without any quotes
+func labelNameRange(src []byte, rng hcl.Range) hcl.Range {
+	if rng.End.Byte-rng.Start.Byte < 2 || rng.End.Byte > len(src) {
+		return rng
+	}
+	if src[rng.Start.Byte] != '"' || src[rng.End.Byte-1] != '"' {
+		return rng
+	}
+
+	rng.Start.Byte++
+	rng.Start.Column++
+	rng.End.Byte--
+	rng.End.Column--
+	return rng
+}
+
+// traversalStepRanges parses the traversal found within the given range
+// of src and returns ranges of names of each of its root and attribute
+// steps (index steps have zero range)
+func traversalStepRanges(src []byte, rng hcl.Range) ([]hcl.Range, bool) {
+	if rng.Start.Byte > rng.End.Byte || rng.End.Byte > len(src) {
+		return nil, false
+	}
+
+	traversal, diags := hclsyntax.ParseTraversalAbs(src[rng.Start.Byte:rng.End.Byte],
+		rng.Filename, rng.Start)
+	if diags.HasErrors() {
+		return nil, false
+	}
+
+	ranges := make([]hcl.Range, len(traversal))
+	for i, step := range traversal {
+		switch t := step.(type) {
+		case hcl.TraverseRoot:
+			ranges[i] = t.SrcRange
+		case hcl.TraverseAttr:
+			ranges[i] = nameAtEndOfRange(t.SrcRange, t.Name)
+		}
+	}
+
+	return ranges, true
+}
+
+func nameAtEndOfRange(rng hcl.Range, name string) hcl.Range {
+	rng.Start = hcl.Pos{
+		Line:   rng.End.Line,
+		Column: rng.End.Column - utf8.RuneCountInString(name),
+		Byte:   rng.End.Byte - len(name),
+	}
+	return rng
+}
+
+// addressHasPrefix reports whether the address starts with all steps
+// of the given prefix
+func addressHasPrefix(addr, prefix lang.Address) bool {
+	if len(addr) < len(prefix) {
+		return false
+	}
+	for i, step := range prefix {
+		if addr[i].String() != step.String() {
+			return false
+		}
+	}
+	return true
+}
+
+func stepName(step lang.AddressStep) (string, bool) {
+	switch s := step.(type) {
+	case lang.RootStep:
+		return s.Name, true
+	case lang.AttrStep:
+		return s.Name, true
+	}
+	return "", false
+}
@@ -0,0 +1,183 @@
+package refactor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const varsFilename = "terraform.tfvars"
+
+// DeclareVariables calculates edits appending declarations of all
+// variables which are referenced within the module, but not declared,
+// to variables.tf. Types are inferred from constraints of attributes
+// where the variables are referenced, falling back to any if unknown
+// or conflicting.
+//
+// Path of variables.tf is returned as a file to be created first
+// if it does not exist yet. False is returned if there is no
+// undeclared variable.
+func DeclareVariables(mod module.Module) (Edits, []string, bool) {
+	declared := declaredNames(mod, ExtractToVariable)
+
+	types := make(map[string]cty.Type, 0)
+	conflicting := make(map[string]bool, 0)
+	for _, origin := range mod.RefOrigins {
+		if len(origin.Addr) < 2 || origin.Addr[0].String() != "var" {
+			continue
+		}
+		name, ok := stepName(origin.Addr[1])
+		if !ok || declared[name] {
+			continue
+		}
+
+		ty := cty.DynamicPseudoType
+		if len(origin.Addr) == 2 {
+			ty = originType(origin.Constraints)
+		}
+
+		prevTy, ok := types[name]
+		switch {
+		case !ok, prevTy == cty.DynamicPseudoType:
+			types[name] = ty
+		case ty != cty.DynamicPseudoType && !prevTy.Equals(ty):
+			conflicting[name] = true
+		}
+	}
+	for name := range conflicting {
+		types[name] = cty.DynamicPseudoType
+	}
+
+	if len(types) == 0 {
+		return nil, nil, false
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var decls strings.Builder
+	for i, name := range names {
+		if i > 0 {
+			decls.WriteString("\n")
+		}
+		fmt.Fprintf(&decls, "variable %q {\n  type = %s\n}\n",
+			name, typeexpr.TypeString(types[name]))
+	}
+
+	edits, newFiles := appendToModuleFile(mod, variablesFilename, decls.String())
+	return edits, newFiles, true
+}
+
+// originType returns type of the attribute where a reference is found,
+// or cty.DynamicPseudoType if the type is not known
+func originType(constraints lang.ReferenceOriginConstraints) cty.Type {
+	for _, c := range constraints {
+		if c.OfType != cty.NilType {
+			return c.OfType
+		}
+	}
+	return cty.DynamicPseudoType
+}
+
+// GenerateVarsFile calculates edits appending all required variables
+// (i.e. without default values) of the module which are not set in
+// terraform.tfvars yet to that file, with placeholder values.
+//
+// Path of terraform.tfvars is returned as a file to be created first
+// if it does not exist yet. False is returned if there is no such variable.
+func GenerateVarsFile(mod module.Module) (Edits, []string, bool) {
+	assigned := make(map[string]bool, 0)
+	if f, ok := mod.ParsedVarsFiles[ast.VarsFilename(varsFilename)]; ok {
+		attrs, _ := f.Body.JustAttributes()
+		for name := range attrs {
+			assigned[name] = true
+		}
+	}
+
+	names := make([]string, 0)
+	for name, v := range mod.Meta.Variables {
+		if v.DefaultValue != cty.NilVal || assigned[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, nil, false
+	}
+	sort.Strings(names)
+
+	var values strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&values, "%s = %s\n", name, placeholderValue(mod.Meta.Variables[name].Type))
+	}
+
+	edits, newFiles := appendToModuleFile(mod, varsFilename, values.String())
+	return edits, newFiles, true
+}
+
+// placeholderValue returns an empty value of the type in native syntax
+func placeholderValue(ty cty.Type) string {
+	switch {
+	case ty == cty.Number:
+		return "0"
+	case ty == cty.Bool:
+		return "false"
+	case ty.IsListType(), ty.IsSetType(), ty.IsTupleType():
+		return "[]"
+	case ty.IsMapType():
+		return "{}"
+	case ty.IsObjectType():
+		attrTypes := ty.AttributeTypes()
+		names := make([]string, 0, len(attrTypes))
+		for name := range attrTypes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		attrs := make([]string, 0, len(names))
+		for _, name := range names {
+			attrs = append(attrs, fmt.Sprintf("%s = %s", name, placeholderValue(attrTypes[name])))
+		}
+		if len(attrs) == 0 {
+			return "{}"
+		}
+		return fmt.Sprintf("{ %s }", strings.Join(attrs, ", "))
+	}
+	// strings are the most common, so these are also assumed
+	// for unknown types, as null would not satisfy required variables
+	return `""`
+}
+
+// appendToModuleFile returns edits appending text to the module file,
+// which is returned as a new file if it does not exist yet
+func appendToModuleFile(mod module.Module, filename, text string) (Edits, []string) {
+	edits := make(Edits, 0)
+	path := filepath.Join(mod.Path, filename)
+
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		f, ok = mod.ParsedVarsFiles[ast.VarsFilename(filename)]
+	}
+	if !ok {
+		edits.Add(path, hcl.Range{
+			Filename: filename,
+			Start:    hcl.InitialPos,
+			End:      hcl.InitialPos,
+		}, text)
+		return edits, []string{path}
+	}
+
+	addByteEdit(edits, path, f.Bytes, appendToFile(f.Bytes, text))
+	return edits, []string{}
+}
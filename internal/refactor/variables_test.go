@@ -0,0 +1,294 @@
+package refactor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDeclareVariables(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		// origins with constraints of attributes which would be
+		// decoded with provider schemas, located in main.tf
+		origins          map[string]cty.Type
+		expectedFiles    map[string]string
+		expectedNewFiles []string
+	}{
+		{
+			"types from usage",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  ami           = var.ami
+  instance_type = var.name
+}
+
+output "name" {
+  value = var.name
+}
+
+output "zone" {
+  value = var.region.zone
+}
+`,
+				"variables.tf": `variable "env" {
+}
+`,
+			},
+			map[string]cty.Type{
+				"var.ami":  cty.String,
+				"var.name": cty.String,
+			},
+			map[string]string{
+				"variables.tf": `variable "env" {
+}
+
+variable "ami" {
+  type = string
+}
+
+variable "name" {
+  type = string
+}
+
+variable "region" {
+  type = any
+}
+`,
+			},
+			[]string{},
+		},
+		{
+			"new file",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "app" {
+  count = var.instances
+}
+`,
+			},
+			map[string]cty.Type{
+				"var.instances": cty.Number,
+			},
+			map[string]string{
+				"variables.tf": `variable "instances" {
+  type = number
+}
+`,
+			},
+			[]string{"variables.tf"},
+		},
+		{
+			"all declared",
+			map[string]string{
+				"main.tf": `variable "instances" {
+}
+
+resource "aws_instance" "app" {
+  count = var.instances
+}
+`,
+			},
+			map[string]cty.Type{
+				"var.instances": cty.Number,
+			},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mod := loadSingleTestModule(t, tc.files)
+			for ref, ty := range tc.origins {
+				mod.RefOrigins = append(mod.RefOrigins, lang.ReferenceOrigin{
+					Addr:  testAddress(t, ref),
+					Range: textRange(t, tc.files["main.tf"], ref),
+					Constraints: lang.ReferenceOriginConstraints{
+						{OfType: ty},
+					},
+				})
+			}
+
+			edits, newFiles, ok := DeclareVariables(mod)
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no declarations, got %#v", edits)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected declarations")
+			}
+
+			assertEditedFiles(t, modPath, tc.files, edits, newFiles, tc.expectedFiles, tc.expectedNewFiles)
+		})
+	}
+}
+
+func TestGenerateVarsFile(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		expectedFiles    map[string]string
+		expectedNewFiles []string
+	}{
+		{
+			"new file",
+			map[string]string{
+				"variables.tf": `variable "name" {
+  type = string
+}
+
+variable "tags" {
+  type = map(string)
+}
+
+variable "network" {
+  type = object({
+    cidr    = string
+    subnets = list(string)
+  })
+}
+
+variable "env" {
+  default = "dev"
+}
+`,
+			},
+			map[string]string{
+				"terraform.tfvars": `name = ""
+network = { cidr = "", subnets = [] }
+tags = {}
+`,
+			},
+			[]string{"terraform.tfvars"},
+		},
+		{
+			"existing file",
+			map[string]string{
+				"variables.tf": `variable "name" {
+  type = string
+}
+
+variable "instances" {
+  type = number
+}
+`,
+				"terraform.tfvars": `name = "app"`,
+			},
+			map[string]string{
+				"terraform.tfvars": `name = "app"
+
+instances = 0
+`,
+			},
+			[]string{},
+		},
+		{
+			"all assigned",
+			map[string]string{
+				"variables.tf": `variable "name" {
+}
+`,
+				"terraform.tfvars": `name = "app"
+`,
+			},
+			nil,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			modPath, mod := loadSingleTestModule(t, tc.files)
+
+			edits, newFiles, ok := GenerateVarsFile(mod)
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no values, got %#v", edits)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected values")
+			}
+
+			assertEditedFiles(t, modPath, tc.files, edits, newFiles, tc.expectedFiles, tc.expectedNewFiles)
+		})
+	}
+}
+
+func TestPlaceholderValue(t *testing.T) {
+	testCases := []struct {
+		ty       cty.Type
+		expected string
+	}{
+		{cty.String, `""`},
+		{cty.Number, "0"},
+		{cty.Bool, "false"},
+		{cty.Set(cty.String), "[]"},
+		{cty.Map(cty.Number), "{}"},
+		{cty.EmptyObject, "{}"},
+		{cty.Object(map[string]cty.Type{"b": cty.Bool, "a": cty.String}), `{ a = "", b = false }`},
+		{cty.DynamicPseudoType, `""`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ty.FriendlyName(), func(t *testing.T) {
+			value := placeholderValue(tc.ty)
+			if value != tc.expected {
+				t.Fatalf("expected %q, given %q", tc.expected, value)
+			}
+		})
+	}
+}
+
+func testAddress(t *testing.T, ref string) lang.Address {
+	traversal, diags := hclsyntax.ParseTraversalAbs([]byte(ref), "test.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+	addr := lang.Address{}
+	for _, step := range traversal {
+		switch s := step.(type) {
+		case hcl.TraverseRoot:
+			addr = append(addr, lang.RootStep{Name: s.Name})
+		case hcl.TraverseAttr:
+			addr = append(addr, lang.AttrStep{Name: s.Name})
+		}
+	}
+	return addr
+}
+
+func assertEditedFiles(t *testing.T, modPath string, files map[string]string, edits Edits, newFiles []string,
+	expectedFiles map[string]string, expectedNewFiles []string) {
+	editedFiles := make(map[string]string, len(edits))
+	for path, fileEdits := range edits {
+		rel, err := filepath.Rel(modPath, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		editedFiles[rel] = applyTextEdits(files[rel], fileEdits)
+	}
+	if diff := cmp.Diff(expectedFiles, editedFiles); diff != "" {
+		t.Fatalf("unexpected files: %s", diff)
+	}
+
+	relNewFiles := make([]string, 0, len(newFiles))
+	for _, path := range newFiles {
+		rel, err := filepath.Rel(modPath, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		relNewFiles = append(relNewFiles, rel)
+	}
+	if diff := cmp.Diff(expectedNewFiles, relNewFiles); diff != "" {
+		t.Fatalf("unexpected new files: %s", diff)
+	}
+}
@@ -8,11 +8,76 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
+const (
+	// TerraformFormatter formats via Terraform CLI (terraform fmt)
+	// falling back to the native formatter if Terraform is not found
+	TerraformFormatter = "terraform"
+	// NativeFormatter formats in-process, without Terraform CLI
+	NativeFormatter = "native"
+
+	// DefaultMovedBlocksFile is the file which moved blocks
+	// generated on rename are written into by default
+	DefaultMovedBlocksFile = "moved.tf"
+
+	// OtherAttributes stands for all attributes
+	// not listed in the order of organized attributes
+	OtherAttributes = "<attributes>"
+	// OtherBlocks stands for all nested blocks
+	// not listed in the order of organized attributes
+	OtherBlocks = "<blocks>"
+)
+
+// DefaultAttributesOrder is the order of contents of resource,
+// data and module blocks used when organizing attributes by default
+var DefaultAttributesOrder = []string{
+	"count",
+	"for_each",
+	"provider",
+	OtherAttributes,
+	OtherBlocks,
+	"lifecycle",
+	"depends_on",
+}
+
 type ExperimentalFeatures struct {
-	ValidateOnSave        bool `mapstructure:"validateOnSave"`
+	ValidateOnSave bool `mapstructure:"validateOnSave"`
+	// ValidateOnChange enables validation by the server itself
+	// whenever a document is opened or changed
+	ValidateOnChange      bool `mapstructure:"validateOnChange"`
 	PrefillRequiredFields bool `mapstructure:"prefillRequiredFields"`
 }
 
+type RenameOptions struct {
+	// MovedBlocks enables generation of moved blocks
+	// when renaming resources and module calls
+	MovedBlocks     bool   `mapstructure:"movedBlocks"`
+	MovedBlocksFile string `mapstructure:"movedBlocksFile"`
+}
+
+// MovedBlocksFilename returns name of the file for moved blocks
+func (o RenameOptions) MovedBlocksFilename() string {
+	if o.MovedBlocksFile == "" {
+		return DefaultMovedBlocksFile
+	}
+	return o.MovedBlocksFile
+}
+
+type OrganizeAttributesOptions struct {
+	// Order lists names of attributes and nested blocks in the order
+	// they are organized into, including OtherAttributes and OtherBlocks
+	Order []string `mapstructure:"order"`
+	// OnFormat enables organizing attributes when formatting documents
+	OnFormat bool `mapstructure:"onFormat"`
+}
+
+// AttributesOrder returns order of attributes to organize blocks into
+func (o OrganizeAttributesOptions) AttributesOrder() []string {
+	if len(o.Order) == 0 {
+		return DefaultAttributesOrder
+	}
+	return o.Order
+}
+
 type Options struct {
 	// ModulePaths describes a list of absolute paths to modules to load
 	ModulePaths        []string `mapstructure:"rootModulePaths"`
@@ -25,6 +90,13 @@ type Options struct {
 	TerraformExecPath    string `mapstructure:"terraformExecPath"`
 	TerraformExecTimeout string `mapstructure:"terraformExecTimeout"`
 	TerraformLogFilePath string `mapstructure:"terraformLogFilePath"`
+
+	// Formatter is either TerraformFormatter (default) or NativeFormatter
+	Formatter string `mapstructure:"formatter"`
+
+	Rename RenameOptions `mapstructure:"rename"`
+
+	OrganizeAttributes OrganizeAttributesOptions `mapstructure:"organizeAttributes"`
 }
 
 func (o *Options) Validate() error {
@@ -46,6 +118,33 @@ func (o *Options) Validate() error {
 		}
 	}
 
+	switch o.Formatter {
+	case "", TerraformFormatter, NativeFormatter:
+	default:
+		return fmt.Errorf("Unknown formatter %q, expected %q or %q",
+			o.Formatter, TerraformFormatter, NativeFormatter)
+	}
+
+	if f := o.Rename.MovedBlocksFile; f != "" {
+		if filepath.Base(f) != f || filepath.Ext(f) != ".tf" {
+			return fmt.Errorf("Expected name of a *.tf file for moved blocks, got %q", f)
+		}
+	}
+
+	if order := o.OrganizeAttributes.Order; len(order) > 0 {
+		names := make(map[string]bool, len(order))
+		for _, name := range order {
+			if names[name] {
+				return fmt.Errorf("Duplicate %q in order of attributes", name)
+			}
+			names[name] = true
+		}
+		if !names[OtherAttributes] || !names[OtherBlocks] {
+			return fmt.Errorf("Expected order of attributes to contain %q and %q",
+				OtherAttributes, OtherBlocks)
+		}
+	}
+
 	return nil
 }
 
@@ -40,3 +40,55 @@ func TestDecodeOptions_success(t *testing.T) {
 		t.Fatalf("options mismatch: %s", diff)
 	}
 }
+
+func TestValidate_formatter(t *testing.T) {
+	opts := &Options{Formatter: NativeFormatter}
+	if err := opts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts = &Options{Formatter: "unknown"}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected unknown formatter to return error")
+	}
+}
+
+func TestValidate_movedBlocksFile(t *testing.T) {
+	opts := &Options{Rename: RenameOptions{MovedBlocksFile: "refactoring.tf"}}
+	if err := opts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts = &Options{Rename: RenameOptions{MovedBlocksFile: "../moved.tf"}}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected path outside of the module to return error")
+	}
+
+	opts = &Options{Rename: RenameOptions{MovedBlocksFile: "moved.txt"}}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected non-Terraform file to return error")
+	}
+}
+
+func TestValidate_attributesOrder(t *testing.T) {
+	opts := &Options{OrganizeAttributes: OrganizeAttributesOptions{
+		Order: []string{"for_each", "count", OtherAttributes, "depends_on", OtherBlocks},
+	}}
+	if err := opts.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts = &Options{OrganizeAttributes: OrganizeAttributesOptions{
+		Order: []string{"count", OtherAttributes},
+	}}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected order without other blocks to return error")
+	}
+
+	opts = &Options{OrganizeAttributes: OrganizeAttributesOptions{
+		Order: []string{"count", OtherAttributes, OtherBlocks, "count"},
+	}}
+	if err := opts.Validate(); err == nil {
+		t.Fatal("expected duplicate name to return error")
+	}
+}
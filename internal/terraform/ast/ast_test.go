@@ -18,8 +18,11 @@ func TestVarsDiags_autoloadedOnly(t *testing.T) {
 				Detail:   "Test description",
 			},
 		},
-		"beta.tfvars":      {},
-		"gama.auto.tfvars": {},
+		"beta.tfvars":            {},
+		"beta.tfvars.json":       {},
+		"gama.auto.tfvars":       {},
+		"delta.auto.tfvars.json": {},
+		"terraform.tfvars.json":  {},
 	})
 	diags := vd.AutoloadedOnly().AsMap()
 	expectedDiags := map[string]hcl.Diagnostics{
@@ -30,7 +33,9 @@ func TestVarsDiags_autoloadedOnly(t *testing.T) {
 				Detail:   "Test description",
 			},
 		},
-		"gama.auto.tfvars": {},
+		"gama.auto.tfvars":       {},
+		"delta.auto.tfvars.json": {},
+		"terraform.tfvars.json":  {},
 	}
 
 	if diff := cmp.Diff(expectedDiags, diags, ctydebug.CmpOptions); diff != "" {
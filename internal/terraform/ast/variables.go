@@ -16,15 +16,21 @@ func NewVarsFilename(name string) (VarsFilename, bool) {
 }
 
 func IsVarsFilename(name string) bool {
-	return strings.HasSuffix(name, ".tfvars") && !isIgnoredFile(name)
+	return (strings.HasSuffix(name, ".tfvars") ||
+		strings.HasSuffix(name, ".tfvars.json")) &&
+		!isIgnoredFile(name)
 }
 
 func (vf VarsFilename) String() string {
 	return string(vf)
 }
 
+func (vf VarsFilename) IsJSON() bool {
+	return strings.HasSuffix(string(vf), ".json")
+}
+
 func (vf VarsFilename) IsAutoloaded() bool {
-	name := string(vf)
+	name := strings.TrimSuffix(string(vf), ".json")
 	return strings.HasSuffix(name, ".auto.tfvars") || name == "terraform.tfvars"
 }
 
@@ -38,6 +44,14 @@ func VarsFilesFromMap(m map[string]*hcl.File) VarsFiles {
 	return mf
 }
 
+func (vf VarsFiles) AsMap() map[string]*hcl.File {
+	m := make(map[string]*hcl.File, len(vf))
+	for name, file := range vf {
+		m[string(name)] = file
+	}
+	return m
+}
+
 type VarsDiags map[VarsFilename]hcl.Diagnostics
 
 func VarsDiagsFromMap(m map[string]hcl.Diagnostics) VarsDiags {
@@ -2,6 +2,7 @@ package exec
 
 import (
 	"context"
+	"sync"
 	"time"
 )
 
@@ -11,13 +12,49 @@ type ExecutorOpts struct {
 	Timeout     time.Duration
 }
 
+// SharedExecutorOpts holds options which can be replaced
+// (e.g. on configuration change) while they're read by
+// running operations
+type SharedExecutorOpts struct {
+	mu   *sync.RWMutex
+	opts ExecutorOpts
+}
+
+func NewSharedExecutorOpts(opts *ExecutorOpts) *SharedExecutorOpts {
+	return &SharedExecutorOpts{
+		mu:   &sync.RWMutex{},
+		opts: *opts,
+	}
+}
+
+// Get returns a snapshot of the current options
+func (s *SharedExecutorOpts) Get() *ExecutorOpts {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	opts := s.opts
+	return &opts
+}
+
+func (s *SharedExecutorOpts) Set(opts *ExecutorOpts) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts = *opts
+}
+
 var ctxExecOpts = ctxKey("executor opts")
 
 func ExecutorOptsFromContext(ctx context.Context) (*ExecutorOpts, bool) {
-	opts, ok := ctx.Value(ctxExecOpts).(*ExecutorOpts)
-	return opts, ok
+	opts, ok := ctx.Value(ctxExecOpts).(*SharedExecutorOpts)
+	if !ok {
+		return nil, false
+	}
+	return opts.Get(), true
 }
 
 func WithExecutorOpts(ctx context.Context, opts *ExecutorOpts) context.Context {
+	return WithSharedExecutorOpts(ctx, NewSharedExecutorOpts(opts))
+}
+
+func WithSharedExecutorOpts(ctx context.Context, opts *SharedExecutorOpts) context.Context {
 	return context.WithValue(ctx, ctxExecOpts, opts)
 }
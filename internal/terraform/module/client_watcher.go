@@ -0,0 +1,194 @@
+package module
+
+import (
+	"log"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/pathcmp"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/datadir"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+)
+
+// clientWatcher does not watch the filesystem itself and instead
+// relies on the client reporting changes of files matching
+// ClientWatcherPatterns (via workspace/didChangeWatchedFiles).
+// This scales better than fsnotify in large trees and works
+// with network filesystems and in containers.
+type clientWatcher struct {
+	fs        filesystem.Filesystem
+	modMgr    ModuleManager
+	modules   []*watchedModule
+	modulesMu *sync.RWMutex
+	logger    *log.Logger
+}
+
+// ClientWatcherPatterns represents glob patterns of files
+// which the client is expected to watch on behalf of clientWatcher
+var ClientWatcherPatterns = []string{
+	"**/*.tf",
+	"**/*.tfvars",
+	"**/*.tfvars.json",
+	"**/.terraform.lock.hcl",
+	"**/.terraform/modules/modules.json",
+}
+
+func NewClientWatcher(fs filesystem.Filesystem, modMgr ModuleManager) (Watcher, error) {
+	return &clientWatcher{
+		fs:        fs,
+		modMgr:    modMgr,
+		modules:   make([]*watchedModule, 0),
+		modulesMu: &sync.RWMutex{},
+		logger:    defaultLogger,
+	}, nil
+}
+
+func (w *clientWatcher) SetLogger(logger *log.Logger) {
+	w.logger = logger
+}
+
+func (w *clientWatcher) Start() error {
+	return nil
+}
+
+func (w *clientWatcher) Stop() error {
+	return nil
+}
+
+func (w *clientWatcher) IsModuleWatched(modPath string) bool {
+	modPath = filepath.Clean(modPath)
+
+	w.modulesMu.RLock()
+	defer w.modulesMu.RUnlock()
+
+	for _, m := range w.modules {
+		if pathcmp.PathEquals(m.Path, modPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *clientWatcher) AddModule(modPath string) error {
+	modPath = filepath.Clean(modPath)
+
+	w.logger.Printf("adding module for watching by client: %s", modPath)
+
+	w.modulesMu.Lock()
+	defer w.modulesMu.Unlock()
+
+	w.modules = append(w.modules, &watchedModule{
+		Path:      modPath,
+		Watched:   make([]string, 0),
+		Watchable: datadir.WatchableModulePaths(modPath),
+	})
+
+	return nil
+}
+
+func (w *clientWatcher) RemoveModule(modPath string) error {
+	modPath = filepath.Clean(modPath)
+
+	w.logger.Printf("removing module from watching by client: %s", modPath)
+
+	w.modulesMu.Lock()
+	defer w.modulesMu.Unlock()
+
+	for i, mod := range w.modules {
+		if pathcmp.PathEquals(mod.Path, modPath) {
+			w.modules = append(w.modules[:i], w.modules[i+1:]...)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// ProcessFileChange enqueues module operations relevant
+// for the changed file, same as the fsnotify based watcher
+// does for the data directory. Changes of configuration files
+// of known modules (e.g. from git checkout) also cause the
+// module to be parsed again.
+func (w *clientWatcher) ProcessFileChange(path string, changeType FileChangeType) error {
+	path = filepath.Clean(path)
+
+	w.modulesMu.RLock()
+	modules := make([]*watchedModule, len(w.modules))
+	copy(modules, w.modules)
+	w.modulesMu.RUnlock()
+
+	for _, mod := range modules {
+		if pathcmp.PathEquals(mod.Path, path) && changeType == FileDeleted {
+			// Whole module being removed
+			return w.RemoveModule(mod.Path)
+		}
+
+		if containsPath(mod.Watchable.ModuleManifests, path) {
+			if changeType == FileDeleted {
+				return nil
+			}
+			return w.modMgr.EnqueueModuleOp(mod.Path, op.OpTypeParseModuleManifest,
+				decodeCalledModulesFunc(w.modMgr, w, mod.Path))
+		}
+
+		if containsPath(mod.Watchable.PluginLockFiles, path) {
+			if changeType == FileDeleted {
+				return nil
+			}
+			err := w.modMgr.EnqueueModuleOp(mod.Path, op.OpTypeObtainSchema, nil)
+			if err != nil {
+				return err
+			}
+			return w.modMgr.EnqueueModuleOp(mod.Path, op.OpTypeGetTerraformVersion, nil)
+		}
+	}
+
+	dir, filename := filepath.Split(path)
+	if !ast.IsModuleFilename(filename) && !ast.IsVarsFilename(filename) {
+		return nil
+	}
+
+	mod, err := w.modMgr.ModuleByPath(filepath.Clean(dir))
+	if err != nil {
+		if IsModuleNotFound(err) {
+			// modules not indexed yet are left to the walker
+			return nil
+		}
+		return err
+	}
+
+	return w.parseModule(mod.Path)
+}
+
+// parseModule enqueues operations to parse the module again,
+// each one after the previous one finished, so that the handler
+// of reported changes doesn't block until the module is parsed.
+// Changes of multiple files of the same module are batched as
+// the module loader ignores operations which are already queued.
+func (w *clientWatcher) parseModule(modPath string) error {
+	w.logger.Printf("parsing module after change reported by client: %s", modPath)
+
+	return w.enqueueModuleOps(modPath, []op.OpType{
+		op.OpTypeParseModuleConfiguration,
+		op.OpTypeParseVariables,
+		op.OpTypeLoadModuleMetadata,
+		op.OpTypeDecodeReferenceTargets,
+		op.OpTypeDecodeReferenceOrigins,
+	})
+}
+
+func (w *clientWatcher) enqueueModuleOps(modPath string, opTypes []op.OpType) error {
+	if len(opTypes) == 0 {
+		return nil
+	}
+
+	return w.modMgr.EnqueueModuleOp(modPath, opTypes[0], func(opErr error) {
+		err := w.enqueueModuleOps(modPath, opTypes[1:])
+		if err != nil {
+			w.logger.Printf("failed to enqueue operations for %s: %s", modPath, err)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package module
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-ls/internal/filesystem"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/exec"
+	op "github.com/hashicorp/terraform-ls/internal/terraform/module/operation"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestClientWatcher_configurationChange(t *testing.T) {
+	fs := filesystem.NewFilesystem()
+
+	modPath := filepath.Join(t.TempDir(), "module")
+	err := os.Mkdir(modPath, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mmm := NewModuleManagerMock(&ModuleManagerMockInput{
+		Logger: testLogger(),
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{},
+		},
+	})
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modMgr := mmm(context.Background(), fs, ss.Modules, ss.ProviderSchemas)
+
+	w, err := NewClientWatcher(fs, modMgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetLogger(testLogger())
+
+	_, err = modMgr.AddModule(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.AddModule(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsModuleWatched(modPath) {
+		t.Fatalf("expected %q to be watched", modPath)
+	}
+
+	mainPath := filepath.Join(modPath, "main.tf")
+	err = ioutil.WriteFile(mainPath, []byte(`variable "name" {}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fcw := w.(FileChangeWatcher)
+	err = fcw.ProcessFileChange(mainPath, FileCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// operations after parsing are enqueued without waiting
+	mod := waitForReferenceOrigins(t, ss.Modules, modPath)
+	if _, ok := mod.ParsedModuleFiles[ast.ModFilename("main.tf")]; !ok {
+		t.Fatalf("expected main.tf to be parsed, given: %#v", mod.ParsedModuleFiles)
+	}
+	if _, ok := mod.Meta.Variables["name"]; !ok {
+		t.Fatalf("expected variable to be loaded, given: %#v", mod.Meta.Variables)
+	}
+
+	err = fcw.ProcessFileChange(modPath, FileDeleted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.IsModuleWatched(modPath) {
+		t.Fatalf("expected %q to be no longer watched", modPath)
+	}
+}
+
+func TestClientWatcher_jsonVarsFileChange(t *testing.T) {
+	fs := filesystem.NewFilesystem()
+
+	modPath := filepath.Join(t.TempDir(), "module")
+	err := os.Mkdir(modPath, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(modPath, "main.tf"), []byte(`variable "name" {}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mmm := NewModuleManagerMock(&ModuleManagerMockInput{
+		Logger: testLogger(),
+		TerraformCalls: &exec.TerraformMockCalls{
+			PerWorkDir: map[string][]*mock.Call{},
+		},
+	})
+	ss, err := state.NewStateStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modMgr := mmm(context.Background(), fs, ss.Modules, ss.ProviderSchemas)
+
+	w, err := NewClientWatcher(fs, modMgr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.SetLogger(testLogger())
+
+	_, err = modMgr.AddModule(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.AddModule(modPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	varsPath := filepath.Join(modPath, "terraform.tfvars.json")
+	err = ioutil.WriteFile(varsPath, []byte(`{"name": "terraform"}
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = w.(FileChangeWatcher).ProcessFileChange(varsPath, FileCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mod := waitForReferenceOrigins(t, ss.Modules, modPath)
+	if _, ok := mod.ParsedVarsFiles[ast.VarsFilename("terraform.tfvars.json")]; !ok {
+		t.Fatalf("expected terraform.tfvars.json to be parsed, given: %#v", mod.ParsedVarsFiles)
+	}
+}
+
+func waitForReferenceOrigins(t *testing.T, ms *state.ModuleStore, modPath string) *state.Module {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mod, err := ms.ModuleByPath(modPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mod.RefOriginsState == op.OpStateLoaded {
+			return mod
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for reference origins of %q", modPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -60,3 +60,18 @@ type Watcher interface {
 	RemoveModule(string) error
 	IsModuleWatched(string) bool
 }
+
+// FileChangeWatcher represents a watcher which is driven by
+// file changes reported from outside, such as by the client
+type FileChangeWatcher interface {
+	Watcher
+	ProcessFileChange(path string, changeType FileChangeType) error
+}
+
+type FileChangeType int
+
+const (
+	FileCreated FileChangeType = iota + 1
+	FileChanged
+	FileDeleted
+)
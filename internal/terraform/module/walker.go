@@ -48,7 +48,8 @@ type Walker struct {
 	cancelFunc context.CancelFunc
 	doneCh     <-chan struct{}
 
-	excludeModulePaths map[string]bool
+	excludeModulePaths   map[string]bool
+	excludeModulePathsMu *sync.RWMutex
 }
 
 // queueCap represents channel buffer size
@@ -66,6 +67,8 @@ func NewWalker(fs filesystem.Filesystem, modMgr ModuleManager) *Walker {
 		queueMu:   &sync.Mutex{},
 		pushChan:  make(chan struct{}, queueCap),
 		doneCh:    make(chan struct{}, 0),
+
+		excludeModulePathsMu: &sync.RWMutex{},
 	}
 }
 
@@ -77,11 +80,23 @@ func (w *Walker) SetWatcher(watcher Watcher) {
 	w.watcher = watcher
 }
 
+// SetExcludeModulePaths sets paths to exclude from walking,
+// which may change while walking, e.g. on configuration change
 func (w *Walker) SetExcludeModulePaths(excludeModulePaths []string) {
-	w.excludeModulePaths = make(map[string]bool)
+	paths := make(map[string]bool)
 	for _, path := range excludeModulePaths {
-		w.excludeModulePaths[path] = true
+		paths[path] = true
 	}
+
+	w.excludeModulePathsMu.Lock()
+	defer w.excludeModulePathsMu.Unlock()
+	w.excludeModulePaths = paths
+}
+
+func (w *Walker) isExcluded(dir string) bool {
+	w.excludeModulePathsMu.RLock()
+	defer w.excludeModulePathsMu.RUnlock()
+	return w.excludeModulePaths[dir]
 }
 
 func (w *Walker) Stop() {
@@ -221,7 +236,7 @@ func (w *Walker) walk(ctx context.Context, rootPath string) error {
 			return err
 		}
 
-		if _, ok := w.excludeModulePaths[dir]; ok {
+		if w.isExcluded(dir) {
 			return filepath.SkipDir
 		}
 
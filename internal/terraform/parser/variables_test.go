@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/spf13/afero"
+)
+
+func TestParseVariableFiles(t *testing.T) {
+	testCases := []struct {
+		dirName           string
+		expectedFileNames map[string]struct{}
+		expectedDiags     map[string]hcl.Diagnostics
+	}{
+		{
+			"valid-vars-files",
+			map[string]struct{}{
+				"prod.auto.tfvars":      {},
+				"terraform.tfvars":      {},
+				"terraform.tfvars.json": {},
+			},
+			map[string]hcl.Diagnostics{
+				"prod.auto.tfvars":      nil,
+				"terraform.tfvars":      nil,
+				"terraform.tfvars.json": nil,
+			},
+		},
+		{
+			"invalid-vars-files",
+			map[string]struct{}{
+				"terraform.tfvars.json": {},
+			},
+			map[string]hcl.Diagnostics{
+				"terraform.tfvars.json": {
+					{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid JSON keyword",
+						Detail:   `"name" is not a valid JSON keyword.`,
+						Subject: &hcl.Range{
+							Filename: "terraform.tfvars.json",
+							Start:    hcl.InitialPos,
+							End:      hcl.Pos{Line: 1, Column: 5, Byte: 4},
+						},
+					},
+					{
+						Severity: hcl.DiagError,
+						Summary:  "Root value must be object",
+						Detail:   "The root value in a JSON-based configuration must be either a JSON object or a JSON array of objects.",
+						Subject: &hcl.Range{
+							Filename: "terraform.tfvars.json",
+							Start:    hcl.InitialPos,
+							End:      hcl.Pos{Line: 1, Column: 5, Byte: 4},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	fs := afero.NewIOFS(afero.NewOsFs())
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("%d-%s", i, tc.dirName), func(t *testing.T) {
+			modPath := filepath.Join("testdata", tc.dirName)
+
+			files, diags, err := ParseVariableFiles(fs, modPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fileNames := varsMapKeys(files)
+			if diff := cmp.Diff(tc.expectedFileNames, fileNames); diff != "" {
+				t.Fatalf("unexpected file names: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.expectedDiags, diags.AsMap()); diff != "" {
+				t.Fatalf("unexpected diagnostics: %s", diff)
+			}
+		})
+	}
+}
+
+func varsMapKeys(vf ast.VarsFiles) map[string]struct{} {
+	m := make(map[string]struct{}, len(vf))
+	for name := range vf {
+		m[name.String()] = struct{}{}
+	}
+	return m
+}
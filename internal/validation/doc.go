@@ -0,0 +1,11 @@
+/*
+Package validation provides validation of Terraform configuration
+beyond what is reported when parsing it, such as checking resources
+against provider schemas, references against declared variables
+or use of syntax deprecated since Terraform 0.12.
+
+Each problem found is identified by a Code, which is published
+as part of the diagnostic, such that a fix of the problem
+can be offered to the user later.
+*/
+package validation
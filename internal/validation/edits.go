@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// insertIntoBlock returns range and text of an edit which inserts
+// given lines at the end of the block body, indented one level deeper
+// than the block itself
+func insertIntoBlock(src []byte, block *hclsyntax.Block, lines []string) (hcl.Range, string) {
+	indent := lineIndent(src, block.TypeRange.Start.Byte)
+	closeBrace := block.CloseBraceRange
+
+	text := ""
+	for _, line := range lines {
+		text += indent + "  " + line + "\n"
+	}
+
+	braceLineStart := lineStart(src, closeBrace.Start.Byte)
+	if strings.TrimSpace(string(src[braceLineStart:closeBrace.Start.Byte])) == "" {
+		pos := posAtByte(src, braceLineStart)
+		return hcl.Range{
+			Filename: closeBrace.Filename,
+			Start:    pos,
+			End:      pos,
+		}, text
+	}
+
+	// closing brace shares the line with other content, e.g. "{}",
+	// so whitespace before it is replaced
+	startByte := closeBrace.Start.Byte
+	for startByte > braceLineStart && (src[startByte-1] == ' ' || src[startByte-1] == '\t') {
+		startByte--
+	}
+	return hcl.Range{
+		Filename: closeBrace.Filename,
+		Start:    posAtByte(src, startByte),
+		End:      closeBrace.Start,
+	}, "\n" + text + indent
+}
+
+// appendToFile returns range and text of an edit which appends
+// text to the file, separated from existing content by an empty line
+func appendToFile(src []byte, filename, text string) (hcl.Range, string) {
+	pos := posAtByte(src, len(src))
+	rng := hcl.Range{
+		Filename: filename,
+		Start:    pos,
+		End:      pos,
+	}
+
+	content := string(src)
+	switch {
+	case strings.TrimSpace(content) == "":
+		return rng, text
+	case strings.HasSuffix(content, "\n"):
+		return rng, "\n" + text
+	}
+	return rng, "\n\n" + text
+}
+
+// removalRange returns range which covers whole lines of the given range,
+// including the trailing newline, if nothing else is on those lines.
+// Otherwise the given range is returned.
+func removalRange(src []byte, rng hcl.Range) hcl.Range {
+	startByte := lineStart(src, rng.Start.Byte)
+	if strings.TrimSpace(string(src[startByte:rng.Start.Byte])) != "" {
+		return rng
+	}
+
+	endByte := rng.End.Byte
+	for endByte < len(src) && src[endByte] != '\n' {
+		endByte++
+	}
+	if strings.TrimSpace(string(src[rng.End.Byte:endByte])) != "" {
+		return rng
+	}
+	if endByte < len(src) {
+		endByte++
+	}
+
+	return hcl.Range{
+		Filename: rng.Filename,
+		Start:    posAtByte(src, startByte),
+		End:      posAtByte(src, endByte),
+	}
+}
+
+func lineStart(src []byte, offset int) int {
+	for offset > 0 && src[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+func lineIndent(src []byte, offset int) string {
+	start := lineStart(src, offset)
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[start:end])
+}
+
+func posAtByte(src []byte, offset int) hcl.Pos {
+	pos := hcl.InitialPos
+	start := 0
+	for i := 0; i < offset; i++ {
+		if src[i] == '\n' {
+			pos.Line++
+			start = i + 1
+		}
+	}
+	pos.Column = utf8.RuneCount(src[start:offset]) + 1
+	pos.Byte = offset
+	return pos
+}
@@ -0,0 +1,220 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Fix represents changes which resolve a problem
+type Fix struct {
+	Title string
+	Edits refactor.Edits
+
+	// NewFiles are absolute paths of files which need
+	// to be created before the edits are applied
+	NewFiles []string
+}
+
+// FixFunc returns fixes of the problem found in the module
+type FixFunc func(mod module.Module, p Problem) []Fix
+
+// FixFuncs maps codes of problems to functions providing their fixes
+type FixFuncs map[Code]FixFunc
+
+// QuickFixes are fixes offered for problems found by Validate
+var QuickFixes = FixFuncs{
+	MissingRequiredAttribute: addRequiredAttribute,
+	UnknownAttribute:         removeAttribute,
+	UndeclaredVariable:       declareVariable,
+	MissingRequiredProvider:  addRequiredProvider,
+
+	InterpolationOnlyExpression: upgradeSyntax,
+	QuotedTypeConstraint:        upgradeSyntax,
+	QuotedReference:             upgradeSyntax,
+	DeprecatedFunction:          upgradeSyntax,
+}
+
+// Fixes returns fixes of the problem, if any are available
+func (ff FixFuncs) Fixes(mod module.Module, p Problem) []Fix {
+	fn, ok := ff[p.Code]
+	if !ok {
+		return []Fix{}
+	}
+	return fn(mod, p)
+}
+
+const variablesFilename = "variables.tf"
+
+func addRequiredAttribute(mod module.Module, p Problem) []Fix {
+	src, ok := fileBytes(mod, p.Filename)
+	if !ok || p.block == nil {
+		return []Fix{}
+	}
+
+	line := fmt.Sprintf("%s = %s", p.Name, placeholderValue(p.attrSchema))
+	rng, text := insertIntoBlock(src, p.block, []string{line})
+
+	edits := make(refactor.Edits, 0)
+	edits.Add(filepath.Join(mod.Path, p.Filename), rng, text)
+
+	return []Fix{
+		{
+			Title: fmt.Sprintf("Add required attribute %q", p.Name),
+			Edits: edits,
+		},
+	}
+}
+
+func removeAttribute(mod module.Module, p Problem) []Fix {
+	src, ok := fileBytes(mod, p.Filename)
+	if !ok || p.block == nil {
+		return []Fix{}
+	}
+	attr, ok := p.block.Body.Attributes[p.Name]
+	if !ok {
+		return []Fix{}
+	}
+
+	edits := make(refactor.Edits, 0)
+	edits.Add(filepath.Join(mod.Path, p.Filename), removalRange(src, attr.SrcRange), "")
+
+	return []Fix{
+		{
+			Title: fmt.Sprintf("Remove attribute %q", p.Name),
+			Edits: edits,
+		},
+	}
+}
+
+func declareVariable(mod module.Module, p Problem) []Fix {
+	path := filepath.Join(mod.Path, variablesFilename)
+	decl := fmt.Sprintf("variable %q {\n}\n", p.Name)
+
+	fix := Fix{
+		Title: fmt.Sprintf("Declare variable %q in %s", p.Name, variablesFilename),
+		Edits: make(refactor.Edits, 0),
+	}
+
+	src, ok := fileBytes(mod, variablesFilename)
+	if !ok {
+		fix.NewFiles = []string{path}
+		fix.Edits.Add(path, hcl.Range{
+			Filename: variablesFilename,
+			Start:    hcl.InitialPos,
+			End:      hcl.InitialPos,
+		}, decl)
+		return []Fix{fix}
+	}
+
+	rng, text := appendToFile(src, variablesFilename, decl)
+	fix.Edits.Add(path, rng, text)
+
+	return []Fix{fix}
+}
+
+func addRequiredProvider(mod module.Module, p Problem) []Fix {
+	entry := []string{
+		fmt.Sprintf("%s = {", p.Name),
+		fmt.Sprintf("  source = %q", defaultProviderSource(p.Name)),
+		"}",
+	}
+
+	fix := Fix{
+		Title: fmt.Sprintf("Add %q to required_providers", p.Name),
+		Edits: make(refactor.Edits, 0),
+	}
+
+	// prefer adding to any existing declaration
+	var tfBlock *syntaxFileBlock
+	for _, f := range syntaxFiles(mod.ParsedModuleFiles) {
+		for _, block := range f.Body.Blocks {
+			if block.Type != "terraform" {
+				continue
+			}
+			for _, nestedBlock := range block.Body.Blocks {
+				if nestedBlock.Type == "required_providers" {
+					rng, text := insertIntoBlock(f.Bytes, nestedBlock, entry)
+					fix.Edits.Add(filepath.Join(mod.Path, f.Name), rng, text)
+					return []Fix{fix}
+				}
+			}
+			if tfBlock == nil {
+				tfBlock = &syntaxFileBlock{file: f, block: block}
+			}
+		}
+	}
+
+	rpBlock := []string{"required_providers {"}
+	for _, line := range entry {
+		rpBlock = append(rpBlock, "  "+line)
+	}
+	rpBlock = append(rpBlock, "}")
+
+	if tfBlock != nil {
+		rng, text := insertIntoBlock(tfBlock.file.Bytes, tfBlock.block, rpBlock)
+		fix.Edits.Add(filepath.Join(mod.Path, tfBlock.file.Name), rng, text)
+		return []Fix{fix}
+	}
+
+	text := "terraform {\n"
+	for _, line := range rpBlock {
+		text += "  " + line + "\n"
+	}
+	text += "}\n\n"
+
+	fix.Edits.Add(filepath.Join(mod.Path, p.Filename), hcl.Range{
+		Filename: p.Filename,
+		Start:    hcl.InitialPos,
+		End:      hcl.InitialPos,
+	}, text)
+
+	return []Fix{fix}
+}
+
+type syntaxFileBlock struct {
+	file  syntaxFile
+	block *hclsyntax.Block
+}
+
+func fileBytes(mod module.Module, filename string) ([]byte, bool) {
+	f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]
+	if !ok {
+		return nil, false
+	}
+	return f.Bytes, true
+}
+
+// placeholderValue returns an empty value of the type
+// expected by the attribute, or null if it is not known
+func placeholderValue(attrSchema *schema.AttributeSchema) string {
+	if attrSchema == nil {
+		return "null"
+	}
+	for _, expr := range attrSchema.Expr {
+		lt, ok := expr.(schema.LiteralTypeExpr)
+		if !ok {
+			continue
+		}
+		switch {
+		case lt.Type == cty.String:
+			return `""`
+		case lt.Type == cty.Number:
+			return "0"
+		case lt.Type == cty.Bool:
+			return "false"
+		case lt.Type.IsListType(), lt.Type.IsSetType(), lt.Type.IsTupleType():
+			return "[]"
+		case lt.Type.IsMapType(), lt.Type.IsObjectType():
+			return "{}"
+		}
+	}
+	return "null"
+}
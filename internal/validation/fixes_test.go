@@ -0,0 +1,256 @@
+package validation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQuickFixes(t *testing.T) {
+	testCases := []struct {
+		name             string
+		files            map[string]string
+		code             Code
+		expectedTitle    string
+		expectedNewFiles []string
+		expectedFiles    map[string]string
+	}{
+		{
+			"missing required attribute",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			},
+			MissingRequiredAttribute,
+			`Add required attribute "instance_type"`,
+			nil,
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami = "ami-123"
+  instance_type = ""
+}
+`,
+			},
+		},
+		{
+			"missing required attribute in empty block",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" { instance_type = "t2.micro" }
+`,
+			},
+			MissingRequiredAttribute,
+			`Add required attribute "ami"`,
+			nil,
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" { instance_type = "t2.micro"
+  ami = ""
+}
+`,
+			},
+		},
+		{
+			"unknown attribute",
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+  type          = "t2.micro"
+}
+`,
+			},
+			UnknownAttribute,
+			`Remove attribute "type"`,
+			nil,
+			map[string]string{
+				"main.tf": `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t2.micro"
+}
+`,
+			},
+		},
+		{
+			"undeclared variable with existing variables.tf",
+			map[string]string{
+				"main.tf": `output "region" {
+  value = var.region
+}
+`,
+				"variables.tf": `variable "zone" {
+}`,
+			},
+			UndeclaredVariable,
+			`Declare variable "region" in variables.tf`,
+			nil,
+			map[string]string{
+				"variables.tf": `variable "zone" {
+}
+
+variable "region" {
+}
+`,
+			},
+		},
+		{
+			"undeclared variable without variables.tf",
+			map[string]string{
+				"main.tf": `output "region" {
+  value = var.region
+}
+`,
+			},
+			UndeclaredVariable,
+			`Declare variable "region" in variables.tf`,
+			[]string{filepath.FromSlash("/test/variables.tf")},
+			map[string]string{
+				"variables.tf": `variable "region" {
+}
+`,
+			},
+		},
+		{
+			"missing required provider with existing required_providers",
+			map[string]string{
+				"main.tf": `resource "aws_eip" "ip" {
+}
+`,
+				"versions.tf": `terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+  }
+}
+`,
+			},
+			MissingRequiredProvider,
+			`Add "aws" to required_providers`,
+			nil,
+			map[string]string{
+				"versions.tf": `terraform {
+  required_providers {
+    google = {
+      source = "hashicorp/google"
+    }
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+`,
+			},
+		},
+		{
+			"missing required provider with terraform block",
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = ">= 1.0"
+}
+
+resource "aws_eip" "ip" {
+}
+`,
+			},
+			MissingRequiredProvider,
+			`Add "aws" to required_providers`,
+			nil,
+			map[string]string{
+				"main.tf": `terraform {
+  required_version = ">= 1.0"
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+resource "aws_eip" "ip" {
+}
+`,
+			},
+		},
+		{
+			"interpolation-only expression",
+			map[string]string{
+				"main.tf": `variable "ami" {
+}
+
+resource "aws_instance" "web" {
+  ami           = "${var.ami}"
+  instance_type = "t2.micro"
+}
+`,
+			},
+			InterpolationOnlyExpression,
+			"Remove interpolation sequence",
+			nil,
+			map[string]string{
+				"main.tf": `variable "ami" {
+}
+
+resource "aws_instance" "web" {
+  ami           = var.ami
+  instance_type = "t2.micro"
+}
+`,
+			},
+		},
+		{
+			"missing required provider without terraform block",
+			map[string]string{
+				"main.tf": `resource "aws_eip" "ip" {
+}
+`,
+			},
+			MissingRequiredProvider,
+			`Add "aws" to required_providers`,
+			nil,
+			map[string]string{
+				"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+resource "aws_eip" "ip" {
+}
+`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mod := testModule(t, tc.files)
+
+			var fixes []Fix
+			for _, p := range Validate(mod, testSchema) {
+				if p.Code == tc.code {
+					fixes = QuickFixes.Fixes(mod, p)
+					break
+				}
+			}
+			if len(fixes) != 1 {
+				t.Fatalf("expected exactly 1 fix, given: %#v", fixes)
+			}
+
+			fix := fixes[0]
+			if fix.Title != tc.expectedTitle {
+				t.Fatalf("expected title %q, given: %q", tc.expectedTitle, fix.Title)
+			}
+			if diff := cmp.Diff(tc.expectedNewFiles, fix.NewFiles); diff != "" {
+				t.Fatalf("unexpected new files: %s", diff)
+			}
+
+			files := applyEdits(t, mod, fix.Edits)
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+		})
+	}
+}
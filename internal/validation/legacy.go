@@ -0,0 +1,337 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/refactor"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// legacyRewrite represents an expression written in syntax
+// of Terraform 0.11 and the replacement of it
+type legacyRewrite struct {
+	code   Code
+	name   string
+	rng    hcl.Range
+	detail string
+
+	// render returns the replacement, using text to obtain source
+	// of nested expressions with any nested rewrites applied
+	render func(text func(hcl.Range) string) string
+}
+
+// legacyProblems returns problems of syntax which is deprecated
+// since Terraform 0.12, such as interpolation-only expressions,
+// with replacements which upgrade the syntax
+func legacyProblems(files []syntaxFile) []Problem {
+	problems := make([]Problem, 0)
+
+	for _, f := range files {
+		rewrites := legacyRewrites(f.Body)
+		sort.SliceStable(rewrites, func(i, j int) bool {
+			if rewrites[i].rng.Start.Byte != rewrites[j].rng.Start.Byte {
+				return rewrites[i].rng.Start.Byte < rewrites[j].rng.Start.Byte
+			}
+			return rewrites[i].rng.End.Byte > rewrites[j].rng.End.Byte
+		})
+
+		src := f.Bytes
+		var text func(rng hcl.Range) string
+		text = func(rng hcl.Range) string {
+			var b strings.Builder
+			offset := rng.Start.Byte
+			for _, r := range rewrites {
+				if r.rng.Start.Byte < offset || r.rng.End.Byte > rng.End.Byte {
+					continue
+				}
+				b.Write(src[offset:r.rng.Start.Byte])
+				b.WriteString(r.render(text))
+				offset = r.rng.End.Byte
+			}
+			b.Write(src[offset:rng.End.Byte])
+			return b.String()
+		}
+
+		for _, r := range rewrites {
+			rng := r.rng
+			problems = append(problems, Problem{
+				Code:     r.code,
+				Filename: f.Name,
+				Diagnostic: &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  legacySummaries[r.code],
+					Detail:   r.detail,
+					Subject:  &rng,
+				},
+				Name:        r.name,
+				replacement: r.render(text),
+			})
+		}
+	}
+
+	return problems
+}
+
+var legacySummaries = map[Code]string{
+	InterpolationOnlyExpression: interpolationOnlyExpressionSummary,
+	QuotedTypeConstraint:        quotedTypeConstraintSummary,
+	QuotedReference:             quotedReferenceSummary,
+	DeprecatedFunction:          deprecatedFunctionSummary,
+}
+
+func legacyRewrites(body *hclsyntax.Body) []legacyRewrite {
+	rewrites := make([]legacyRewrite, 0)
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case "variable":
+			if attr, ok := block.Body.Attributes["type"]; ok {
+				if r, ok := quotedTypeRewrite(attr.Expr); ok {
+					rewrites = append(rewrites, r)
+				}
+			}
+		case "resource", "data", "module", "output":
+			if attr, ok := block.Body.Attributes["depends_on"]; ok {
+				rewrites = append(rewrites, quotedReferenceRewrites(attr.Expr)...)
+			}
+		}
+	}
+
+	// operands of other expressions, which need to be
+	// parenthesized if their unwrapped expression is an operation
+	operands := make(map[hclsyntax.Expression]bool, 0)
+
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		switch expr := node.(type) {
+		case *hclsyntax.BinaryOpExpr:
+			operands[expr.LHS] = true
+			operands[expr.RHS] = true
+		case *hclsyntax.UnaryOpExpr:
+			operands[expr.Val] = true
+		case *hclsyntax.ConditionalExpr:
+			operands[expr.Condition] = true
+			operands[expr.TrueResult] = true
+			operands[expr.FalseResult] = true
+		case *hclsyntax.IndexExpr:
+			operands[expr.Collection] = true
+		case *hclsyntax.RelativeTraversalExpr:
+			operands[expr.Source] = true
+		case *hclsyntax.SplatExpr:
+			operands[expr.Source] = true
+		case *hclsyntax.TemplateWrapExpr:
+			rewrites = append(rewrites, interpolationRewrite(expr, operands[expr]))
+		case *hclsyntax.FunctionCallExpr:
+			if r, ok := functionRewrite(expr); ok {
+				rewrites = append(rewrites, r)
+			}
+		}
+		return nil
+	})
+
+	return rewrites
+}
+
+func interpolationRewrite(expr *hclsyntax.TemplateWrapExpr, isOperand bool) legacyRewrite {
+	needsParens := false
+	switch expr.Wrapped.(type) {
+	case *hclsyntax.BinaryOpExpr, *hclsyntax.UnaryOpExpr, *hclsyntax.ConditionalExpr:
+		needsParens = isOperand
+	}
+
+	return legacyRewrite{
+		code: InterpolationOnlyExpression,
+		rng:  expr.SrcRange,
+		detail: "Terraform 0.11 and earlier required all non-constant expressions " +
+			"to be provided via interpolation syntax, but this pattern is now deprecated. " +
+			`Remove the "${ sequence from the start and the }" sequence from the end ` +
+			"of this expression, leaving just the inner expression.",
+		render: func(text func(hcl.Range) string) string {
+			inner := text(expr.Wrapped.Range())
+			if needsParens {
+				return "(" + inner + ")"
+			}
+			return inner
+		},
+	}
+}
+
+// legacyTypeKeywords maps quoted type constraints
+// to their equivalents in the type constraint syntax
+var legacyTypeKeywords = map[string]string{
+	"string": "string",
+	"list":   "list(string)",
+	"map":    "map(string)",
+}
+
+func quotedTypeRewrite(expr hclsyntax.Expression) (legacyRewrite, bool) {
+	value, ok := stringLiteral(expr)
+	if !ok {
+		return legacyRewrite{}, false
+	}
+	keyword, ok := legacyTypeKeywords[value]
+	if !ok {
+		return legacyRewrite{}, false
+	}
+
+	return legacyRewrite{
+		code: QuotedTypeConstraint,
+		name: value,
+		rng:  expr.Range(),
+		detail: fmt.Sprintf("Terraform 0.11 and earlier required type constraints "+
+			"to be given in quotes, but that form is now deprecated. "+
+			"Replace the quoted %q with %s.", value, keyword),
+		render: func(func(hcl.Range) string) string {
+			return keyword
+		},
+	}, true
+}
+
+func quotedReferenceRewrites(expr hclsyntax.Expression) []legacyRewrite {
+	rewrites := make([]legacyRewrite, 0)
+
+	tuple, ok := expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return rewrites
+	}
+	for _, elem := range tuple.Exprs {
+		value, ok := stringLiteral(elem)
+		if !ok {
+			continue
+		}
+		_, diags := hclsyntax.ParseTraversalAbs([]byte(value), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			continue
+		}
+
+		rewrites = append(rewrites, legacyRewrite{
+			code: QuotedReference,
+			name: value,
+			rng:  elem.Range(),
+			detail: fmt.Sprintf("In this context, references are expected literally "+
+				"rather than in quotes. Remove the quotes surrounding %s.", value),
+			render: func(func(hcl.Range) string) string {
+				return value
+			},
+		})
+	}
+
+	return rewrites
+}
+
+func functionRewrite(expr *hclsyntax.FunctionCallExpr) (legacyRewrite, bool) {
+	if expr.ExpandFinal {
+		return legacyRewrite{}, false
+	}
+
+	switch expr.Name {
+	case "list":
+		return legacyRewrite{
+			code:   DeprecatedFunction,
+			name:   expr.Name,
+			rng:    expr.Range(),
+			detail: "The list function is deprecated. Use tuple syntax ([ ... ]) instead.",
+			render: func(text func(hcl.Range) string) string {
+				elems := make([]string, 0, len(expr.Args))
+				for _, arg := range expr.Args {
+					elems = append(elems, text(arg.Range()))
+				}
+				return "[" + strings.Join(elems, ", ") + "]"
+			},
+		}, true
+	case "map":
+		if len(expr.Args)%2 != 0 {
+			return legacyRewrite{}, false
+		}
+		return legacyRewrite{
+			code:   DeprecatedFunction,
+			name:   expr.Name,
+			rng:    expr.Range(),
+			detail: "The map function is deprecated. Use object syntax ({ ... }) instead.",
+			render: func(text func(hcl.Range) string) string {
+				if len(expr.Args) == 0 {
+					return "{}"
+				}
+				items := make([]string, 0, len(expr.Args)/2)
+				for i := 0; i < len(expr.Args); i += 2 {
+					key := text(expr.Args[i].Range())
+					if _, ok := expr.Args[i].(*hclsyntax.TemplateExpr); !ok {
+						// only literal keys may be written without parentheses
+						key = "(" + key + ")"
+					}
+					items = append(items, fmt.Sprintf("%s = %s", key, text(expr.Args[i+1].Range())))
+				}
+				return "{ " + strings.Join(items, ", ") + " }"
+			},
+		}, true
+	}
+
+	return legacyRewrite{}, false
+}
+
+// stringLiteral returns value of a quoted string without any interpolation
+func stringLiteral(expr hclsyntax.Expression) (string, bool) {
+	tpl, ok := expr.(*hclsyntax.TemplateExpr)
+	if !ok || !tpl.IsStringLiteral() {
+		return "", false
+	}
+	val, diags := tpl.Value(nil)
+	if diags.HasErrors() || val.IsNull() {
+		return "", false
+	}
+	return val.AsString(), true
+}
+
+func upgradeSyntax(mod module.Module, p Problem) []Fix {
+	edits := make(refactor.Edits, 0)
+	edits.Add(filepath.Join(mod.Path, p.Filename), *p.Diagnostic.Subject, p.replacement)
+
+	return []Fix{
+		{
+			Title: legacyFixTitle(p),
+			Edits: edits,
+		},
+	}
+}
+
+func legacyFixTitle(p Problem) string {
+	switch p.Code {
+	case InterpolationOnlyExpression:
+		return "Remove interpolation sequence"
+	case QuotedTypeConstraint:
+		return "Remove quotes from type constraint"
+	case QuotedReference:
+		return "Remove quotes from reference"
+	}
+	return fmt.Sprintf("Replace %s function", p.Name)
+}
+
+// UpgradeLegacySyntax returns a fix replacing all syntax of the module
+// deprecated since Terraform 0.12, or false if there is no such syntax
+func UpgradeLegacySyntax(mod module.Module) (Fix, bool) {
+	problems := legacyProblems(syntaxFiles(mod.ParsedModuleFiles))
+	if len(problems) == 0 {
+		return Fix{}, false
+	}
+
+	edits := make(refactor.Edits, 0)
+	var last *Problem
+	for i, p := range problems {
+		// nested problems are fixed by replacement of the outer ones
+		if last != nil && last.Filename == p.Filename &&
+			p.Diagnostic.Subject.Start.Byte < last.Diagnostic.Subject.End.Byte {
+			continue
+		}
+		edits.Add(filepath.Join(mod.Path, p.Filename), *p.Diagnostic.Subject, p.replacement)
+		last = &problems[i]
+	}
+
+	return Fix{
+		Title: "Upgrade legacy syntax in module",
+		Edits: edits,
+	}, true
+}
@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestValidate_legacySyntax(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"main.tf": `variable "names" {
+  type = "list"
+}
+
+resource "aws_eip" "ip" {
+  tags       = "${map("Name", "ip")}"
+  depends_on = ["aws_instance.web"]
+}
+`,
+	})
+
+	problems := Validate(mod, nil)
+
+	type problem struct {
+		Code         Code
+		Line, Column int
+		Replacement  string
+		Severity     hcl.DiagnosticSeverity
+	}
+	got := make([]problem, 0)
+	for _, p := range problems {
+		if !p.Code.IsDeprecation() {
+			continue
+		}
+		got = append(got, problem{
+			p.Code,
+			p.Diagnostic.Subject.Start.Line,
+			p.Diagnostic.Subject.Start.Column,
+			p.replacement,
+			p.Diagnostic.Severity,
+		})
+	}
+
+	expected := []problem{
+		{QuotedTypeConstraint, 2, 10, "list(string)", hcl.DiagWarning},
+		{InterpolationOnlyExpression, 6, 16, `{ "Name" = "ip" }`, hcl.DiagWarning},
+		{DeprecatedFunction, 6, 19, `{ "Name" = "ip" }`, hcl.DiagWarning},
+		{QuotedReference, 7, 17, "aws_instance.web", hcl.DiagWarning},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("unexpected problems: %s", diff)
+	}
+}
+
+func TestUpgradeLegacySyntax(t *testing.T) {
+	testCases := []struct {
+		name          string
+		files         map[string]string
+		expectedFiles map[string]string
+	}{
+		{
+			"whole module",
+			map[string]string{
+				"main.tf": `variable "zones" {
+  type    = "list"
+  default = ["a", "b"]
+}
+
+variable "name" {
+  type = "string"
+}
+
+resource "aws_instance" "web" {
+  count = "${length(var.zones)}"
+  tags  = "${map("Name", "${var.name}-web", var.name, "true")}"
+  zones = "${list("${var.zones[0]}", "c")}"
+  size  = "${var.name == "" ? 1 : 2}" + 1
+  name  = "web-${var.name}"
+
+  depends_on = ["aws_eip.ip", "module.vpc"]
+}
+`,
+				"outputs.tf": `output "ids" {
+  value = "${aws_instance.web.*.id}"
+}
+`,
+			},
+			map[string]string{
+				"main.tf": `variable "zones" {
+  type    = list(string)
+  default = ["a", "b"]
+}
+
+variable "name" {
+  type = string
+}
+
+resource "aws_instance" "web" {
+  count = length(var.zones)
+  tags  = { "Name" = "${var.name}-web", (var.name) = "true" }
+  zones = [var.zones[0], "c"]
+  size  = (var.name == "" ? 1 : 2) + 1
+  name  = "web-${var.name}"
+
+  depends_on = [aws_eip.ip, module.vpc]
+}
+`,
+				"outputs.tf": `output "ids" {
+  value = aws_instance.web.*.id
+}
+`,
+			},
+		},
+		{
+			"current syntax",
+			map[string]string{
+				"main.tf": `variable "name" {
+  type = string
+}
+
+output "name" {
+  value = "${var.name}-web"
+}
+`,
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mod := testModule(t, tc.files)
+
+			fix, ok := UpgradeLegacySyntax(mod)
+			if tc.expectedFiles == nil {
+				if ok {
+					t.Fatalf("expected no fix, given: %#v", fix)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("expected fix")
+			}
+
+			files := applyEdits(t, mod, fix.Edits)
+			if diff := cmp.Diff(tc.expectedFiles, files); diff != "" {
+				t.Fatalf("unexpected files: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// Terraform 0.13 introduced provider source addresses
+// which are declared in required_providers
+var v0_13 = version.Must(version.NewVersion("0.13.0"))
+
+var terraformBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "terraform"},
+	},
+}
+
+var requiredProvidersBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "required_providers"},
+	},
+}
+
+// providerProblems returns problems of providers used by resources
+// or data sources which are not declared in required_providers.
+// Each provider is reported only once, at its first usage.
+func providerProblems(mod module.Module, files []syntaxFile) []Problem {
+	problems := make([]Problem, 0)
+
+	if mod.TerraformVersion != nil && mod.TerraformVersion.LessThan(v0_13) {
+		return problems
+	}
+
+	declared := requiredProviderNames(mod.ParsedModuleFiles)
+	reported := make(map[string]bool, 0)
+
+	for _, f := range files {
+		for _, block := range f.Body.Blocks {
+			if (block.Type != "resource" && block.Type != "data") || len(block.Labels) == 0 {
+				continue
+			}
+			name := providerLocalName(block)
+			// the built-in provider is never declared
+			if name == "" || name == "terraform" {
+				continue
+			}
+			if _, ok := declared[name]; ok || reported[name] {
+				continue
+			}
+			reported[name] = true
+
+			rng := block.LabelRanges[0]
+			problems = append(problems, Problem{
+				Code:     MissingRequiredProvider,
+				Filename: f.Name,
+				Diagnostic: &hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  missingRequiredProviderSummary,
+					Detail: fmt.Sprintf("Provider %q is not declared in required_providers, "+
+						"so Terraform assumes source %q.", name, defaultProviderSource(name)),
+					Subject: &rng,
+				},
+				Name:  name,
+				block: block,
+			})
+		}
+	}
+
+	return problems
+}
+
+// providerLocalName returns local name of the provider used
+// by the resource or data block, which is either referenced
+// explicitly or implied by the type
+func providerLocalName(block *hclsyntax.Block) string {
+	if attr, ok := block.Body.Attributes["provider"]; ok {
+		if expr, ok := attr.Expr.(*hclsyntax.ScopeTraversalExpr); ok {
+			return expr.Traversal.RootName()
+		}
+		return ""
+	}
+
+	return strings.SplitN(block.Labels[0], "_", 2)[0]
+}
+
+func defaultProviderSource(name string) string {
+	return "hashicorp/" + name
+}
+
+func requiredProviderNames(modFiles ast.ModFiles) map[string]struct{} {
+	names := make(map[string]struct{}, 0)
+	for _, f := range modFiles {
+		content, _, _ := f.Body.PartialContent(terraformBlockSchema)
+		for _, tfBlock := range content.Blocks {
+			tfContent, _, _ := tfBlock.Body.PartialContent(requiredProvidersBlockSchema)
+			for _, rpBlock := range tfContent.Blocks {
+				attrs, _ := rpBlock.Body.JustAttributes()
+				for name := range attrs {
+					names[name] = struct{}{}
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/decoder"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// schemaProblems returns problems of resource and data blocks
+// whose schema is known, i.e. provided by an installed provider
+func schemaProblems(files []syntaxFile, bodySchema *schema.BodySchema) []Problem {
+	problems := make([]Problem, 0)
+
+	for _, f := range files {
+		for _, block := range f.Body.Blocks {
+			if block.Type != "resource" && block.Type != "data" {
+				continue
+			}
+			bSchema, ok := bodySchema.Blocks[block.Type]
+			if !ok {
+				continue
+			}
+			depSchema, _, ok := decoder.NewBlockSchema(bSchema).DependentBodySchema(block.AsHCLBlock())
+			if !ok {
+				continue
+			}
+
+			// meta-arguments (count, provider etc.) come from the core schema
+			attrs := make(map[string]*schema.AttributeSchema, 0)
+			if bSchema.Body != nil {
+				for name, attr := range bSchema.Body.Attributes {
+					attrs[name] = attr
+				}
+			}
+			for name, attr := range depSchema.Attributes {
+				if _, exists := attrs[name]; !exists {
+					attrs[name] = attr
+				}
+			}
+
+			problems = append(problems, bodyProblems(f.Name, block, &schema.BodySchema{
+				Attributes:   attrs,
+				Blocks:       depSchema.Blocks,
+				AnyAttribute: depSchema.AnyAttribute,
+			})...)
+		}
+	}
+
+	return problems
+}
+
+func bodyProblems(filename string, block *hclsyntax.Block, bodySchema *schema.BodySchema) []Problem {
+	problems := make([]Problem, 0)
+
+	names := make([]string, 0, len(bodySchema.Attributes))
+	for name := range bodySchema.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attrSchema := bodySchema.Attributes[name]
+		if !attrSchema.IsRequired {
+			continue
+		}
+		if _, ok := block.Body.Attributes[name]; ok {
+			continue
+		}
+		defRng := block.DefRange()
+		problems = append(problems, Problem{
+			Code:     MissingRequiredAttribute,
+			Filename: filename,
+			Diagnostic: &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  missingRequiredAttributeSummary,
+				Detail:   fmt.Sprintf("The argument %q is required, but no definition was found.", name),
+				Subject:  &defRng,
+			},
+			Name:       name,
+			block:      block,
+			attrSchema: attrSchema,
+		})
+	}
+
+	if bodySchema.AnyAttribute == nil {
+		for _, attr := range sortedAttributes(block.Body.Attributes) {
+			if _, ok := bodySchema.Attributes[attr.Name]; ok {
+				continue
+			}
+			nameRng := attr.NameRange
+			problems = append(problems, Problem{
+				Code:     UnknownAttribute,
+				Filename: filename,
+				Diagnostic: &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  unknownAttributeSummary,
+					Detail:   fmt.Sprintf("An argument named %q is not expected here.", attr.Name),
+					Subject:  &nameRng,
+				},
+				Name:  attr.Name,
+				block: block,
+			})
+		}
+	}
+
+	for _, nestedBlock := range block.Body.Blocks {
+		bSchema, ok := bodySchema.Blocks[nestedBlock.Type]
+		if !ok || bSchema.Body == nil {
+			continue
+		}
+		problems = append(problems, bodyProblems(filename, nestedBlock, bSchema.Body)...)
+	}
+
+	return problems
+}
+
+func sortedAttributes(attrs hclsyntax.Attributes) []*hclsyntax.Attribute {
+	sorted := make([]*hclsyntax.Attribute, 0, len(attrs))
+	for _, attr := range attrs {
+		sorted = append(sorted, attr)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SrcRange.Start.Byte < sorted[j].SrcRange.Start.Byte
+	})
+	return sorted
+}
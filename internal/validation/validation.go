@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+)
+
+// Source is the source of diagnostics which represent
+// problems found by validation
+const Source = "terraform-ls"
+
+// Code identifies the kind of problem
+type Code string
+
+const (
+	MissingRequiredAttribute Code = "missing-required-attribute"
+	UnknownAttribute         Code = "unknown-attribute"
+	UndeclaredVariable       Code = "undeclared-variable"
+	MissingRequiredProvider  Code = "missing-required-provider"
+
+	InterpolationOnlyExpression Code = "interpolation-only-expression"
+	QuotedTypeConstraint        Code = "quoted-type-constraint"
+	QuotedReference             Code = "quoted-reference"
+	DeprecatedFunction          Code = "deprecated-function"
+)
+
+// IsDeprecation reports whether the code identifies
+// syntax which is deprecated, rather than an error
+func (c Code) IsDeprecation() bool {
+	_, ok := legacySummaries[c]
+	return ok
+}
+
+const (
+	missingRequiredAttributeSummary = "Missing required argument"
+	unknownAttributeSummary         = "Unsupported argument"
+	undeclaredVariableSummary       = "Reference to undeclared input variable"
+	missingRequiredProviderSummary  = "Missing required provider"
+
+	interpolationOnlyExpressionSummary = "Interpolation-only expressions are deprecated"
+	quotedTypeConstraintSummary        = "Quoted type constraints are deprecated"
+	quotedReferenceSummary             = "Quoted references are deprecated"
+	deprecatedFunctionSummary          = "Deprecated function"
+)
+
+// Problem represents a problem found in a file of the module
+type Problem struct {
+	Code       Code
+	Filename   string
+	Diagnostic *hcl.Diagnostic
+
+	// Name is the name of the attribute, variable or provider
+	// which the problem relates to
+	Name string
+
+	// block is the block which the problem was found in, if any
+	block *hclsyntax.Block
+	// attrSchema is the schema of the missing attribute, if any
+	attrSchema *schema.AttributeSchema
+	// replacement is the upgraded syntax of a deprecated expression
+	replacement string
+}
+
+// Validate returns problems found in the module, ordered by filename
+// and position. Checks which require schema are skipped if it is nil.
+func Validate(mod module.Module, bodySchema *schema.BodySchema) []Problem {
+	files := syntaxFiles(mod.ParsedModuleFiles)
+
+	problems := make([]Problem, 0)
+	if bodySchema != nil {
+		problems = append(problems, schemaProblems(files, bodySchema)...)
+	}
+	problems = append(problems, variableProblems(mod.ParsedModuleFiles, files)...)
+	problems = append(problems, providerProblems(mod, files)...)
+	problems = append(problems, legacyProblems(files)...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Filename != problems[j].Filename {
+			return problems[i].Filename < problems[j].Filename
+		}
+		return problems[i].Diagnostic.Subject.Start.Byte < problems[j].Diagnostic.Subject.Start.Byte
+	})
+
+	return problems
+}
+
+// ModuleProblems returns problems found in the module,
+// using the schema from finder where available
+func ModuleProblems(mf module.ModuleFinder, mod module.Module) []Problem {
+	bodySchema, err := mf.SchemaForModule(mod.Path)
+	if err != nil {
+		bodySchema = nil
+	}
+	return Validate(mod, bodySchema)
+}
+
+// Diagnostics returns diagnostics of the problems indexed by filename
+func Diagnostics(problems []Problem) map[string]hcl.Diagnostics {
+	diags := make(map[string]hcl.Diagnostics, 0)
+	for _, p := range problems {
+		diags[p.Filename] = append(diags[p.Filename], p.Diagnostic)
+	}
+	return diags
+}
+
+type syntaxFile struct {
+	Name  string
+	Bytes []byte
+	Body  *hclsyntax.Body
+}
+
+// syntaxFiles returns native syntax files sorted by name
+func syntaxFiles(files ast.ModFiles) []syntaxFile {
+	sf := make([]syntaxFile, 0)
+	for name, f := range files {
+		body, ok := f.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		sf = append(sf, syntaxFile{
+			Name:  name.String(),
+			Bytes: f.Bytes,
+			Body:  body,
+		})
+	}
+	sort.SliceStable(sf, func(i, j int) bool {
+		return sf[i].Name < sf[j].Name
+	})
+	return sf
+}
@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl-lang/lang"
+	"github.com/hashicorp/hcl-lang/schema"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/state"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+	"github.com/hashicorp/terraform-ls/internal/terraform/module"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestValidate(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"main.tf": `resource "aws_instance" "web" {
+  ami  = var.ami
+  type = "t2.micro"
+}
+
+resource "aws_eip" "ip" {
+  instance = aws_instance.web.id
+}
+
+data "terraform_remote_state" "vpc" {
+  backend = "local"
+}
+`,
+		"variables.tf": `variable "unused" {}
+`,
+	})
+
+	problems := Validate(mod, testSchema)
+
+	type problem struct {
+		Code    Code
+		File    string
+		Name    string
+		Line    int
+		Summary string
+	}
+	got := make([]problem, len(problems))
+	for i, p := range problems {
+		got[i] = problem{p.Code, p.Filename, p.Name, p.Diagnostic.Subject.Start.Line, p.Diagnostic.Summary}
+	}
+
+	expected := []problem{
+		{MissingRequiredAttribute, "main.tf", "instance_type", 1, "Missing required argument"},
+		{MissingRequiredProvider, "main.tf", "aws", 1, "Missing required provider"},
+		{UndeclaredVariable, "main.tf", "ami", 2, "Reference to undeclared input variable"},
+		{UnknownAttribute, "main.tf", "type", 3, "Unsupported argument"},
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Fatalf("unexpected problems: %s", diff)
+	}
+}
+
+func TestValidate_declaredProvider(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"main.tf": `terraform {
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+    }
+  }
+}
+
+resource "aws_eip" "ip" {
+}
+`,
+	})
+
+	problems := Validate(mod, nil)
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems, given: %#v", problems)
+	}
+}
+
+func TestValidate_legacyTerraform(t *testing.T) {
+	mod := testModule(t, map[string]string{
+		"main.tf": `resource "aws_eip" "ip" {
+}
+`,
+	})
+	mod.TerraformVersion = version.Must(version.NewVersion("0.12.31"))
+
+	problems := Validate(mod, nil)
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems, given: %#v", problems)
+	}
+}
+
+var testSchema = &schema.BodySchema{
+	Blocks: map[string]*schema.BlockSchema{
+		"resource": {
+			Labels: []*schema.LabelSchema{
+				{Name: "type", IsDepKey: true},
+				{Name: "name"},
+			},
+			Body: &schema.BodySchema{
+				Attributes: map[string]*schema.AttributeSchema{
+					"count": {
+						Expr:       schema.LiteralTypeOnly(cty.Number),
+						IsOptional: true,
+					},
+				},
+			},
+			DependentBody: map[schema.SchemaKey]*schema.BodySchema{
+				schema.NewSchemaKey(schema.DependencyKeys{
+					Labels: []schema.LabelDependent{
+						{Index: 0, Value: "aws_instance"},
+					},
+				}): {
+					Attributes: map[string]*schema.AttributeSchema{
+						"ami": {
+							Expr:       schema.LiteralTypeOnly(cty.String),
+							IsRequired: true,
+						},
+						"instance_type": {
+							Expr:       schema.LiteralTypeOnly(cty.String),
+							IsRequired: true,
+						},
+						"tags": {
+							Expr:       schema.LiteralTypeOnly(cty.Map(cty.String)),
+							IsOptional: true,
+						},
+					},
+				},
+			},
+		},
+	},
+}
+
+func testModule(t *testing.T, files map[string]string) module.Module {
+	modFiles := make(ast.ModFiles, 0)
+	for name, src := range files {
+		f, diags := hclsyntax.ParseConfig([]byte(src), name, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags)
+		}
+		modFiles[ast.ModFilename(name)] = f
+	}
+
+	return &state.Module{
+		Path:              filepath.FromSlash("/test"),
+		ParsedModuleFiles: modFiles,
+	}
+}
+
+// applyEdits returns content of the files after edits are applied,
+// indexed by filename relative to the module
+func applyEdits(t *testing.T, mod module.Module, edits map[string][]lang.TextEdit) map[string]string {
+	result := make(map[string]string, 0)
+	for path, fileEdits := range edits {
+		filename, err := filepath.Rel(mod.Path, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var src []byte
+		if f, ok := mod.ParsedModuleFiles[ast.ModFilename(filename)]; ok {
+			src = f.Bytes
+		}
+
+		sorted := make([]lang.TextEdit, len(fileEdits))
+		copy(sorted, fileEdits)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Range.Start.Byte > sorted[j].Range.Start.Byte
+		})
+
+		content := string(src)
+		for _, edit := range sorted {
+			content = content[:edit.Range.Start.Byte] + edit.NewText + content[edit.Range.End.Byte:]
+		}
+		result[filename] = content
+	}
+	return result
+}
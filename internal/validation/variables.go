@@ -0,0 +1,74 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-ls/internal/terraform/ast"
+)
+
+var variableBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "variable",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+// variableProblems returns problems of references to variables
+// which are not declared in any file of the module
+func variableProblems(modFiles ast.ModFiles, files []syntaxFile) []Problem {
+	problems := make([]Problem, 0)
+
+	declared := declaredVariables(modFiles)
+
+	for _, f := range files {
+		filename := f.Name
+		hclsyntax.VisitAll(f.Body, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok || len(expr.Traversal) < 2 || expr.Traversal.RootName() != "var" {
+				return nil
+			}
+			attr, ok := expr.Traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				return nil
+			}
+			if _, ok := declared[attr.Name]; ok {
+				return nil
+			}
+
+			rng := hcl.RangeBetween(expr.Traversal[0].SourceRange(), attr.SrcRange)
+			problems = append(problems, Problem{
+				Code:     UndeclaredVariable,
+				Filename: filename,
+				Diagnostic: &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  undeclaredVariableSummary,
+					Detail: fmt.Sprintf("An input variable with the name %q has not been declared. "+
+						"This variable can be declared with a variable %q {} block.", attr.Name, attr.Name),
+					Subject: &rng,
+				},
+				Name: attr.Name,
+			})
+			return nil
+		})
+	}
+
+	return problems
+}
+
+func declaredVariables(modFiles ast.ModFiles) map[string]struct{} {
+	declared := make(map[string]struct{}, 0)
+	for _, f := range modFiles {
+		content, _, _ := f.Body.PartialContent(variableBlockSchema)
+		for _, block := range content.Blocks {
+			if len(block.Labels) != 1 {
+				continue
+			}
+			declared[block.Labels[0]] = struct{}{}
+		}
+	}
+	return declared
+}